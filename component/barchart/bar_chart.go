@@ -20,11 +20,21 @@ const (
 
 // Bar represents a single bar in the chart.
 // Color is optional; set UseColor to true to override the theme.
+// Segments stack colored parts bottom-up; when set, Value is their sum.
+// Marker draws a reference level (e.g. a rolling average) across the bar; 0 = none.
 type Bar struct {
 	Label    string
 	Value    float64
 	Color    tcell.Color
 	UseColor bool
+	Segments []Segment
+	Marker   float64
+}
+
+// Segment is one stacked part of a bar.
+type Segment struct {
+	Value float64
+	Color tcell.Color
 }
 
 // Theme defines colors and glyphs used by the chart.
@@ -33,11 +43,13 @@ type Theme struct {
 	LabelColor      tcell.Color
 	ValueColor      tcell.Color
 	BarColor        tcell.Color
+	MarkerColor     tcell.Color
 	BackgroundColor tcell.Color // background color for chart area
 	BarGradientFrom [3]int
 	BarGradientTo   [3]int
 	DotChar         rune
 	BarChar         rune
+	MarkerChar      rune
 	DotRowGap       int
 	DotColGap       int
 }
@@ -68,11 +80,13 @@ func DefaultTheme() Theme {
 		LabelColor:      colors.BurndownChartLabelColor,
 		ValueColor:      colors.BurndownChartValueColor,
 		BarColor:        colors.BurndownChartBarColor,
+		MarkerColor:     colors.ChartMarkerColor,
 		BackgroundColor: config.GetContentBackgroundColor(),
 		BarGradientFrom: colors.BurndownChartGradientFrom.Start,
 		BarGradientTo:   colors.BurndownChartGradientTo.Start,
 		DotChar:         '⣿', // braille full cell for dense dot matrix
 		BarChar:         '█',
+		MarkerChar:      '━',
 		DotRowGap:       0,
 		DotColGap:       0,
 	}
@@ -99,6 +113,11 @@ func NewBarChart() *BarChart {
 // SetBars replaces the bars to render.
 func (c *BarChart) SetBars(bars []Bar) *BarChart {
 	c.bars = append([]Bar(nil), bars...)
+	for i := range c.bars {
+		if len(c.bars[i].Segments) > 0 {
+			c.bars[i].Value = segmentTotal(c.bars[i].Segments)
+		}
+	}
 	return c
 }
 
@@ -306,6 +325,10 @@ func (c *BarChart) Draw(screen tcell.Screen) {
 			}
 		}
 
+		if row := markerRow(bar.Marker, maxValue, chartHeight); row >= 0 {
+			drawMarker(screen, barX, chartBottom-row, barWidth, c.theme)
+		}
+
 		if labelHeight > 0 {
 			drawCenteredText(screen, barX, labelY, barWidth, truncateRunes(bar.Label, barWidth), c.theme.LabelColor, c.theme.BackgroundColor)
 		}
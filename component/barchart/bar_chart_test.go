@@ -85,6 +85,30 @@ func TestBarFillColorPrefersCustom(t *testing.T) {
 	}
 }
 
+func TestBarFillColorStackedSegments(t *testing.T) {
+	theme := DefaultTheme()
+	chart := NewBarChart().SetBars([]Bar{{
+		Segments: []Segment{
+			{Value: 1, Color: tcell.ColorGreen},
+			{Value: 3, Color: tcell.ColorBlue},
+		},
+	}})
+	bar := chart.bars[0]
+	if bar.Value != 4 {
+		t.Fatalf("expected value to be the segment sum, got %v", bar.Value)
+	}
+
+	// 4 rows: bottom row belongs to the first segment, the rest to the second
+	if got := barFillColor(bar, 0, 4, theme); got != tcell.ColorGreen {
+		t.Fatalf("expected bottom row to use first segment color, got %v", got)
+	}
+	for row := 1; row < 4; row++ {
+		if got := barFillColor(bar, row, 4, theme); got != tcell.ColorBlue {
+			t.Fatalf("row %d: expected second segment color, got %v", row, got)
+		}
+	}
+}
+
 func TestValueToBrailleHeight(t *testing.T) {
 	if got := valueToBrailleHeight(50, 100, 2); got != 4 {
 		t.Fatalf("expected scaled height of 4, got %d", got)
@@ -121,3 +145,15 @@ func TestBrailleColumnMaskOrder(t *testing.T) {
 		t.Fatalf("full left column should produce mask 0x47, got 0x%x", got-0x2800)
 	}
 }
+
+func TestMarkerRow(t *testing.T) {
+	if got := markerRow(0, 10, 5); got != -1 {
+		t.Fatalf("expected no marker for zero value, got row %d", got)
+	}
+	if got := markerRow(10, 10, 5); got != 4 {
+		t.Fatalf("expected marker at top row 4, got %d", got)
+	}
+	if got := markerRow(0.1, 10, 5); got != 0 {
+		t.Fatalf("expected small marker on bottom row, got %d", got)
+	}
+}
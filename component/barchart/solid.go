@@ -34,7 +34,17 @@ func drawBarDots(screen tcell.Screen, x, bottomY, width, height int, bar Bar, th
 	}
 }
 
+func drawMarker(screen tcell.Screen, x, y, width int, theme Theme) {
+	style := tcell.StyleDefault.Foreground(theme.MarkerColor).Background(theme.BackgroundColor)
+	for col := 0; col < width; col++ {
+		screen.SetContent(x+col, y, theme.MarkerChar, nil, style)
+	}
+}
+
 func barFillColor(bar Bar, row, total int, theme Theme) tcell.Color {
+	if len(bar.Segments) > 0 {
+		return segmentColor(bar, row, total, theme)
+	}
 	if bar.UseColor {
 		return bar.Color
 	}
@@ -52,3 +62,25 @@ func barFillColor(bar Bar, row, total int, theme Theme) tcell.Color {
 	//nolint:gosec // G115: RGB values are 0-255, safe to convert to int32
 	return tcell.NewRGBColor(int32(rgb[0]), int32(rgb[1]), int32(rgb[2]))
 }
+
+// segmentColor picks the color of the stacked segment covering the middle of the given row.
+func segmentColor(bar Bar, row, total int, theme Theme) tcell.Color {
+	if total <= 0 || bar.Value <= 0 {
+		return theme.BarColor
+	}
+	level := (float64(row) + 0.5) / float64(total) * bar.Value
+	cumulative := 0.0
+	for _, seg := range bar.Segments {
+		cumulative += seg.Value
+		if level < cumulative {
+			return seg.Color
+		}
+	}
+	// rounding can leave the top row just above the sum
+	for i := len(bar.Segments) - 1; i >= 0; i-- {
+		if bar.Segments[i].Value > 0 {
+			return bar.Segments[i].Color
+		}
+	}
+	return theme.BarColor
+}
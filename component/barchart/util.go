@@ -120,6 +120,16 @@ func maxBarValue(bars []Bar) float64 {
 	return max
 }
 
+func segmentTotal(segments []Segment) float64 {
+	total := 0.0
+	for _, seg := range segments {
+		if seg.Value > 0 {
+			total += seg.Value
+		}
+	}
+	return total
+}
+
 func truncateRunes(text string, width int) string {
 	if width <= 0 {
 		return ""
@@ -139,3 +149,11 @@ func hasLabels(bars []Bar) bool {
 	}
 	return false
 }
+
+// markerRow returns the row (0 = bottom) at which a marker value is drawn, or -1 for none.
+func markerRow(marker, maxValue float64, chartHeight int) int {
+	if marker <= 0 || maxValue <= 0 || chartHeight <= 0 {
+		return -1
+	}
+	return valueToHeight(marker, maxValue, chartHeight) - 1
+}
@@ -65,6 +65,10 @@ type ColorConfig struct {
 	BurndownHeaderGradientFrom Gradient // Header-specific chart gradient
 	BurndownHeaderGradientTo   Gradient
 
+	// Chart plugin colors
+	ChartStatusColors map[string]tcell.Color // stacked segment color keyed by status (backlog, ready, ...)
+	ChartMarkerColor  tcell.Color            // reference line across bars (e.g. velocity rolling average)
+
 	// Header view colors
 	HeaderInfoLabel  string // tview color string like "[orange]"
 	HeaderInfoValue  string // tview color string like "[white]"
@@ -155,6 +159,16 @@ func DefaultColors() *ColorConfig {
 			End:   [3]int{110, 190, 255}, // Cyan top (solid)
 		},
 
+		// Chart plugins
+		ChartStatusColors: map[string]tcell.Color{
+			"backlog":     tcell.NewRGBColor(110, 110, 130), // Slate gray
+			"ready":       tcell.NewRGBColor(90, 170, 255),  // Blue
+			"in_progress": tcell.NewRGBColor(255, 175, 95),  // Orange
+			"review":      tcell.NewRGBColor(200, 120, 230), // Purple
+			"done":        tcell.NewRGBColor(95, 200, 120),  // Green
+		},
+		ChartMarkerColor: tcell.NewRGBColor(255, 215, 95), // Amber
+
 		// Header
 		HeaderInfoLabel:  "[orange]",
 		HeaderInfoValue:  "[#cccccc]",
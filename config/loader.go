@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/spf13/pflag"
@@ -38,6 +39,21 @@ type Config struct {
 		MaxPoints int `mapstructure:"maxPoints"`
 	} `mapstructure:"tiki"`
 
+	// Burndown configuration. Sprint dates (burndown.start, burndown.end) and the
+	// sprints list are read through GetBurndownSprint and GetSprints because YAML
+	// decodes unquoted dates as timestamps.
+	Burndown struct {
+		Sprint string `mapstructure:"sprint"` // sprint name from the sprints list, or "current"
+		Filter string `mapstructure:"filter"` // filter expression selecting tasks in scope
+		Unit   string `mapstructure:"unit"`   // "count" or "points"
+	} `mapstructure:"burndown"`
+
+	// Store configuration: the storage backend and the extra task directories it reads
+	Store struct {
+		Backend string   `mapstructure:"backend"` // "tiki" or "multidir"
+		Dirs    []string `mapstructure:"dirs"`    // task directories besides .doc/tiki, e.g. .doc/archive
+	} `mapstructure:"store"`
+
 	// Appearance configuration
 	Appearance struct {
 		Theme             string `mapstructure:"theme"`             // "dark", "light", "auto"
@@ -111,6 +127,12 @@ func setDefaults() {
 	// Tiki defaults
 	viper.SetDefault("tiki.maxPoints", 10)
 
+	// Burndown defaults
+	viper.SetDefault("burndown.unit", "count")
+
+	// Store defaults
+	viper.SetDefault("store.backend", "tiki")
+
 	// Appearance defaults
 	viper.SetDefault("appearance.theme", "auto")
 	viper.SetDefault("appearance.gradientThreshold", 256)
@@ -276,6 +298,118 @@ func GetMaxPoints() int {
 	return maxPoints
 }
 
+// GetBurndownSprint returns the configured sprint as [start, end) in local time.
+// burndown.sprint names a sprint from the sprints section (or "current");
+// otherwise burndown.start and burndown.end give the dates directly.
+// Both are zero when no sprint is configured and the rolling window applies.
+func GetBurndownSprint() (time.Time, time.Time, error) {
+	if name := strings.TrimSpace(viper.GetString("burndown.sprint")); name != "" {
+		sprints, err := GetSprints()
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		sprint, ok := FindSprint(sprints, name, time.Now())
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("burndown sprint %q not found", name)
+		}
+		return sprint.Start, sprint.End, nil
+	}
+
+	startSet := viper.IsSet("burndown.start")
+	endSet := viper.IsSet("burndown.end")
+	if !startSet && !endSet {
+		return time.Time{}, time.Time{}, nil
+	}
+	if !startSet || !endSet {
+		return time.Time{}, time.Time{}, fmt.Errorf("burndown sprint needs both start and end")
+	}
+
+	start, err := configDate(viper.Get("burndown.start"))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid burndown start: %w", err)
+	}
+	end, err := configDate(viper.Get("burndown.end"))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid burndown end: %w", err)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("burndown end %s is before start %s",
+			end.Format(time.DateOnly), start.Format(time.DateOnly))
+	}
+
+	// the end date is inclusive
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// configDate converts a YAML date (decoded as a timestamp or left as a YYYY-MM-DD string)
+// to local midnight of that calendar day.
+func configDate(raw interface{}) (time.Time, error) {
+	switch v := raw.(type) {
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.Local), nil
+	case string:
+		return time.ParseInLocation(time.DateOnly, strings.TrimSpace(v), time.Local)
+	default:
+		return time.Time{}, fmt.Errorf("expected a YYYY-MM-DD date, got %v", raw)
+	}
+}
+
+// GetBurndownFilter returns the filter expression scoping the burndown (empty = all tasks)
+func GetBurndownFilter() string {
+	return strings.TrimSpace(viper.GetString("burndown.filter"))
+}
+
+// GetBurndownPoints reports whether the burndown measures story points instead of task count
+func GetBurndownPoints() bool {
+	return strings.EqualFold(strings.TrimSpace(viper.GetString("burndown.unit")), "points")
+}
+
+// storeBackend overrides store.backend for commands that need a particular backend
+var storeBackend string
+
+// SetStoreBackend makes GetStoreBackend return name, whatever config.yaml says
+func SetStoreBackend(name string) {
+	storeBackend = name
+}
+
+// GetStoreBackend returns the task storage backend named by store.backend ("tiki" by default)
+func GetStoreBackend() string {
+	if storeBackend != "" {
+		return storeBackend
+	}
+	backend := strings.ToLower(strings.TrimSpace(viper.GetString("store.backend")))
+	if backend == "" {
+		return "tiki"
+	}
+	return backend
+}
+
+// GetStoreDirs returns the task directories: the project's task directory first, where new
+// tasks go, then those listed in store.dirs. Relative entries are resolved against the
+// project root and duplicates are dropped.
+func GetStoreDirs() []string {
+	taskDir := GetTaskDir()
+	projectRoot := filepath.Dir(GetProjectConfigDir())
+	dirs := []string{taskDir}
+	seen := map[string]bool{filepath.Clean(taskDir): true}
+	for _, dir := range viper.GetStringSlice("store.dirs") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectRoot, dir)
+		}
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
 // saveConfig writes the current viper configuration to config.yaml
 func saveConfig() error {
 	configFile := viper.ConfigFileUsed()
@@ -343,3 +477,65 @@ func GetGradientThreshold() int {
 	}
 	return threshold
 }
+
+// GetGitHubToken returns the token the markdown viewer sends for private GitHub sources:
+// GITHUB_TOKEN, else viewer.githubToken from the user config.yaml
+func GetGitHubToken() string {
+	if token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN")); token != "" {
+		return token
+	}
+	return strings.TrimSpace(readUserViewerConfig().GitHubToken)
+}
+
+// GetGitLabToken returns the token the markdown viewer sends for private GitLab sources:
+// GITLAB_TOKEN, else viewer.gitlabToken from the user config.yaml
+func GetGitLabToken() string {
+	if token := strings.TrimSpace(os.Getenv("GITLAB_TOKEN")); token != "" {
+		return token
+	}
+	return strings.TrimSpace(readUserViewerConfig().GitLabToken)
+}
+
+// GetGitLabHosts returns the hosts the markdown viewer treats as GitLab: gitlab.com, the
+// comma-separated GITLAB_HOST and viewer.gitlabHosts from the user config.yaml. Hosts are
+// given without scheme or path, e.g. "git.example.com" or "git.example.com:8443".
+func GetGitLabHosts() []string {
+	hosts := []string{"gitlab.com"}
+	seen := map[string]bool{"gitlab.com": true}
+	configured := append(strings.Split(os.Getenv("GITLAB_HOST"), ","), readUserViewerConfig().GitLabHosts...)
+	for _, host := range configured {
+		host = strings.TrimSpace(host)
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+		host = strings.TrimSuffix(host, "/")
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// viewerConfig is the viewer section of config.yaml
+type viewerConfig struct {
+	GitHubToken string   `yaml:"githubToken"`
+	GitLabToken string   `yaml:"gitlabToken"`
+	GitLabHosts []string `yaml:"gitlabHosts"`
+}
+
+// readUserViewerConfig reads the viewer section of the user config.yaml. The project
+// config.yaml isn't read: it is committed with the repository, so a cloned project could
+// name its own host and be sent the user's token.
+func readUserViewerConfig() viewerConfig {
+	var cfg struct {
+		Viewer viewerConfig `yaml:"viewer"`
+	}
+	data, err := os.ReadFile(GetConfigFile())
+	if err != nil {
+		return cfg.Viewer
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		slog.Warn("ignoring viewer settings", "file", GetConfigFile(), "error", err)
+	}
+	return cfg.Viewer
+}
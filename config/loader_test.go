@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -132,3 +135,154 @@ func TestGetConfig(t *testing.T) {
 		t.Error("GetConfig should return the same instance")
 	}
 }
+
+func TestGetBurndownSprint(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+burndown:
+  start: 2024-06-03
+  end: 2024-06-14
+  filter: "type = story"
+  unit: points
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	appConfig = nil
+	ResetPathManager()
+
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	start, end, err := GetBurndownSprint()
+	if err != nil {
+		t.Fatalf("GetBurndownSprint failed: %v", err)
+	}
+	if want := time.Date(2024, 6, 3, 0, 0, 0, 0, time.Local); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+	// end date is inclusive, so the window closes at the following midnight
+	if want := time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+	if got := GetBurndownFilter(); got != "type = story" {
+		t.Errorf("GetBurndownFilter() = %q, want %q", got, "type = story")
+	}
+	if !GetBurndownPoints() {
+		t.Error("GetBurndownPoints() = false, want true")
+	}
+}
+
+func TestGetStoreBackendAndDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	sharedDir := filepath.Join(t.TempDir(), "shared")
+	configContent := `
+store:
+  backend: MultiDir
+  dirs:
+    - .doc/archive
+    - .doc/tiki
+    - ` + sharedDir + `
+    - .doc/archive/
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	appConfig = nil
+	ResetPathManager()
+	defer ResetPathManager()
+
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if got := GetStoreBackend(); got != "multidir" {
+		t.Errorf("GetStoreBackend() = %q, want multidir", got)
+	}
+
+	dirs := GetStoreDirs()
+	want := []string{GetTaskDir(), filepath.Join(filepath.Dir(GetProjectConfigDir()), ".doc", "archive"), sharedDir}
+	if len(dirs) != len(want) {
+		t.Fatalf("GetStoreDirs() = %v, want %v", dirs, want)
+	}
+	for i := range want {
+		if dirs[i] != want[i] {
+			t.Errorf("GetStoreDirs()[%d] = %q, want %q", i, dirs[i], want[i])
+		}
+	}
+}
+
+func TestViewerSettingsReadFromUserConfigOnly(t *testing.T) {
+	projectDir := t.TempDir()
+	userDir := t.TempDir()
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(projectDir)
+	t.Setenv("XDG_CONFIG_HOME", userDir)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("GITLAB_HOST", "")
+	appConfig = nil
+	ResetPathManager()
+	defer ResetPathManager()
+
+	project := "viewer:\n  githubToken: project-token\n  gitlabToken: project-token\n  gitlabHosts:\n    - evil.example.com\n"
+	if err := os.MkdirAll(filepath.Dir(GetProjectConfigFile()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetProjectConfigFile(), []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if viper.GetString("viewer.githubToken") != "project-token" {
+		t.Fatal("project config not loaded")
+	}
+
+	// a committed project config can't set tokens or hosts
+	if got := GetGitHubToken(); got != "" {
+		t.Errorf("GetGitHubToken() = %q from the project config, want none", got)
+	}
+	if got := GetGitLabToken(); got != "" {
+		t.Errorf("GetGitLabToken() = %q from the project config, want none", got)
+	}
+	if got := GetGitLabHosts(); len(got) != 1 || got[0] != "gitlab.com" {
+		t.Errorf("GetGitLabHosts() = %v, want only gitlab.com", got)
+	}
+
+	user := "viewer:\n  githubToken: user-token\n  gitlabHosts:\n    - https://git.example.com/\n"
+	if err := os.MkdirAll(filepath.Dir(GetConfigFile()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetConfigFile(), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetGitHubToken(); got != "user-token" {
+		t.Errorf("GetGitHubToken() = %q, want the user config token", got)
+	}
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	if got := GetGitHubToken(); got != "env-token" {
+		t.Errorf("GetGitHubToken() = %q, want GITHUB_TOKEN first", got)
+	}
+	t.Setenv("GITLAB_HOST", "gl.internal")
+	if got := GetGitLabHosts(); len(got) != 3 || got[1] != "gl.internal" || got[2] != "git.example.com" {
+		t.Errorf("GetGitLabHosts() = %v, want gitlab.com, GITLAB_HOST and the user config host", got)
+	}
+}
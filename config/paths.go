@@ -188,6 +188,15 @@ func (pm *PathManager) TemplateFile() string {
 	return filepath.Join(pm.configDir, "new.md")
 }
 
+// TemplateDirs returns the directories holding named task templates: the project's
+// .doc/templates first, then templates in the user config directory
+func (pm *PathManager) TemplateDirs() []string {
+	return []string{
+		filepath.Join(pm.ProjectConfigDir(), "templates"),
+		filepath.Join(pm.configDir, "templates"),
+	}
+}
+
 // EnsureDirs creates all necessary directories with appropriate permissions
 func (pm *PathManager) EnsureDirs() error {
 	// Create user config directory
@@ -402,6 +411,11 @@ func GetTemplateFile() string {
 	return mustGetPathManager().TemplateFile()
 }
 
+// GetTemplateDirs returns the directories holding named task templates, project first
+func GetTemplateDirs() []string {
+	return mustGetPathManager().TemplateDirs()
+}
+
 // EnsureDirs creates all necessary directories with appropriate permissions
 func EnsureDirs() error {
 	return mustGetPathManager().EnsureDirs()
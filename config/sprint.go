@@ -0,0 +1,135 @@
+package config
+
+// Sprint (iteration) definitions: named date ranges from the sprints section of config.yaml
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/viper"
+)
+
+// CurrentSprint selects the sprint containing today wherever a sprint name is expected
+const CurrentSprint = "current"
+
+// Sprint is a named iteration. Tasks carrying the sprint's tag belong to it.
+type Sprint struct {
+	Name  string
+	Tag   string    // tag marking sprint membership; defaults to the name as a tag, "Sprint 12" -> sprint-12
+	Start time.Time // local midnight of the first day
+	End   time.Time // local midnight after the last day (exclusive)
+}
+
+// Contains reports whether t falls within the sprint
+func (s Sprint) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+// HasTag reports whether tags include the sprint's tag (case-insensitive)
+func (s Sprint) HasTag(tags []string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, s.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSprints returns the configured sprints ordered by start date.
+// Example:
+//
+//	sprints:
+//	  - name: Sprint 12
+//	    tag: sprint-12
+//	    start: 2026-09-21
+//	    end: 2026-10-02
+func GetSprints() ([]Sprint, error) {
+	raw := viper.Get("sprints")
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("sprints must be a list")
+	}
+
+	sprints := make([]Sprint, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("sprint %d must be a mapping", i+1)
+		}
+
+		name := strings.TrimSpace(fmt.Sprint(fields["name"]))
+		if fields["name"] == nil || name == "" {
+			return nil, fmt.Errorf("sprint %d has no name", i+1)
+		}
+		if strings.EqualFold(name, CurrentSprint) {
+			return nil, fmt.Errorf("sprint name %q is reserved", name)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("duplicate sprint name %q", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		start, err := configDate(fields["start"])
+		if err != nil {
+			return nil, fmt.Errorf("sprint %q: invalid start: %w", name, err)
+		}
+		end, err := configDate(fields["end"])
+		if err != nil {
+			return nil, fmt.Errorf("sprint %q: invalid end: %w", name, err)
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("sprint %q ends before it starts", name)
+		}
+
+		tag := sprintTag(name)
+		if rawTag, ok := fields["tag"].(string); ok && strings.TrimSpace(rawTag) != "" {
+			tag = strings.TrimSpace(rawTag)
+			if strings.ContainsFunc(tag, unicode.IsSpace) {
+				return nil, fmt.Errorf("sprint %q: tag %q can't contain spaces", name, tag)
+			}
+		}
+
+		sprints = append(sprints, Sprint{
+			Name:  name,
+			Tag:   tag,
+			Start: start,
+			End:   end.AddDate(0, 0, 1), // the end date is inclusive
+		})
+	}
+
+	sort.SliceStable(sprints, func(i, j int) bool {
+		return sprints[i].Start.Before(sprints[j].Start)
+	})
+	return sprints, nil
+}
+
+// sprintTag turns a sprint name into the tag tikis carry: lowercase, with runs of spaces
+// replaced by dashes, since a tag can't hold spaces
+func sprintTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// FindSprint looks up a sprint by name (case-insensitive). CurrentSprint selects
+// the sprint containing now. Returns false if there is no match.
+func FindSprint(sprints []Sprint, name string, now time.Time) (Sprint, bool) {
+	name = strings.TrimSpace(name)
+	for _, sprint := range sprints {
+		if strings.EqualFold(name, CurrentSprint) {
+			if sprint.Contains(now) {
+				return sprint, true
+			}
+			continue
+		}
+		if strings.EqualFold(sprint.Name, name) {
+			return sprint, true
+		}
+	}
+	return Sprint{}, false
+}
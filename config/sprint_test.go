@@ -0,0 +1,122 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func loadTestYAML(t *testing.T, content string) {
+	t.Helper()
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatalf("failed to read test config: %v", err)
+	}
+	t.Cleanup(viper.Reset)
+}
+
+func TestGetSprints(t *testing.T) {
+	loadTestYAML(t, `
+sprints:
+  - name: Sprint 2
+    tag: sprint-2
+    start: 2024-06-17
+    end: "2024-06-28"
+  - name: Sprint 1
+    start: 2024-06-03
+    end: 2024-06-14
+`)
+
+	sprints, err := GetSprints()
+	if err != nil {
+		t.Fatalf("GetSprints failed: %v", err)
+	}
+	if len(sprints) != 2 {
+		t.Fatalf("len(sprints) = %d, want 2", len(sprints))
+	}
+
+	first := sprints[0]
+	if first.Name != "Sprint 1" || first.Tag != "sprint-1" {
+		t.Errorf("first sprint = %q (tag %q), want Sprint 1 tagged sprint-1 after its name", first.Name, first.Tag)
+	}
+	if want := time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local); !first.End.Equal(want) {
+		t.Errorf("first.End = %v, want %v (end date is inclusive)", first.End, want)
+	}
+	if sprints[1].Tag != "sprint-2" {
+		t.Errorf("second sprint tag = %q, want sprint-2", sprints[1].Tag)
+	}
+
+	current, ok := FindSprint(sprints, CurrentSprint, time.Date(2024, 6, 20, 12, 0, 0, 0, time.Local))
+	if !ok || current.Name != "Sprint 2" {
+		t.Errorf("FindSprint(current) = %q, %v; want Sprint 2", current.Name, ok)
+	}
+	if _, ok := FindSprint(sprints, "sprint 1", time.Now()); !ok {
+		t.Error("FindSprint should match names case-insensitively")
+	}
+}
+
+func TestGetSprintsInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantError string
+	}{
+		{
+			name:      "missing name",
+			yaml:      "sprints:\n  - start: 2024-06-03\n    end: 2024-06-14\n",
+			wantError: "has no name",
+		},
+		{
+			name:      "duplicate name",
+			yaml:      "sprints:\n  - {name: A, start: 2024-06-03, end: 2024-06-14}\n  - {name: a, start: 2024-06-17, end: 2024-06-28}\n",
+			wantError: "duplicate sprint name",
+		},
+		{
+			name:      "end before start",
+			yaml:      "sprints:\n  - {name: A, start: 2024-06-14, end: 2024-06-03}\n",
+			wantError: "ends before it starts",
+		},
+		{
+			name:      "bad date",
+			yaml:      "sprints:\n  - {name: A, start: June, end: 2024-06-03}\n",
+			wantError: "invalid start",
+		},
+		{
+			name:      "tag with spaces",
+			yaml:      "sprints:\n  - {name: A, tag: sprint a, start: 2024-06-03, end: 2024-06-14}\n",
+			wantError: "can't contain spaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestYAML(t, tt.yaml)
+			_, err := GetSprints()
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("GetSprints() error = %v, want containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGetBurndownSprintByName(t *testing.T) {
+	loadTestYAML(t, `
+burndown:
+  sprint: Sprint 1
+sprints:
+  - name: Sprint 1
+    start: 2024-06-03
+    end: 2024-06-14
+`)
+
+	start, end, err := GetBurndownSprint()
+	if err != nil {
+		t.Fatalf("GetBurndownSprint failed: %v", err)
+	}
+	if !start.Equal(time.Date(2024, 6, 3, 0, 0, 0, 0, time.Local)) || !end.Equal(time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("GetBurndownSprint() = %v – %v, want Sprint 1 dates", start, end)
+	}
+}
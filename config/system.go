@@ -1,7 +1,7 @@
 package config
 
 import (
-	_ "embed"
+	"embed"
 	"fmt"
 	"os"
 	"os/exec"
@@ -41,6 +41,9 @@ var dokiLinked string
 //go:embed default_workflow.yaml
 var defaultWorkflowYAML string
 
+//go:embed templates/*.md
+var sampleTemplates embed.FS
+
 // GenerateRandomID generates a 6-character random alphanumeric ID (lowercase)
 func GenerateRandomID() string {
 	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
@@ -131,6 +134,13 @@ func BootstrapSystem() error {
 	}
 	createdFiles = append(createdFiles, linkedPath)
 
+	// Write sample task templates (bug, spike, postmortem)
+	templateFiles, err := writeSampleTemplates(filepath.Join(GetProjectConfigDir(), "templates"))
+	if err != nil {
+		return fmt.Errorf("write sample templates: %w", err)
+	}
+	createdFiles = append(createdFiles, templateFiles...)
+
 	// Write default config.yaml
 	defaultConfig := `logging:
   level: error
@@ -160,6 +170,32 @@ appearance:
 	return nil
 }
 
+// writeSampleTemplates copies the embedded task templates to dir and returns the written paths
+func writeSampleTemplates(dir string) ([]string, error) {
+	entries, err := sampleTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	//nolint:gosec // G301: 0755 is appropriate for templates directory
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create templates directory %s: %w", dir, err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		data, err := sampleTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, entry.Name())
+		//nolint:gosec // G306: 0644 is appropriate for template files
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("write template %s: %w", entry.Name(), err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // GetDefaultNewTaskTemplate returns the embedded new.md template
 func GetDefaultNewTaskTemplate() string {
 	return defaultNewTaskTemplate
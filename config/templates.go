@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// named task templates: markdown files with frontmatter defaults and a description
+// skeleton, like new.md. A template is named after its file: templates/bug.md is "bug".
+
+// ListTaskTemplates returns the names of the available task templates, sorted.
+// A project template hides a user template with the same name.
+func ListTaskTemplates() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, dir := range GetTemplateDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := templateName(entry)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FindTaskTemplate returns the path of the named task template
+func FindTaskTemplate(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+	for _, dir := range GetTemplateDirs() {
+		path := filepath.Join(dir, name+".md")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	if names := ListTaskTemplates(); len(names) > 0 {
+		return "", fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return "", fmt.Errorf("unknown template %q (no templates in %s)", name, strings.Join(GetTemplateDirs(), " or "))
+}
+
+func templateName(entry os.DirEntry) (string, bool) {
+	if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || strings.HasPrefix(entry.Name(), ".") {
+		return "", false
+	}
+	return strings.TrimSuffix(entry.Name(), ".md"), true
+}
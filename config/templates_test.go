@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTaskTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectTemplates := filepath.Join(tmpDir, ".doc", "templates")
+	userTemplates := filepath.Join(tmpDir, "xdg", "tiki", "templates")
+	for path, content := range map[string]string{
+		filepath.Join(projectTemplates, "bug.md"):      "---\ntype: bug\n---\n",
+		filepath.Join(projectTemplates, "notes.txt"):   "not a template",
+		filepath.Join(userTemplates, "bug.md"):         "---\ntype: story\n---\n",
+		filepath.Join(userTemplates, "postmortem.md"):  "---\ntype: story\n---\n",
+		filepath.Join(userTemplates, ".hidden.md"):     "---\n---\n",
+		filepath.Join(userTemplates, "spike.md", "x"):  "a directory, not a template",
+		filepath.Join(tmpDir, "xdg", "tiki", "new.md"): "---\n---\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	ResetPathManager()
+	defer ResetPathManager()
+
+	if got, want := ListTaskTemplates(), []string{"bug", "postmortem"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListTaskTemplates() = %v, want %v", got, want)
+	}
+
+	// the project template hides the user one
+	path, err := FindTaskTemplate("bug")
+	if err != nil {
+		t.Fatalf("FindTaskTemplate(bug): %v", err)
+	}
+	if want := filepath.Join(projectTemplates, "bug.md"); path != want {
+		t.Errorf("FindTaskTemplate(bug) = %s, want %s", path, want)
+	}
+
+	if _, err := FindTaskTemplate("spike"); err == nil || !strings.Contains(err.Error(), "bug, postmortem") {
+		t.Errorf("expected unknown template error listing templates, got %v", err)
+	}
+	if _, err := FindTaskTemplate("../new"); err == nil {
+		t.Error("expected error for a name outside the templates directories")
+	}
+}
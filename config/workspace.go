@@ -0,0 +1,110 @@
+package config
+
+// Workspace: the repositories `tiki workspace` shows on one board, listed in the user config.yaml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceRepo is a repository of the workspace
+type WorkspaceRepo struct {
+	Name string // prefix of the repository's task IDs on the combined board: api:TIKI-ABC123
+	Path string // repository root
+}
+
+// GetWorkspaceRepos returns the repositories listed under workspace.repos in the user
+// config.yaml. The project config.yaml isn't read: a workspace spans projects. A repository
+// is named after its directory unless it gives a name.
+// Example:
+//
+//	workspace:
+//	  repos:
+//	    - name: api
+//	      path: ~/src/api-server
+//	    - ~/src/web
+func GetWorkspaceRepos() ([]WorkspaceRepo, error) {
+	configFile := GetConfigFile()
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no workspace: list repositories under workspace.repos in %s", configFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", configFile, err)
+	}
+	return parseWorkspaceRepos(data, configFile)
+}
+
+func parseWorkspaceRepos(data []byte, configFile string) ([]WorkspaceRepo, error) {
+	var cfg struct {
+		Workspace struct {
+			Repos []yaml.Node `yaml:"repos"`
+		} `yaml:"workspace"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", configFile, err)
+	}
+	if len(cfg.Workspace.Repos) == 0 {
+		return nil, fmt.Errorf("no workspace: list repositories under workspace.repos in %s", configFile)
+	}
+
+	repos := make([]WorkspaceRepo, 0, len(cfg.Workspace.Repos))
+	seen := make(map[string]bool, len(cfg.Workspace.Repos))
+	for i, node := range cfg.Workspace.Repos {
+		var repo WorkspaceRepo
+		if node.Kind == yaml.ScalarNode {
+			repo.Path = node.Value
+		} else {
+			var fields struct {
+				Name string `yaml:"name"`
+				Path string `yaml:"path"`
+			}
+			if err := node.Decode(&fields); err != nil {
+				return nil, fmt.Errorf("workspace repo %d: %w", i+1, err)
+			}
+			repo.Name, repo.Path = strings.TrimSpace(fields.Name), fields.Path
+		}
+
+		path, err := expandHome(strings.TrimSpace(repo.Path))
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			return nil, fmt.Errorf("workspace repo %d has no path", i+1)
+		}
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("workspace repo %q: path must be absolute or start with ~/", repo.Path)
+		}
+		repo.Path = filepath.Clean(path)
+
+		if repo.Name == "" {
+			repo.Name = filepath.Base(repo.Path)
+		}
+		if strings.ContainsAny(repo.Name, ": \t") {
+			return nil, fmt.Errorf("workspace repo name %q can't contain spaces or colons", repo.Name)
+		}
+		if seen[strings.ToLower(repo.Name)] {
+			return nil, fmt.Errorf("duplicate workspace repo name %q: give the repos names", repo.Name)
+		}
+		seen[strings.ToLower(repo.Name)] = true
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ErrNoHome
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
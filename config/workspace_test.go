@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseWorkspaceRepos(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	abs := filepath.Join(t.TempDir(), "web")
+
+	repos, err := parseWorkspaceRepos([]byte(`
+workspace:
+  repos:
+    - name: api
+      path: ~/src/api-server
+    - `+abs+`
+`), "config.yaml")
+	if err != nil {
+		t.Fatalf("parseWorkspaceRepos: %v", err)
+	}
+	want := []WorkspaceRepo{
+		{Name: "api", Path: filepath.Join(home, "src", "api-server")},
+		{Name: "web", Path: abs},
+	}
+	if len(repos) != len(want) {
+		t.Fatalf("got %d repos, want %d", len(repos), len(want))
+	}
+	for i := range want {
+		if repos[i] != want[i] {
+			t.Errorf("repo %d = %+v, want %+v", i, repos[i], want[i])
+		}
+	}
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no workspace", "logging:\n  level: info\n", "no workspace"},
+		{"relative path", "workspace:\n  repos:\n    - src/api\n", "must be absolute"},
+		{"no path", "workspace:\n  repos:\n    - name: api\n", "has no path"},
+		{"colon in name", "workspace:\n  repos:\n    - name: 'a:b'\n      path: " + abs + "\n", "can't contain"},
+		{"duplicate names", "workspace:\n  repos:\n    - " + abs + "\n    - name: WEB\n      path: ~/web\n", "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseWorkspaceRepos([]byte(tt.yaml), "config.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -22,14 +22,15 @@ const (
 
 // ActionID values for task navigation and manipulation (used by plugins).
 const (
-	ActionMoveTaskLeft  ActionID = "move_task_left"
-	ActionMoveTaskRight ActionID = "move_task_right"
-	ActionNewTask       ActionID = "new_task"
-	ActionDeleteTask    ActionID = "delete_task"
-	ActionNavLeft       ActionID = "nav_left"
-	ActionNavRight      ActionID = "nav_right"
-	ActionNavUp         ActionID = "nav_up"
-	ActionNavDown       ActionID = "nav_down"
+	ActionMoveTaskLeft    ActionID = "move_task_left"
+	ActionMoveTaskRight   ActionID = "move_task_right"
+	ActionNewTask         ActionID = "new_task"
+	ActionNewFromTemplate ActionID = "new_from_template"
+	ActionDeleteTask      ActionID = "delete_task"
+	ActionNavLeft         ActionID = "nav_left"
+	ActionNavRight        ActionID = "nav_right"
+	ActionNavUp           ActionID = "nav_up"
+	ActionNavDown         ActionID = "nav_down"
 )
 
 // ActionID values for task detail view actions.
@@ -38,6 +39,7 @@ const (
 	ActionEditSource ActionID = "edit_source"
 	ActionFullscreen ActionID = "fullscreen"
 	ActionCloneTask  ActionID = "clone_task"
+	ActionHistory    ActionID = "history"
 )
 
 // ActionID values for task edit view actions.
@@ -60,10 +62,24 @@ const (
 	ActionOpenFromPlugin ActionID = "open_from_plugin"
 )
 
+// ActionID values for chart plugin actions.
+const (
+	ActionWindowShorter ActionID = "window_shorter"
+	ActionWindowLonger  ActionID = "window_longer"
+)
+
 // ActionID values for doki plugin (markdown navigation) actions.
 const (
 	ActionNavigateBack    ActionID = "navigate_back"
 	ActionNavigateForward ActionID = "navigate_forward"
+	ActionNextLink        ActionID = "navigate_next_link"
+	ActionBacklinks       ActionID = "backlinks"
+	ActionOutline         ActionID = "outline"
+	ActionNextHeading     ActionID = "next_heading"
+	ActionPrevHeading     ActionID = "prev_heading"
+	ActionFind            ActionID = "find"
+	ActionFindNext        ActionID = "find_next"
+	ActionFindPrev        ActionID = "find_prev"
 )
 
 // PluginInfo provides the minimal info needed to register plugin actions.
@@ -252,6 +268,12 @@ func TaskDetailViewActions() *ActionRegistry {
 	r.Register(Action{ID: ActionEditTitle, Key: tcell.KeyRune, Rune: 'e', Label: "Edit", ShowInHeader: true})
 	r.Register(Action{ID: ActionEditSource, Key: tcell.KeyRune, Rune: 's', Label: "Edit source", ShowInHeader: true})
 	r.Register(Action{ID: ActionFullscreen, Key: tcell.KeyRune, Rune: 'f', Label: "Full screen", ShowInHeader: true})
+	r.Register(Action{ID: ActionHistory, Key: tcell.KeyRune, Rune: 'h', Label: "History", ShowInHeader: true})
+	// handled by the description viewer: Tab selects links to tikis and doki pages, Enter follows them
+	r.Register(Action{ID: ActionNextLink, Key: tcell.KeyTab, Label: "Next Link", ShowInHeader: true})
+	r.Register(Action{ID: ActionOutline, Key: tcell.KeyRune, Rune: 'o', Label: "Outline", ShowInHeader: true})
+	r.Register(Action{ID: ActionNextHeading, Key: tcell.KeyRune, Rune: ']', Label: "Next heading"})
+	r.Register(Action{ID: ActionPrevHeading, Key: tcell.KeyRune, Rune: '[', Label: "Prev heading"})
 	// Clone action removed - not yet implemented
 
 	return r
@@ -387,9 +409,16 @@ func PluginViewActions() *ActionRegistry {
 
 // DokiViewActions returns the action registry for doki (documentation) plugin views.
 // Doki views primarily handle navigation through the NavigableMarkdown component.
-func DokiViewActions() *ActionRegistry {
+// Search, backlinks and editing are only registered for views that show the doki tree.
+func DokiViewActions(searchable bool) *ActionRegistry {
 	r := NewActionRegistry()
 
+	if searchable {
+		r.Register(Action{ID: ActionSearch, Key: tcell.KeyRune, Rune: '/', Label: "Search", ShowInHeader: true})
+		r.Register(Action{ID: ActionBacklinks, Key: tcell.KeyRune, Rune: 'b', Label: "Backlinks", ShowInHeader: true})
+		r.Register(Action{ID: ActionEditSource, Key: tcell.KeyRune, Rune: 'e', Label: "Edit", ShowInHeader: true})
+	}
+
 	// Navigation actions (handled by the NavigableMarkdown component in the view)
 	// These are registered here for consistency, but actual handling is in the view
 	// Note: The navidown component supports both plain Left/Right and Alt+Left/Right
@@ -397,6 +426,34 @@ func DokiViewActions() *ActionRegistry {
 	r.Register(Action{ID: ActionNavigateBack, Key: tcell.KeyLeft, Label: "← Back", ShowInHeader: true})
 	r.Register(Action{ID: ActionNavigateForward, Key: tcell.KeyRight, Label: "Forward →", ShowInHeader: true})
 
+	// heading navigation (handled by the view through OutlineView)
+	r.Register(Action{ID: ActionOutline, Key: tcell.KeyRune, Rune: 'o', Label: "Outline", ShowInHeader: true})
+	r.Register(Action{ID: ActionNextHeading, Key: tcell.KeyRune, Rune: ']', Label: "Next heading"})
+	r.Register(Action{ID: ActionPrevHeading, Key: tcell.KeyRune, Rune: '[', Label: "Prev heading"})
+
+	// find in page (handled by the view through FindView)
+	r.Register(Action{ID: ActionFind, Key: tcell.KeyRune, Rune: 'f', Label: "Find", ShowInHeader: true})
+	r.Register(Action{ID: ActionFindNext, Key: tcell.KeyRune, Rune: 'n', Label: "Next match"})
+	r.Register(Action{ID: ActionFindPrev, Key: tcell.KeyRune, Rune: 'N', Label: "Prev match"})
+
+	// plugin activation keys are merged dynamically after plugins load
+	r.MergePluginActions()
+
+	return r
+}
+
+// ChartViewActions returns the action registry for chart (analytics) plugin views.
+// Window keys are only registered for charts with a selectable time window.
+func ChartViewActions(windowed bool) *ActionRegistry {
+	r := NewActionRegistry()
+
+	if windowed {
+		r.Register(Action{ID: ActionWindowShorter, Key: tcell.KeyRune, Rune: '[', Label: "Shorter", ShowInHeader: true})
+		r.Register(Action{ID: ActionWindowLonger, Key: tcell.KeyRune, Rune: ']', Label: "Longer", ShowInHeader: true})
+		r.Register(Action{ID: ActionWindowShorter, Key: tcell.KeyLeft, Label: "←"})
+		r.Register(Action{ID: ActionWindowLonger, Key: tcell.KeyRight, Label: "→"})
+	}
+
 	// plugin activation keys are merged dynamically after plugins load
 	r.MergePluginActions()
 
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin"
+)
+
+// ChartController handles chart plugin view actions (time window selection).
+// Only the cumulative flow diagram has a selectable window; burndown and velocity
+// charts span the sprints from config.
+// Chart views are read-only; the controller only mutates the shared ChartConfig.
+type ChartController struct {
+	pluginDef     *plugin.ChartPlugin
+	chartConfig   *model.ChartConfig
+	navController *NavigationController
+	registry      *ActionRegistry
+}
+
+// NewChartController creates a chart controller
+func NewChartController(
+	pluginDef *plugin.ChartPlugin,
+	navController *NavigationController,
+) *ChartController {
+	return &ChartController{
+		pluginDef:     pluginDef,
+		chartConfig:   model.NewChartConfig(pluginDef.Name, pluginDef.Window),
+		navController: navController,
+		registry:      ChartViewActions(pluginDef.Chart == "cfd"),
+	}
+}
+
+// GetActionRegistry returns the actions for the chart view
+func (cc *ChartController) GetActionRegistry() *ActionRegistry {
+	return cc.registry
+}
+
+// GetPluginName returns the plugin name
+func (cc *ChartController) GetPluginName() string {
+	return cc.pluginDef.Name
+}
+
+// GetChartConfig returns the chart state shared with the view
+func (cc *ChartController) GetChartConfig() *model.ChartConfig {
+	return cc.chartConfig
+}
+
+// HandleAction processes a chart action
+func (cc *ChartController) HandleAction(actionID ActionID) bool {
+	switch actionID {
+	case ActionWindowShorter:
+		cc.chartConfig.CycleWindow(-1)
+		return true
+	case ActionWindowLonger:
+		cc.chartConfig.CycleWindow(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// HandleSearch is not applicable for chart plugins
+func (cc *ChartController) HandleSearch(query string) {
+	// No-op: chart plugins don't support search
+}
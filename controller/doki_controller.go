@@ -1,29 +1,56 @@
 package controller
 
 import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/doki"
+	"github.com/boolean-maybe/tiki/model"
 	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/store"
 )
 
+// fileStager is implemented by stores that can stage files outside the task directory
+type fileStager interface {
+	StageFile(path string) error
+}
+
+// dokiSearchLimit caps the number of hits listed for a documentation search
+const dokiSearchLimit = 200
+
 // DokiController handles doki plugin view actions (documentation/markdown navigation).
 // DokiPlugins are read-only documentation views and don't need task filtering/sorting.
 type DokiController struct {
 	pluginDef     *plugin.DokiPlugin
 	navController *NavigationController
+	taskStore     store.Store
 	registry      *ActionRegistry
+	search        *model.DokiSearch
 }
 
 // NewDokiController creates a doki controller
 func NewDokiController(
 	pluginDef *plugin.DokiPlugin,
 	navController *NavigationController,
+	taskStore store.Store,
 ) *DokiController {
 	return &DokiController{
 		pluginDef:     pluginDef,
 		navController: navController,
-		registry:      DokiViewActions(),
+		taskStore:     taskStore,
+		registry:      DokiViewActions(isSearchableDoki(pluginDef)),
+		search:        model.NewDokiSearch(pluginDef.Name),
 	}
 }
 
+// isSearchableDoki reports whether a doki plugin shows the doki tree, which full-text search covers
+func isSearchableDoki(pluginDef *plugin.DokiPlugin) bool {
+	return pluginDef.Fetcher == "file"
+}
+
 // GetActionRegistry returns the actions for the doki view
 func (dc *DokiController) GetActionRegistry() *ActionRegistry {
 	return dc.registry
@@ -34,6 +61,11 @@ func (dc *DokiController) GetPluginName() string {
 	return dc.pluginDef.Name
 }
 
+// GetSearch returns the documentation search state shared with the view
+func (dc *DokiController) GetSearch() *model.DokiSearch {
+	return dc.search
+}
+
 // HandleAction processes a doki action
 // Note: Most doki actions (Tab, Shift+Tab, Alt+Left, Alt+Right) are handled
 // directly by the NavigableMarkdown component in the view. The controller
@@ -46,12 +78,73 @@ func (dc *DokiController) HandleAction(actionID ActionID) bool {
 	case ActionNavigateForward:
 		// Let the view's NavigableMarkdown component handle this
 		return false
+	case ActionBacklinks:
+		dc.showBacklinks()
+		return true
+	case ActionEditSource:
+		return dc.editPage()
 	default:
 		return false
 	}
 }
 
-// HandleSearch is not applicable for DokiPlugins (documentation views don't have search)
+// HandleSearch runs a full-text search across every markdown file in the doki directory.
+// The index is rebuilt per query so edits made outside tiki are always found.
 func (dc *DokiController) HandleSearch(query string) {
-	// No-op: Doki plugins don't support search
+	query = strings.TrimSpace(query)
+	if query == "" || !isSearchableDoki(dc.pluginDef) {
+		return
+	}
+
+	idx, err := doki.BuildIndex(config.GetDokiDir())
+	if err != nil {
+		dc.search.SetResults(query, nil, err)
+		return
+	}
+	dc.search.SetResults(query, idx.Search(query, dokiSearchLimit), nil)
+}
+
+// showBacklinks lists the pages in the doki directory that link to the page on display
+func (dc *DokiController) showBacklinks() {
+	page := dc.search.GetPage()
+	if page == "" || !isSearchableDoki(dc.pluginDef) {
+		return
+	}
+
+	idx, err := doki.BuildIndex(config.GetDokiDir())
+	if err != nil {
+		dc.search.SetBacklinks(page, nil, err)
+		return
+	}
+	dc.search.SetBacklinks(page, idx.Backlinks(page), nil)
+}
+
+// editPage opens the page on display in $EDITOR, creating its directory for pages
+// that don't exist yet, then stages the file in git and has the view reload it.
+func (dc *DokiController) editPage() bool {
+	page := dc.search.GetPage()
+	if page == "" || !isSearchableDoki(dc.pluginDef) ||
+		strings.HasPrefix(page, "http://") || strings.HasPrefix(page, "https://") {
+		return false
+	}
+
+	//nolint:gosec // G301: 0755 is appropriate for doki directories
+	if err := os.MkdirAll(filepath.Dir(page), 0755); err != nil {
+		slog.Error("failed to create doki directory", "path", page, "error", err)
+		return true
+	}
+
+	dc.navController.SuspendAndEdit(page)
+
+	if _, err := os.Stat(page); err != nil {
+		// editor closed without saving a new page
+		return true
+	}
+	if stager, ok := dc.taskStore.(fileStager); ok {
+		if err := stager.StageFile(page); err != nil {
+			slog.Warn("failed to git add doki page", "path", page, "error", err)
+		}
+	}
+	dc.search.PageEdited()
+	return true
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	"log/slog"
 
+	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/model"
 	"github.com/boolean-maybe/tiki/store"
 
@@ -42,7 +43,7 @@ func NewInputRouter(
 	pluginControllers map[string]PluginControllerInterface,
 	taskStore store.Store,
 ) *InputRouter {
-	return &InputRouter{
+	ir := &InputRouter{
 		navController:     navController,
 		taskController:    taskController,
 		taskEditCoord:     NewTaskEditCoordinator(navController, taskController),
@@ -50,16 +51,23 @@ func NewInputRouter(
 		globalActions:     DefaultGlobalActions(),
 		taskStore:         taskStore,
 	}
+	for _, controller := range pluginControllers {
+		if pc, ok := controller.(interface{ SetTemplatePrompter(TemplatePrompter) }); ok {
+			pc.SetTemplatePrompter(ir.promptTemplateFields)
+		}
+	}
+	return ir
 }
 
 // HandleInput processes a key event for the current view and routes it to the appropriate handler.
 // It processes events through multiple handlers in order:
 // 1. Search input (if search is active)
 // 2. Fullscreen escape (Esc key in fullscreen views)
-// 3. Inline editors (title/description editing)
-// 4. Task edit field focus (field navigation)
-// 5. Global actions (Esc, Refresh)
-// 6. View-specific actions (based on current view)
+// 3. History escape (Esc key while the history pane is open)
+// 4. Inline editors (title/description editing)
+// 5. Task edit field focus (field navigation)
+// 6. Global actions (Esc, Refresh)
+// 7. View-specific actions (based on current view)
 // Returns true if the event was handled, false otherwise.
 func (ir *InputRouter) HandleInput(event *tcell.EventKey, currentView *ViewEntry) bool {
 	slog.Debug("input received", "name", event.Name(), "key", int(event.Key()), "rune", string(event.Rune()), "modifiers", int(event.Modifiers()))
@@ -80,9 +88,21 @@ func (ir *InputRouter) HandleInput(event *tcell.EventKey, currentView *ViewEntry
 	if stop, handled := ir.maybeHandleSearchInput(activeView, event); stop {
 		return handled
 	}
+	if stop, handled := ir.maybeHandleFindInput(activeView, event); stop {
+		return handled
+	}
 	if stop, handled := ir.maybeHandleFullscreenEscape(activeView, event); stop {
 		return handled
 	}
+	if stop, handled := ir.maybeHandleHistoryEscape(activeView, event); stop {
+		return handled
+	}
+	if stop, handled := ir.maybeHandleOutlineEscape(activeView, event); stop {
+		return handled
+	}
+	if stop, handled := ir.maybeHandleTemplatePicker(activeView, event); stop {
+		return handled
+	}
 	if stop, handled := ir.maybeHandleInlineEditors(activeView, isTaskEditView, event); stop {
 		return handled
 	}
@@ -129,6 +149,23 @@ func (ir *InputRouter) maybeHandleSearchInput(activeView View, event *tcell.Even
 	return false, false
 }
 
+// maybeHandleFindInput lets the find box take its keys and closes find on Esc.
+func (ir *InputRouter) maybeHandleFindInput(activeView View, event *tcell.EventKey) (stop bool, handled bool) {
+	findView, ok := activeView.(FindView)
+	if !ok {
+		return false, false
+	}
+	if findView.IsFindBoxFocused() {
+		// Find box has focus and handles input through tview.
+		return true, false
+	}
+	if findView.IsFindActive() && event.Key() == tcell.KeyEscape {
+		findView.HideFind()
+		return true, true
+	}
+	return false, false
+}
+
 // maybeHandleFullscreenEscape exits fullscreen before bubbling Esc to global handler.
 func (ir *InputRouter) maybeHandleFullscreenEscape(activeView View, event *tcell.EventKey) (stop bool, handled bool) {
 	fullscreenView, ok := activeView.(FullscreenView)
@@ -142,6 +179,19 @@ func (ir *InputRouter) maybeHandleFullscreenEscape(activeView View, event *tcell
 	return false, false
 }
 
+// maybeHandleHistoryEscape closes the history pane before bubbling Esc to global handler.
+func (ir *InputRouter) maybeHandleHistoryEscape(activeView View, event *tcell.EventKey) (stop bool, handled bool) {
+	historyView, ok := activeView.(HistoryView)
+	if !ok {
+		return false, false
+	}
+	if historyView.IsHistoryVisible() && event.Key() == tcell.KeyEscape {
+		historyView.HideHistory()
+		return true, true
+	}
+	return false, false
+}
+
 // maybeHandleInlineEditors handles focused title/description editors (and their cancel semantics).
 func (ir *InputRouter) maybeHandleInlineEditors(activeView View, isTaskEditView bool, event *tcell.EventKey) (stop bool, handled bool) {
 	// Only TaskEditView has inline editors now
@@ -176,6 +226,123 @@ func (ir *InputRouter) maybeHandleTaskEditFieldFocus(activeView View, isTaskEdit
 	return false, false
 }
 
+// maybeHandleOutlineEscape closes the outline before bubbling Esc to global handler.
+func (ir *InputRouter) maybeHandleOutlineEscape(activeView View, event *tcell.EventKey) (stop bool, handled bool) {
+	outlineView, ok := activeView.(OutlineView)
+	if !ok {
+		return false, false
+	}
+	if outlineView.IsOutlineVisible() && event.Key() == tcell.KeyEscape {
+		outlineView.HideOutline()
+		return true, true
+	}
+	return false, false
+}
+
+// maybeHandleTemplatePicker lets the template list take its keys and closes it on Esc.
+func (ir *InputRouter) maybeHandleTemplatePicker(activeView View, event *tcell.EventKey) (stop bool, handled bool) {
+	pickerView, ok := activeView.(TemplatePickerView)
+	if !ok || !pickerView.IsTemplatePickerVisible() {
+		return false, false
+	}
+	if event.Key() == tcell.KeyEscape {
+		pickerView.HideTemplatePicker()
+		ir.navController.GetApp().SetFocus(pickerView.GetPrimitive())
+		return true, true
+	}
+	// the list has focus and handles input through tview
+	return true, false
+}
+
+// handleNewFromTemplateAction lists the task templates in the active view; picking one
+// starts a new tiki from it through the plugin controller.
+func (ir *InputRouter) handleNewFromTemplateAction(controller PluginControllerInterface) bool {
+	pickerView, ok := ir.navController.GetActiveView().(TemplatePickerView)
+	if !ok {
+		return false
+	}
+	creator, ok := controller.(interface{ HandleNewFromTemplate(string) bool })
+	if !ok {
+		return false
+	}
+
+	app := ir.navController.GetApp()
+	list := pickerView.ShowTemplatePicker(config.ListTaskTemplates(), func(name string) {
+		pickerView.HideTemplatePicker()
+		app.SetFocus(pickerView.GetPrimitive())
+		creator.HandleNewFromTemplate(name)
+	})
+	if list != nil {
+		app.SetFocus(list)
+	}
+	return true
+}
+
+// promptTemplateFields asks for a template's prompt fields in the active view. Without a
+// view that can ask, the fields are left empty.
+func (ir *InputRouter) promptTemplateFields(template string, fields []string, done func(values map[string]string)) {
+	pickerView, ok := ir.navController.GetActiveView().(TemplatePickerView)
+	if !ok {
+		done(nil)
+		return
+	}
+	app := ir.navController.GetApp()
+	form := pickerView.ShowTemplatePrompts(template, fields, func(values map[string]string) {
+		pickerView.HideTemplatePicker()
+		app.SetFocus(pickerView.GetPrimitive())
+		done(values)
+	})
+	if form != nil {
+		app.SetFocus(form)
+	}
+}
+
+// handleOutlineAction handles heading navigation for views showing a markdown document.
+// Returns false for other actions or views without headings.
+func (ir *InputRouter) handleOutlineAction(actionID ActionID) bool {
+	outlineView, ok := ir.navController.GetActiveView().(OutlineView)
+	if !ok {
+		return false
+	}
+	switch actionID {
+	case ActionOutline:
+		outlineView.ToggleOutline()
+	case ActionNextHeading:
+		outlineView.NextHeading()
+	case ActionPrevHeading:
+		outlineView.PreviousHeading()
+	default:
+		return false
+	}
+	return true
+}
+
+// handleFindAction handles find in page for views showing a markdown document.
+// Returns false for other actions or views that can't find.
+func (ir *InputRouter) handleFindAction(actionID ActionID) bool {
+	findView, ok := ir.navController.GetActiveView().(FindView)
+	if !ok {
+		return false
+	}
+	switch actionID {
+	case ActionFind:
+		app := ir.navController.GetApp()
+		findView.SetFocusSetter(func(p tview.Primitive) {
+			app.SetFocus(p)
+		})
+		if findBox := findView.ShowFind(); findBox != nil {
+			app.SetFocus(findBox)
+		}
+	case ActionFindNext:
+		findView.NextMatch()
+	case ActionFindPrev:
+		findView.PreviousMatch()
+	default:
+		return false
+	}
+	return true
+}
+
 // handlePluginInput routes input to the appropriate plugin controller
 func (ir *InputRouter) handlePluginInput(event *tcell.EventKey, viewID model.ViewID) bool {
 	pluginName := model.GetPluginName(viewID)
@@ -191,6 +358,9 @@ func (ir *InputRouter) handlePluginInput(event *tcell.EventKey, viewID model.Vie
 		if action.ID == ActionSearch {
 			return ir.handleSearchAction(controller)
 		}
+		if action.ID == ActionNewFromTemplate {
+			return ir.handleNewFromTemplateAction(controller)
+		}
 		// Handle plugin activation keys - switch to different plugin
 		if targetPluginName := GetPluginNameFromAction(action.ID); targetPluginName != "" {
 			targetViewID := model.MakePluginViewID(targetPluginName)
@@ -200,6 +370,9 @@ func (ir *InputRouter) handlePluginInput(event *tcell.EventKey, viewID model.Vie
 			}
 			return true // already on this plugin, consume the event
 		}
+		if ir.handleOutlineAction(action.ID) || ir.handleFindAction(action.ID) {
+			return true
+		}
 		return controller.HandleAction(action.ID)
 	}
 	return false
@@ -285,6 +458,15 @@ func (ir *InputRouter) handleTaskInput(event *tcell.EventKey, params map[string]
 				return true
 			}
 			return false
+		case ActionHistory:
+			activeView := ir.navController.GetActiveView()
+			if historyView, ok := activeView.(HistoryView); ok {
+				historyView.ToggleHistory()
+				return true
+			}
+			return false
+		case ActionOutline, ActionNextHeading, ActionPrevHeading:
+			return ir.handleOutlineAction(action.ID)
 		default:
 			return ir.taskController.HandleAction(action.ID)
 		}
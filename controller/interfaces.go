@@ -88,6 +88,102 @@ type FullscreenView interface {
 	IsFullscreen() bool
 }
 
+// HistoryView is a view that can show the change history of its task
+type HistoryView interface {
+	View
+
+	// ToggleHistory shows or hides the history pane
+	ToggleHistory()
+
+	// HideHistory closes the history pane
+	HideHistory()
+
+	// IsHistoryVisible reports whether the history pane is currently shown
+	IsHistoryVisible() bool
+}
+
+// OutlineView is a view showing a markdown document that can be navigated by heading
+type OutlineView interface {
+	View
+
+	// ToggleOutline shows or hides the list of headings
+	ToggleOutline()
+
+	// HideOutline closes the list of headings
+	HideOutline()
+
+	// IsOutlineVisible reports whether the list of headings is currently shown
+	IsOutlineVisible() bool
+
+	// NextHeading scrolls to the next heading
+	NextHeading()
+
+	// PreviousHeading scrolls to the previous heading
+	PreviousHeading()
+}
+
+// TemplatePickerView is a view that can offer the task templates to start a tiki from,
+// and ask for the values a template prompts for
+type TemplatePickerView interface {
+	View
+
+	// ShowTemplatePicker lists the template names and returns the primitive to focus.
+	// choose is called with the name picked. An empty list says there are no templates.
+	ShowTemplatePicker(names []string, choose func(name string)) tview.Primitive
+
+	// ShowTemplatePrompts asks for the values of a template's prompt fields and returns the
+	// primitive to focus. submit is called with the values, keyed by field.
+	ShowTemplatePrompts(template string, fields []string, submit func(values map[string]string)) tview.Primitive
+
+	// HideTemplatePicker closes the list of templates or the prompt fields
+	HideTemplatePicker()
+
+	// IsTemplatePickerVisible reports whether the list of templates or the prompt fields are shown
+	IsTemplatePickerVisible() bool
+}
+
+// FindView is a view that can find text in the markdown document it shows
+type FindView interface {
+	View
+
+	// ShowFind displays the find box and returns the primitive to focus
+	ShowFind() tview.Primitive
+
+	// HideFind closes the find box and clears the highlighted matches
+	HideFind()
+
+	// IsFindActive reports whether the find box or highlighted matches are shown
+	IsFindActive() bool
+
+	// IsFindBoxFocused returns whether the find box currently has focus
+	IsFindBoxFocused() bool
+
+	// NextMatch scrolls to the next match
+	NextMatch()
+
+	// PreviousMatch scrolls to the previous match
+	PreviousMatch()
+
+	// SetFocusSetter sets the callback for requesting focus changes
+	SetFocusSetter(setter func(p tview.Primitive))
+}
+
+// TaskLinkView is a view that renders task references as links
+type TaskLinkView interface {
+	View
+
+	// SetTaskLinkHandler sets the callback for following a link to a task
+	SetTaskLinkHandler(handler func(taskID string))
+}
+
+// DokiLinkView is a view that renders links to doki pages
+type DokiLinkView interface {
+	View
+
+	// SetDokiLinkHandler sets the callback for following a link to a markdown file
+	SetDokiLinkHandler(handler func(path, anchor string))
+}
+
 // FullscreenChangeNotifier is a view that notifies when fullscreen state changes
 type FullscreenChangeNotifier interface {
 	// SetFullscreenChangeHandler sets the callback for when fullscreen state changes
@@ -20,8 +20,13 @@ type PluginController struct {
 	pluginDef     *plugin.TikiPlugin
 	navController *NavigationController
 	registry      *ActionRegistry
+	prompter      TemplatePrompter
 }
 
+// TemplatePrompter asks for the values of a template's prompt fields, then calls done
+// with them. done isn't called when the user cancels.
+type TemplatePrompter func(template string, fields []string, done func(values map[string]string))
+
 // NewPluginController creates a plugin controller
 func NewPluginController(
 	taskStore store.Store,
@@ -58,6 +63,9 @@ func NewPluginController(
 		})
 	}
 
+	// templates are listed when the picker opens, so ones added while tiki runs show up
+	pc.registry.Register(Action{ID: ActionNewFromTemplate, Key: tcell.KeyRune, Rune: 'N', Label: "New from template", ShowInHeader: true})
+
 	return pc
 }
 
@@ -188,10 +196,41 @@ func (pc *PluginController) handleLaneSwitch(direction string) bool {
 	return false
 }
 
+// handleNewTask starts a new tiki from the template of the selected lane, or new.md
 func (pc *PluginController) handleNewTask() bool {
-	task, err := pc.taskStore.NewTaskTemplate()
+	template := ""
+	if lane := pc.pluginConfig.GetSelectedLane(); lane >= 0 && lane < len(pc.pluginDef.Lanes) {
+		template = pc.pluginDef.Lanes[lane].Template
+	}
+	return pc.HandleNewFromTemplate(template)
+}
+
+// SetTemplatePrompter sets how the values of template prompt fields are asked for
+func (pc *PluginController) SetTemplatePrompter(prompter TemplatePrompter) {
+	pc.prompter = prompter
+}
+
+// HandleNewFromTemplate starts a new tiki draft from the named template (new.md when empty),
+// asking for the template's prompt fields first if it has any
+func (pc *PluginController) HandleNewFromTemplate(template string) bool {
+	fields, err := pc.taskStore.TemplatePrompts(template)
 	if err != nil {
-		slog.Error("failed to create task template", "error", err)
+		slog.Error("failed to read task template", "template", template, "error", err)
+		return false
+	}
+	if len(fields) == 0 || pc.prompter == nil {
+		return pc.startDraft(template, nil)
+	}
+	pc.prompter(template, fields, func(values map[string]string) {
+		pc.startDraft(template, values)
+	})
+	return true
+}
+
+func (pc *PluginController) startDraft(template string, values map[string]string) bool {
+	task, err := pc.taskStore.NewTaskFromTemplate(template, values)
+	if err != nil {
+		slog.Error("failed to create task template", "template", template, "error", err)
 		return false
 	}
 
@@ -200,7 +239,7 @@ func (pc *PluginController) handleNewTask() bool {
 		Draft:  task,
 		Focus:  model.EditFieldTitle,
 	}))
-	slog.Info("new tiki draft started from plugin", "task_id", task.ID, "plugin", pc.pluginDef.Name)
+	slog.Info("new tiki draft started from plugin", "task_id", task.ID, "plugin", pc.pluginDef.Name, "template", template)
 	return true
 }
 
@@ -351,14 +390,8 @@ func (pc *PluginController) GetFilteredTasksForLane(lane int) []*task.Task {
 	// Get current user for "my tasks" type filters
 	currentUser := getCurrentUserName(pc.taskStore)
 
-	// Apply filter
-	var filtered []*task.Task
-	for _, task := range allTasks {
-		laneFilter := pc.pluginDef.Lanes[lane].Filter
-		if laneFilter == nil || laneFilter.Evaluate(task, now, currentUser) {
-			filtered = append(filtered, task)
-		}
-	}
+	// Apply lane filter and sort
+	filtered := pc.pluginDef.LaneTasks(lane, allTasks, now, currentUser)
 
 	if searchResults != nil {
 		searchTaskMap := make(map[string]bool, len(searchResults))
@@ -368,11 +401,6 @@ func (pc *PluginController) GetFilteredTasksForLane(lane int) []*task.Task {
 		filtered = filterTasksBySearch(filtered, searchTaskMap)
 	}
 
-	// Apply sort
-	if len(pc.pluginDef.Sort) > 0 {
-		plugin.SortTasks(filtered, pc.pluginDef.Sort)
-	}
-
 	return filtered
 }
 
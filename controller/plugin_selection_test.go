@@ -262,3 +262,14 @@ func TestLaneSwitchClampsScrollOffsetToTaskCount(t *testing.T) {
 		t.Errorf("expected selection clamped to valid range [0,2], got %d", selectedIdx)
 	}
 }
+
+// templates are listed when the picker opens, so the action is there before any exist
+func TestPluginControllerOffersNewFromTemplate(t *testing.T) {
+	pluginDef := &plugin.TikiPlugin{BasePlugin: plugin.BasePlugin{Name: "TestPlugin"}}
+	pc := NewPluginController(store.NewInMemoryStore(), model.NewPluginConfig("TestPlugin"), pluginDef, nil)
+
+	action, ok := pc.GetActionRegistry().LookupRune('N')
+	if !ok || action.ID != ActionNewFromTemplate {
+		t.Errorf("N = %+v, %v; want New from template", action, ok)
+	}
+}
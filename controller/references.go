@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"log/slog"
+
+	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin"
+)
+
+// ReferenceController follows cross-references between tikis and doki pages.
+// A task reference opens the task detail view; a doki page opens in the first
+// doki plugin that shows the doki tree.
+type ReferenceController struct {
+	navController *NavigationController
+	dokiPlugin    string // empty when no doki plugin shows the doki tree
+}
+
+// NewReferenceController creates a reference controller for the configured plugins
+func NewReferenceController(navController *NavigationController, plugins []plugin.Plugin) *ReferenceController {
+	rc := &ReferenceController{navController: navController}
+	for _, p := range plugins {
+		if dp, ok := p.(*plugin.DokiPlugin); ok && isSearchableDoki(dp) {
+			rc.dokiPlugin = dp.Name
+			break
+		}
+	}
+	return rc
+}
+
+// OpenTask pushes the task detail view for taskID
+func (rc *ReferenceController) OpenTask(taskID string) {
+	if taskID == "" {
+		return
+	}
+	rc.navController.PushView(model.TaskDetailViewID, model.EncodeTaskDetailParams(model.TaskDetailParams{
+		TaskID: taskID,
+	}))
+}
+
+// OpenDokiPage pushes the doki view at the markdown file path, scrolled to anchor
+func (rc *ReferenceController) OpenDokiPage(path, anchor string) {
+	if rc.dokiPlugin == "" {
+		slog.Warn("no doki plugin to open page in", "path", path)
+		return
+	}
+	rc.navController.PushView(model.MakePluginViewID(rc.dokiPlugin), model.EncodeDokiParams(model.DokiParams{
+		Page:   path,
+		Anchor: anchor,
+	}))
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin"
+)
+
+func TestReferenceController_OpenTask(t *testing.T) {
+	navController := NewNavigationController(nil)
+	rc := NewReferenceController(navController, nil)
+
+	rc.OpenTask("TIKI-ABC123")
+
+	if navController.CurrentViewID() != model.TaskDetailViewID {
+		t.Fatalf("expected task detail view, got %s", navController.CurrentViewID())
+	}
+	if id := model.DecodeTaskDetailParams(navController.CurrentView().Params).TaskID; id != "TIKI-ABC123" {
+		t.Errorf("expected task TIKI-ABC123, got %q", id)
+	}
+}
+
+func TestReferenceController_OpenDokiPage(t *testing.T) {
+	plugins := []plugin.Plugin{
+		&plugin.DokiPlugin{BasePlugin: plugin.BasePlugin{Name: "Help"}, Fetcher: "internal"},
+		&plugin.DokiPlugin{BasePlugin: plugin.BasePlugin{Name: "Docs"}, Fetcher: "file"},
+	}
+	navController := NewNavigationController(nil)
+	rc := NewReferenceController(navController, plugins)
+
+	rc.OpenDokiPage("/repo/.doc/doki/doc/config.md", "sprints")
+
+	if navController.CurrentViewID() != model.MakePluginViewID("Docs") {
+		t.Fatalf("expected the file doki view, got %s", navController.CurrentViewID())
+	}
+	params := model.DecodeDokiParams(navController.CurrentView().Params)
+	if params.Page != "/repo/.doc/doki/doc/config.md" || params.Anchor != "sprints" {
+		t.Errorf("unexpected doki params %+v", params)
+	}
+}
+
+func TestReferenceController_OpenDokiPageWithoutDokiPlugin(t *testing.T) {
+	navController := NewNavigationController(nil)
+	rc := NewReferenceController(navController, nil)
+
+	rc.OpenDokiPage("/repo/.doc/doki/index.md", "")
+
+	if navController.Depth() != 0 {
+		t.Errorf("expected no navigation, depth %d", navController.Depth())
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -16,6 +17,18 @@ import (
 
 // TaskController handles task detail actions: editing, status changes, comments.
 
+// taskFileLocator is implemented by stores that know the file of each task
+type taskFileLocator interface {
+	TaskFilePath(id string) string
+}
+
+// taskSourceEditor is implemented by stores that don't keep tasks in files: a task is
+// edited as the markdown of a tiki file and saved back
+type taskSourceEditor interface {
+	TaskSource(id string) (string, error)
+	SetTaskSource(id, content string) error
+}
+
 // TaskController handles task detail view actions
 type TaskController struct {
 	taskStore     store.Store
@@ -200,9 +213,18 @@ func (tc *TaskController) handleEditSource() bool {
 		return false
 	}
 
+	if editor, ok := tc.taskStore.(taskSourceEditor); ok {
+		tc.editTaskSource(editor, task.ID)
+		return true
+	}
+
 	// Construct the file path for this task
 	filename := strings.ToLower(task.ID) + ".md"
 	filePath := filepath.Join(config.GetTaskDir(), filename)
+	if locator, ok := tc.taskStore.(taskFileLocator); ok {
+		// stores over several directories know which one holds the task
+		filePath = locator.TaskFilePath(task.ID)
+	}
 
 	// Suspend the tview app and open the editor
 	tc.navController.SuspendAndEdit(filePath)
@@ -214,6 +236,45 @@ func (tc *TaskController) handleEditSource() bool {
 	return true
 }
 
+// editTaskSource opens a task of a store without files in the editor as a temporary tiki
+// file, and saves it back when it changed
+func (tc *TaskController) editTaskSource(editor taskSourceEditor, taskID string) {
+	source, err := editor.TaskSource(taskID)
+	if err != nil {
+		slog.Error("failed to read task source", "task_id", taskID, "error", err)
+		return
+	}
+	file, err := os.CreateTemp("", strings.ToLower(taskID)+"-*.md")
+	if err != nil {
+		slog.Error("failed to create temporary task file", "task_id", taskID, "error", err)
+		return
+	}
+	path := file.Name()
+	defer func() { _ = os.Remove(path) }()
+	_, err = file.WriteString(source)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		slog.Error("failed to write temporary task file", "path", path, "error", err)
+		return
+	}
+
+	tc.navController.SuspendAndEdit(path)
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("failed to read edited task file", "path", path, "error", err)
+		return
+	}
+	if string(edited) == source {
+		return
+	}
+	if err := editor.SetTaskSource(taskID, string(edited)); err != nil {
+		slog.Error("failed to save edited task", "task_id", taskID, "error", err)
+	}
+}
+
 // SaveTitle saves the new title to the current task (draft or editing).
 // For draft tasks (new task creation), updates the draft; for editing tasks, updates the editing copy.
 // Returns true if a task was updated, false if no task is being edited.
@@ -0,0 +1,89 @@
+package doki
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Report lists the broken links found across the doki tree and tiki descriptions.
+type Report struct {
+	Dokis    int
+	Tikis    int
+	Problems []Problem
+}
+
+// Doctor checks every link in the markdown files under dokiDir and in the task files
+// in taskDir. Task frontmatter is skipped; line numbers refer to the whole file.
+func Doctor(dokiDir, taskDir string) (*Report, error) {
+	report := &Report{}
+	checker := NewChecker([]string{dokiDir})
+
+	if _, err := os.Stat(dokiDir); err == nil {
+		idx, err := BuildIndex(dokiDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range idx.Documents {
+			report.Problems = append(report.Problems, checker.Check(doc.Path, idx.AbsPath(doc), doc)...)
+		}
+		report.Dokis = len(idx.Documents)
+	}
+
+	taskFiles, err := filepath.Glob(filepath.Join(taskDir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("listing task files: %w", err)
+	}
+	for _, path := range taskFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		name := filepath.Base(path)
+		doc := ParseDocument(name, blankFrontmatter(string(content)))
+		report.Problems = append(report.Problems, checker.Check(filepath.ToSlash(filepath.Join(filepath.Base(taskDir), name)), path, doc)...)
+	}
+	report.Tikis = len(taskFiles)
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		if report.Problems[i].Source != report.Problems[j].Source {
+			return report.Problems[i].Source < report.Problems[j].Source
+		}
+		return report.Problems[i].Line < report.Problems[j].Line
+	})
+	return report, nil
+}
+
+// String formats the report as one line per broken link followed by a summary.
+func (r *Report) String() string {
+	var sb strings.Builder
+	for _, p := range r.Problems {
+		fmt.Fprintf(&sb, "%s:%d: %s: %s\n", p.Source, p.Line, p.Target, p.Reason)
+	}
+	if len(r.Problems) > 0 {
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "checked %d dokis and %d tikis: %d broken links\n", r.Dokis, r.Tikis, len(r.Problems))
+	return sb.String()
+}
+
+// blankFrontmatter replaces YAML frontmatter with empty lines so that line numbers
+// still match the file while its values are not mistaken for markdown.
+func blankFrontmatter(content string) string {
+	if !strings.HasPrefix(content, "---") {
+		return content
+	}
+	end := strings.Index(content[3:], "\n---")
+	if end == -1 {
+		return content
+	}
+	end += 3 + len("\n---")
+	if nl := strings.IndexByte(content[end:], '\n'); nl >= 0 {
+		end += nl
+	} else {
+		end = len(content)
+	}
+	return strings.Repeat("\n", strings.Count(content[:end], "\n")) + content[end:]
+}
@@ -0,0 +1,143 @@
+package doki
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/boolean-maybe/navidown/loaders"
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+// RefReader reads files as of a git branch, tag or commit.
+type RefReader interface {
+	FileAtRef(ref string, filePath string) (string, error)
+}
+
+// GitProvider serves markdown from a git ref instead of the working tree, so docs of
+// another branch or a release tag can be browsed without checking it out.
+// Relative links resolve against the linking file, then against the search roots.
+type GitProvider struct {
+	Reader      RefReader
+	Ref         string
+	SearchRoots []string
+}
+
+// FetchContent implements navidown.ContentProvider. Web links are fetched as usual.
+func (p *GitProvider) FetchContent(elem nav.NavElement) (string, error) {
+	url := elem.URL
+	if url == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return (&loaders.FileHTTP{}).FetchContent(elem)
+	}
+	_, content, err := p.locate(url, elem.SourceFilePath)
+	return content, err
+}
+
+// ResolvePath returns the path under which a link target exists at the ref,
+// or the target itself when it doesn't.
+func (p *GitProvider) ResolvePath(url, sourcePath string) string {
+	resolved, _, err := p.locate(url, sourcePath)
+	if err != nil {
+		return url
+	}
+	return resolved
+}
+
+// locate tries the link target next to the source, then below each search root
+func (p *GitProvider) locate(url, sourcePath string) (string, string, error) {
+	if p.Reader == nil {
+		return "", "", errors.New("git is not available")
+	}
+	if strings.HasPrefix(filepath.ToSlash(filepath.Clean(url)), "../") {
+		return "", "", nav.ErrDirectoryTraversal
+	}
+
+	var candidates []string
+	if filepath.IsAbs(url) {
+		candidates = append(candidates, url)
+	} else {
+		if sourcePath != "" {
+			candidates = append(candidates, filepath.Join(filepath.Dir(sourcePath), url))
+		}
+		for _, root := range p.SearchRoots {
+			candidates = append(candidates, filepath.Join(root, url))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if content, err := p.Reader.FileAtRef(p.Ref, candidate); err == nil {
+			return candidate, content, nil
+		}
+	}
+	return "", "", fmt.Errorf("%s not found at %s: %w", url, p.Ref, nav.ErrFileNotFound)
+}
+
+// DirectoryIndex generates a table of contents for the markdown files below dir:
+// files directly in dir first, then one section per subdirectory. Links are relative
+// to dir and titled with each file's first heading.
+func DirectoryIndex(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+
+	idx, err := BuildIndex(dir)
+	if err != nil {
+		return "", err
+	}
+
+	groups := make(map[string][]*Document)
+	for _, doc := range idx.Documents {
+		group := path.Dir(doc.Path)
+		groups[group] = append(groups[group], doc)
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "." || names[j] == "." {
+			return names[i] == "."
+		}
+		return names[i] < names[j]
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", filepath.Base(filepath.Clean(dir)))
+	if len(idx.Documents) == 0 {
+		sb.WriteString("\nNo markdown files in this directory\n")
+	}
+	for _, name := range names {
+		if name != "." {
+			fmt.Fprintf(&sb, "\n## %s\n", name)
+		}
+		sb.WriteString("\n")
+		for _, doc := range groups[name] {
+			fmt.Fprintf(&sb, "- [%s](%s)\n", escapeLinkText(doc.Title), linkDestination(doc.Path))
+		}
+	}
+	return sb.String(), nil
+}
+
+// escapeLinkText keeps brackets in titles from ending the link text
+func escapeLinkText(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+// linkDestination wraps paths containing spaces or parentheses in angle brackets
+func linkDestination(p string) string {
+	if strings.ContainsAny(p, " ()") {
+		return "<" + p + ">"
+	}
+	return p
+}
@@ -0,0 +1,79 @@
+package doki
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+type fakeRefReader map[string]string
+
+func (f fakeRefReader) FileAtRef(ref string, filePath string) (string, error) {
+	if content, ok := f[ref+":"+filepath.ToSlash(filePath)]; ok {
+		return content, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestGitProvider(t *testing.T) {
+	reader := fakeRefReader{
+		"v1:docs/index.md":       "# Index",
+		"v1:docs/guide/start.md": "# Start",
+		"v2:docs/index.md":       "# Index v2",
+	}
+	p := &GitProvider{Reader: reader, Ref: "v1", SearchRoots: []string{"docs"}}
+
+	content, err := p.FetchContent(nav.NavElement{URL: "index.md"})
+	if err != nil || content != "# Index" {
+		t.Fatalf("root-relative fetch = %q, %v", content, err)
+	}
+	// relative to the linking file
+	content, err = p.FetchContent(nav.NavElement{URL: "start.md", SourceFilePath: "docs/guide/other.md"})
+	if err != nil || content != "# Start" {
+		t.Fatalf("source-relative fetch = %q, %v", content, err)
+	}
+	if got := p.ResolvePath("start.md", "docs/guide/other.md"); got != filepath.Join("docs", "guide", "start.md") {
+		t.Errorf("ResolvePath = %q", got)
+	}
+	if got := p.ResolvePath("missing.md", ""); got != "missing.md" {
+		t.Errorf("unresolved path should be returned as is, got %q", got)
+	}
+	if _, err := p.FetchContent(nav.NavElement{URL: "guide/start.md", SourceFilePath: "docs/index.md"}); err != nil {
+		t.Errorf("nested fetch failed: %v", err)
+	}
+	if _, err := p.FetchContent(nav.NavElement{URL: "../secret.md"}); !errors.Is(err, nav.ErrDirectoryTraversal) {
+		t.Errorf("expected traversal error, got %v", err)
+	}
+	if _, err := p.FetchContent(nav.NavElement{URL: "missing.md"}); !errors.Is(err, nav.ErrFileNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestDirectoryIndex(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"b.md":              "# Beta\n",
+		"a.md":              "no heading\n",
+		"meetings/q1.md":    "# Q1 [draft]\n",
+		"my notes/todo.md":  "# Todo\n",
+		"meetings/skip.txt": "not markdown",
+	})
+
+	got, err := DirectoryIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# " + filepath.Base(root) + "\n" +
+		"\n- [a](a.md)\n- [Beta](b.md)\n" +
+		"\n## meetings\n\n- [Q1 \\[draft\\]](meetings/q1.md)\n" +
+		"\n## my notes\n\n- [Todo](<my notes/todo.md>)\n"
+	if got != want {
+		t.Errorf("DirectoryIndex =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := DirectoryIndex(filepath.Join(root, "a.md")); err == nil {
+		t.Error("expected an error for a file")
+	}
+}
@@ -0,0 +1,191 @@
+// Package doki indexes the markdown documentation tree (doki) for search and link analysis.
+package doki
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is one indexed markdown file.
+type Document struct {
+	Path     string // slash-separated path relative to the index root
+	Title    string // first heading, or the file name without extension
+	Sections []Section
+	Links    []Link
+}
+
+// Section is the text under one heading. The part of a document before its first
+// heading is a section with an empty Heading.
+type Section struct {
+	Heading string
+	Slug    string // GitHub-compatible anchor of the heading
+	Level   int
+	Line    int // 1-based line of the heading (or 1 for the preamble)
+	Text    string
+}
+
+// Link is a markdown link found in a document.
+type Link struct {
+	Text   string
+	Target string // link destination as written
+	Line   int    // 1-based source line
+}
+
+// Index holds every markdown document under a root directory.
+type Index struct {
+	Root      string
+	Documents []*Document
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+)
+
+// BuildIndex walks root and indexes every .md file below it.
+func BuildIndex(root string) (*Index, error) {
+	idx := &Index{Root: root}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		idx.Documents = append(idx.Documents, ParseDocument(filepath.ToSlash(rel), string(content)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexing %s: %w", root, err)
+	}
+
+	sort.Slice(idx.Documents, func(i, j int) bool {
+		return idx.Documents[i].Path < idx.Documents[j].Path
+	})
+	return idx, nil
+}
+
+// ParseDocument splits markdown content into sections and collects its links.
+// Headings and links inside fenced code blocks are ignored.
+func ParseDocument(path, content string) *Document {
+	doc := &Document{Path: path}
+	current := Section{Line: 1}
+	var text []string
+	inFence := false
+	fence := ""
+
+	flush := func() {
+		current.Text = strings.TrimSpace(strings.Join(text, "\n"))
+		if current.Heading != "" || current.Text != "" {
+			doc.Sections = append(doc.Sections, current)
+		}
+		text = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+		trimmed := strings.TrimSpace(line)
+
+		if marker := fenceMarker(trimmed); marker != "" {
+			if !inFence {
+				inFence, fence = true, marker
+			} else if strings.HasPrefix(trimmed, fence) {
+				inFence = false
+			}
+			text = append(text, line)
+			continue
+		}
+		if inFence {
+			text = append(text, line)
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil && !strings.HasPrefix(line, "    ") {
+			flush()
+			current = Section{
+				Heading: m[2],
+				Slug:    Slug(m[2]),
+				Level:   len(m[1]),
+				Line:    lineNo,
+			}
+			if doc.Title == "" {
+				doc.Title = m[2]
+			}
+			continue
+		}
+
+		for _, m := range linkPattern.FindAllStringSubmatch(line, -1) {
+			doc.Links = append(doc.Links, Link{Text: m[1], Target: m[2], Line: lineNo})
+		}
+		text = append(text, line)
+	}
+	flush()
+
+	if doc.Title == "" {
+		doc.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return doc
+}
+
+// fenceMarker returns the fence (``` or ~~~) a line opens or closes, or "".
+func fenceMarker(trimmed string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// Slug converts heading text to the GitHub-compatible anchor used by the markdown viewer.
+// Example: "What's New?" → "whats-new"
+func Slug(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.TrimSpace(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(unicode.ToLower(r))
+		case r == ' ':
+			sb.WriteByte('-')
+		case r == '-' || r == '_':
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Document returns the document at a root-relative path, or nil.
+func (idx *Index) Document(path string) *Document {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, doc := range idx.Documents {
+		if doc.Path == path {
+			return doc
+		}
+	}
+	return nil
+}
+
+// AbsPath returns the filesystem path of a document.
+func (idx *Index) AbsPath(doc *Document) string {
+	return filepath.Join(idx.Root, filepath.FromSlash(doc.Path))
+}
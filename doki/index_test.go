@@ -0,0 +1,77 @@
+package doki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	content := "Intro text with a [link](other.md).\n\n" +
+		"# Getting Started\n\nInstall it.\n\n" +
+		"```bash\n# not a heading\n[not](a-link.md)\n```\n\n" +
+		"## What's New?\n\nSee [config](doc/config.md#sprints \"Sprints\").\n"
+
+	doc := ParseDocument("guide.md", content)
+
+	if doc.Title != "Getting Started" {
+		t.Errorf("Title = %q, want %q", doc.Title, "Getting Started")
+	}
+	if len(doc.Sections) != 3 {
+		t.Fatalf("len(Sections) = %d, want 3 (preamble + 2 headings)", len(doc.Sections))
+	}
+	if doc.Sections[0].Heading != "" || doc.Sections[0].Line != 1 {
+		t.Errorf("preamble section = %+v", doc.Sections[0])
+	}
+	whatsNew := doc.Sections[2]
+	if whatsNew.Slug != "whats-new" || whatsNew.Level != 2 {
+		t.Errorf("section = %q slug %q level %d, want whats-new level 2", whatsNew.Heading, whatsNew.Slug, whatsNew.Level)
+	}
+
+	if len(doc.Links) != 2 {
+		t.Fatalf("len(Links) = %d, want 2 (fenced link ignored): %+v", len(doc.Links), doc.Links)
+	}
+	if doc.Links[1].Target != "doc/config.md#sprints" {
+		t.Errorf("Links[1].Target = %q, want doc/config.md#sprints", doc.Links[1].Target)
+	}
+}
+
+func TestParseDocumentTitleFallback(t *testing.T) {
+	doc := ParseDocument("notes/todo.md", "just text\n")
+	if doc.Title != "todo" {
+		t.Errorf("Title = %q, want todo", doc.Title)
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"index.md":      "# Home\n\n[Config](doc/config.md)\n",
+		"doc/config.md": "# Config\n\n## Sprints\n\nDefine sprints here.\n",
+		"doc/image.png": "not markdown",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := BuildIndex(root)
+	if err != nil {
+		t.Fatalf("BuildIndex() error: %v", err)
+	}
+	if len(idx.Documents) != 2 {
+		t.Fatalf("len(Documents) = %d, want 2", len(idx.Documents))
+	}
+	doc := idx.Document("doc/config.md")
+	if doc == nil {
+		t.Fatal("Document(doc/config.md) = nil")
+	}
+	if got := idx.AbsPath(doc); got != filepath.Join(root, "doc", "config.md") {
+		t.Errorf("AbsPath() = %q", got)
+	}
+}
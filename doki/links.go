@@ -0,0 +1,189 @@
+package doki
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+// ResolvedLink is a link destination located on disk.
+type ResolvedLink struct {
+	Path     string // absolute file path; the source itself for same-page anchors
+	Fragment string // heading anchor without '#', if any
+	External bool   // URL with a scheme (http, mailto, ...), not resolved
+	Err      error  // resolution failure for local links
+}
+
+// ResolveLink resolves a link target written in sourcePath with the same rules as the
+// markdown viewer: absolute paths, then the source's directory, then the search roots.
+func ResolveLink(target, sourcePath string, searchRoots []string) ResolvedLink {
+	if isExternalLink(target) {
+		return ResolvedLink{External: true}
+	}
+
+	path, fragment, _ := strings.Cut(target, "#")
+	if path == "" {
+		return ResolvedLink{Path: sourcePath, Fragment: fragment}
+	}
+
+	resolved, err := nav.ResolveMarkdownPath(path, sourcePath, searchRoots)
+	if err != nil {
+		return ResolvedLink{Fragment: fragment, Err: err}
+	}
+	if abs, err := filepath.Abs(resolved); err == nil {
+		resolved = abs
+	}
+	return ResolvedLink{Path: resolved, Fragment: fragment}
+}
+
+// isExternalLink reports whether a target carries a URL scheme
+func isExternalLink(target string) bool {
+	if strings.Contains(target, "://") {
+		return true
+	}
+	scheme, _, found := strings.Cut(target, ":")
+	if !found || scheme == "" || strings.ContainsAny(scheme, "/.#") {
+		return false
+	}
+	// a single letter is a Windows drive, not a scheme
+	return len(scheme) > 1
+}
+
+// Backlinks returns one hit per link in the index that points at page.
+// Links from page to itself are not included.
+func (idx *Index) Backlinks(page string) []Hit {
+	page = absPath(page)
+	roots := []string{idx.Root}
+
+	var hits []Hit
+	for _, doc := range idx.Documents {
+		source := absPath(idx.AbsPath(doc))
+		if source == page {
+			continue
+		}
+		for _, link := range doc.Links {
+			resolved := ResolveLink(link.Target, source, roots)
+			if resolved.External || resolved.Err != nil || resolved.Path != page {
+				continue
+			}
+			section := doc.SectionAt(link.Line)
+			hits = append(hits, Hit{
+				Document: doc,
+				Heading:  section.Heading,
+				Slug:     section.Slug,
+				Line:     link.Line,
+				Snippet:  link.Text,
+			})
+		}
+	}
+	return hits
+}
+
+// SectionAt returns the section containing the given 1-based line.
+func (doc *Document) SectionAt(line int) Section {
+	var found Section
+	for _, section := range doc.Sections {
+		if section.Line > line {
+			break
+		}
+		found = section
+	}
+	return found
+}
+
+// Problem is a broken link.
+type Problem struct {
+	Source string // path of the file containing the link, as given to the checker
+	Line   int
+	Target string
+	Reason string
+}
+
+// Checker finds links to missing files and anchors. It caches the headings of every
+// markdown file it reads, so one checker should be reused across a tree.
+type Checker struct {
+	searchRoots []string
+	anchors     map[string]map[string]bool
+}
+
+// NewChecker creates a link checker resolving links against searchRoots
+func NewChecker(searchRoots []string) *Checker {
+	return &Checker{
+		searchRoots: searchRoots,
+		anchors:     make(map[string]map[string]bool),
+	}
+}
+
+// Check verifies every local link of a parsed document. sourcePath is the document's
+// file on disk; name is how problems refer to it.
+func (c *Checker) Check(name, sourcePath string, doc *Document) []Problem {
+	sourcePath = absPath(sourcePath)
+	c.anchors[sourcePath] = documentAnchors(doc)
+
+	var problems []Problem
+	for _, link := range doc.Links {
+		resolved := ResolveLink(link.Target, sourcePath, c.searchRoots)
+		if resolved.External {
+			continue
+		}
+		problem := Problem{Source: name, Line: link.Line, Target: link.Target}
+
+		if resolved.Err != nil {
+			problem.Reason = "file not found"
+			if errors.Is(resolved.Err, nav.ErrDirectoryTraversal) {
+				problem.Reason = "path leaves the repository"
+			}
+			problems = append(problems, problem)
+			continue
+		}
+		if resolved.Fragment == "" || !strings.EqualFold(filepath.Ext(resolved.Path), ".md") {
+			continue
+		}
+
+		anchors, err := c.fileAnchors(resolved.Path)
+		if err != nil {
+			problem.Reason = err.Error()
+			problems = append(problems, problem)
+			continue
+		}
+		if !anchors[resolved.Fragment] {
+			problem.Reason = "anchor #" + resolved.Fragment + " not found"
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// fileAnchors returns the heading slugs of a markdown file, reading it once
+func (c *Checker) fileAnchors(path string) (map[string]bool, error) {
+	if anchors, ok := c.anchors[path]; ok {
+		return anchors, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	anchors := documentAnchors(ParseDocument(filepath.Base(path), string(content)))
+	c.anchors[path] = anchors
+	return anchors, nil
+}
+
+func documentAnchors(doc *Document) map[string]bool {
+	anchors := make(map[string]bool, len(doc.Sections))
+	for _, section := range doc.Sections {
+		if section.Slug != "" {
+			anchors[section.Slug] = true
+		}
+	}
+	return anchors
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
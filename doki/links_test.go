@@ -0,0 +1,105 @@
+package doki
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"index.md":      "# Home\n",
+		"doc/config.md": "# Config\n",
+	})
+	source := filepath.Join(root, "doc", "config.md")
+
+	if r := ResolveLink("https://example.com", source, nil); !r.External {
+		t.Error("http link should be external")
+	}
+	if r := ResolveLink("mailto:team@example.com", source, nil); !r.External {
+		t.Error("mailto link should be external")
+	}
+	if r := ResolveLink("#config", source, nil); r.Path != source || r.Fragment != "config" {
+		t.Errorf("same-page anchor resolved to %+v", r)
+	}
+	// not next to the source, found through the search root
+	r := ResolveLink("index.md#home", source, []string{root})
+	if r.Err != nil || r.Path != filepath.Join(root, "index.md") || r.Fragment != "home" {
+		t.Errorf("root-relative link resolved to %+v", r)
+	}
+	if r := ResolveLink("missing.md", source, []string{root}); r.Err == nil {
+		t.Error("missing file should fail to resolve")
+	}
+}
+
+func TestBacklinks(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"index.md":      "# Home\n\nSee [config](doc/config.md).\n\n## More\n\nAgain [here](doc/config.md#sprints).\n",
+		"doc/config.md": "# Config\n\n## Sprints\n\nBack to [top](#config) and [home](../index.md).\n",
+		"doc/other.md":  "# Other\n\nSibling [config](config.md).\n",
+	})
+
+	idx, err := BuildIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits := idx.Backlinks(filepath.Join(root, "doc", "config.md"))
+	if len(hits) != 3 {
+		t.Fatalf("len(Backlinks) = %d, want 3 (self link excluded): %+v", len(hits), hits)
+	}
+	if hits[2].Document.Path != "index.md" || hits[2].Slug != "more" {
+		t.Errorf("expected the second index link to point at section 'more', got %s#%s", hits[2].Document.Path, hits[2].Slug)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	root := t.TempDir()
+	dokiDir := filepath.Join(root, "doki")
+	taskDir := filepath.Join(root, "tiki")
+	writeTree(t, root, map[string]string{
+		"doki/index.md":       "# Home\n\n[ok](doc/config.md#sprints) [bad anchor](doc/config.md#nope) [gone](missing.md) [web](https://example.com)\n",
+		"doki/doc/config.md":  "# Config\n\n## Sprints\n\n[top](#config) [broken](#missing)\n",
+		"tiki/tiki-abc123.md": "---\ntitle: Task\nstatus: ready\n---\nSee [docs](../doki/doc/config.md) and [nothing](nothing.md).\n",
+	})
+
+	report, err := Doctor(dokiDir, taskDir)
+	if err != nil {
+		t.Fatalf("Doctor() error: %v", err)
+	}
+	if report.Dokis != 2 || report.Tikis != 1 {
+		t.Errorf("checked %d dokis and %d tikis, want 2 and 1", report.Dokis, report.Tikis)
+	}
+
+	want := []string{
+		"doc/config.md:5: #missing: anchor #missing not found",
+		"index.md:3: doc/config.md#nope: anchor #nope not found",
+		"index.md:3: missing.md: file not found",
+		"tiki/tiki-abc123.md:5: nothing.md: file not found",
+	}
+	text := report.String()
+	for _, line := range want {
+		if !strings.Contains(text, line) {
+			t.Errorf("report missing %q:\n%s", line, text)
+		}
+	}
+	if len(report.Problems) != len(want) {
+		t.Errorf("len(Problems) = %d, want %d:\n%s", len(report.Problems), len(want), text)
+	}
+}
@@ -0,0 +1,78 @@
+package doki
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TaskLinkScheme prefixes the link targets that refer to tikis, e.g. "tiki:TIKI-ABC123".
+const TaskLinkScheme = "tiki:"
+
+var (
+	// a bare reference is upper case so file names like tiki-abc123.md are left alone
+	taskRefPattern = regexp.MustCompile(`\[\[\s*((?i:tiki)-[A-Za-z0-9]+)\s*\]\]|\bTIKI-[A-Z0-9]+\b`)
+
+	// spans whose text must not be linked: inline code, links, autolinks and URLs
+	protectedPattern = regexp.MustCompile("`[^`]*`|!?\\[[^\\]]*\\]\\([^)]*\\)|<[^>\\s]+>|[a-zA-Z][a-zA-Z0-9+.-]*://\\S+")
+)
+
+// TaskReference returns the task ID a link target refers to.
+func TaskReference(target string) (string, bool) {
+	if len(target) <= len(TaskLinkScheme) || !strings.EqualFold(target[:len(TaskLinkScheme)], TaskLinkScheme) {
+		return "", false
+	}
+	return strings.ToUpper(target[len(TaskLinkScheme):]), true
+}
+
+// LinkTaskReferences turns task IDs written as TIKI-ABC123 or [[TIKI-ABC123]] into
+// markdown links with a tiki: target. Code blocks, inline code and existing links
+// are left untouched.
+func LinkTaskReferences(markdown string) string {
+	if !strings.Contains(strings.ToUpper(markdown), "TIKI-") {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if marker := fenceMarker(trimmed); marker != "" {
+			if !inFence {
+				inFence, fence = true, marker
+			} else if strings.HasPrefix(trimmed, fence) {
+				inFence = false
+			}
+			continue
+		}
+		if inFence || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		lines[i] = linkLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkLine links the references in the unprotected parts of a line
+func linkLine(line string) string {
+	var sb strings.Builder
+	last := 0
+	for _, span := range protectedPattern.FindAllStringIndex(line, -1) {
+		sb.WriteString(linkText(line[last:span[0]]))
+		sb.WriteString(line[span[0]:span[1]])
+		last = span[1]
+	}
+	sb.WriteString(linkText(line[last:]))
+	return sb.String()
+}
+
+func linkText(text string) string {
+	return taskRefPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		id := ref
+		if m := taskRefPattern.FindStringSubmatch(ref); m[1] != "" {
+			id = m[1]
+		}
+		id = strings.ToUpper(id)
+		return "[" + id + "](" + TaskLinkScheme + id + ")"
+	})
+}
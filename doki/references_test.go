@@ -0,0 +1,39 @@
+package doki
+
+import "testing"
+
+func TestLinkTaskReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"bare", "see TIKI-ABC123.", "see [TIKI-ABC123](tiki:TIKI-ABC123)."},
+		{"wiki", "see [[tiki-abc123]]", "see [TIKI-ABC123](tiki:TIKI-ABC123)"},
+		{"several", "TIKI-1 and TIKI-2", "[TIKI-1](tiki:TIKI-1) and [TIKI-2](tiki:TIKI-2)"},
+		{"file name", "open tiki-abc123.md", "open tiki-abc123.md"},
+		{"inline code", "run `tiki show TIKI-1` for TIKI-2", "run `tiki show TIKI-1` for [TIKI-2](tiki:TIKI-2)"},
+		{"existing link", "[TIKI-1](other.md) TIKI-2", "[TIKI-1](other.md) [TIKI-2](tiki:TIKI-2)"},
+		{"url", "https://example.com/TIKI-1", "https://example.com/TIKI-1"},
+		{"fenced code", "```\nTIKI-1\n```\nTIKI-2", "```\nTIKI-1\n```\n[TIKI-2](tiki:TIKI-2)"},
+		{"part of a word", "XTIKI-1 TIKI-1X", "XTIKI-1 [TIKI-1X](tiki:TIKI-1X)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LinkTaskReferences(tt.in); got != tt.want {
+				t.Errorf("LinkTaskReferences(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskReference(t *testing.T) {
+	if id, ok := TaskReference("tiki:tiki-abc123"); !ok || id != "TIKI-ABC123" {
+		t.Errorf("TaskReference = %q, %v", id, ok)
+	}
+	for _, target := range []string{"tiki:", "doc/index.md", "https://example.com"} {
+		if _, ok := TaskReference(target); ok {
+			t.Errorf("%q should not be a task reference", target)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package doki
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Score weights for where a query term matches
+const (
+	titleWeight   = 5
+	headingWeight = 3
+	linkWeight    = 2
+	textWeight    = 1
+)
+
+// snippetWidth is the maximum length of a hit's context snippet in runes
+const snippetWidth = 80
+
+// Hit is a section matching a search query.
+type Hit struct {
+	Document *Document
+	Heading  string
+	Slug     string // anchor to scroll to; empty for the preamble
+	Line     int
+	Snippet  string
+	Score    int
+}
+
+// Search finds sections containing every term of the query (case-insensitive),
+// ranked by where the terms occur: document title, section heading, link targets
+// and body text. Returns at most limit hits (0 = no limit).
+func (idx *Index) Search(query string, limit int) []Hit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var hits []Hit
+	for _, doc := range idx.Documents {
+		title := strings.ToLower(doc.Title + " " + doc.Path)
+		for _, section := range doc.Sections {
+			heading := strings.ToLower(section.Heading)
+			text := strings.ToLower(section.Text)
+			links := strings.ToLower(sectionLinkTargets(doc, section))
+
+			// a title-only match counts once per document, on its first section
+			first := section.Line == doc.Sections[0].Line
+			score := 0
+			matchedAll := true
+			for _, term := range terms {
+				inTitle := strings.Contains(title, term)
+				if !sectionContains(heading, text, links, term) && !(inTitle && first) {
+					matchedAll = false
+					break
+				}
+				if inTitle {
+					score += titleWeight
+				}
+				if strings.Contains(heading, term) {
+					score += headingWeight
+				}
+				if strings.Contains(links, term) {
+					score += linkWeight
+				}
+				score += textWeight * strings.Count(text, term)
+			}
+			if !matchedAll {
+				continue
+			}
+
+			hits = append(hits, Hit{
+				Document: doc,
+				Heading:  section.Heading,
+				Slug:     section.Slug,
+				Line:     section.Line,
+				Snippet:  snippet(section.Text, terms[0]),
+				Score:    score,
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Document.Path != hits[j].Document.Path {
+			return hits[i].Document.Path < hits[j].Document.Path
+		}
+		return hits[i].Line < hits[j].Line
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+func sectionContains(heading, text, links, term string) bool {
+	return strings.Contains(heading, term) || strings.Contains(text, term) || strings.Contains(links, term)
+}
+
+// sectionLinkTargets joins the targets of the links that belong to a section.
+func sectionLinkTargets(doc *Document, section Section) string {
+	end := -1
+	for _, s := range doc.Sections {
+		if s.Line > section.Line {
+			end = s.Line
+			break
+		}
+	}
+	var targets []string
+	for _, link := range doc.Links {
+		if link.Line >= section.Line && (end < 0 || link.Line < end) {
+			targets = append(targets, link.Target)
+		}
+	}
+	return strings.Join(targets, " ")
+}
+
+// snippet returns the line of text containing term, shortened around the match.
+func snippet(text, term string) string {
+	lines := strings.Split(text, "\n")
+	line := ""
+	for _, l := range lines {
+		if strings.Contains(strings.ToLower(l), term) {
+			line = l
+			break
+		}
+	}
+	if line == "" {
+		for _, l := range lines {
+			if strings.TrimSpace(l) != "" {
+				line = l
+				break
+			}
+		}
+	}
+	line = strings.Join(strings.Fields(line), " ")
+	if utf8.RuneCountInString(line) <= snippetWidth {
+		return line
+	}
+
+	// ToLower maps rune by rune, so rune offsets in lower match those in line
+	runes := []rune(line)
+	lower := strings.ToLower(line)
+	pos := utf8.RuneCountInString(lower[:max(0, strings.Index(lower, term))])
+	start := max(0, pos-snippetWidth/3)
+	end := min(len(runes), start+snippetWidth)
+	start = max(0, end-snippetWidth)
+
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package doki
+
+import (
+	"strings"
+	"testing"
+)
+
+func testIndex() *Index {
+	return &Index{Documents: []*Document{
+		ParseDocument("doc/config.md", "# Configuration\n\nGeneral settings.\n\n## Sprints\n\nSprints are named date ranges used by the velocity chart.\n"),
+		ParseDocument("index.md", "# Home\n\nWelcome. Read about [sprints](doc/config.md#sprints).\n\n## Velocity\n\nThe velocity chart.\n"),
+	}}
+}
+
+func TestSearchRanksHeadingsFirst(t *testing.T) {
+	hits := testIndex().Search("sprints", 0)
+	if len(hits) != 2 {
+		t.Fatalf("len(hits) = %d, want 2", len(hits))
+	}
+	if hits[0].Document.Path != "doc/config.md" || hits[0].Slug != "sprints" {
+		t.Errorf("top hit = %s#%s, want doc/config.md#sprints", hits[0].Document.Path, hits[0].Slug)
+	}
+	if hits[1].Document.Path != "index.md" {
+		t.Errorf("second hit = %s, want index.md", hits[1].Document.Path)
+	}
+}
+
+func TestSearchRequiresAllTerms(t *testing.T) {
+	hits := testIndex().Search("Velocity CHART", 0)
+	if len(hits) != 2 {
+		t.Fatalf("len(hits) = %d, want 2", len(hits))
+	}
+	for _, hit := range hits {
+		if !strings.Contains(strings.ToLower(hit.Snippet), "velocity") {
+			t.Errorf("snippet %q does not contain the first term", hit.Snippet)
+		}
+	}
+
+	if hits := testIndex().Search("velocity missing", 0); len(hits) != 0 {
+		t.Errorf("expected no hits when a term is missing, got %d", len(hits))
+	}
+}
+
+func TestSearchTitleMatchesOnce(t *testing.T) {
+	hits := testIndex().Search("configuration", 0)
+	if len(hits) != 1 || hits[0].Heading != "Configuration" {
+		t.Fatalf("expected a single hit on the first section, got %+v", hits)
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	if hits := testIndex().Search("the", 1); len(hits) != 1 {
+		t.Errorf("len(hits) = %d, want 1", len(hits))
+	}
+	if hits := testIndex().Search("   ", 0); hits != nil {
+		t.Errorf("expected nil for empty query, got %v", hits)
+	}
+}
+
+func TestSnippetShortensAroundMatch(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 20) + "needle " + strings.Repeat("dolor sit ", 20)
+	got := snippet(text, "needle")
+	if !strings.Contains(got, "needle") {
+		t.Errorf("snippet %q does not contain the match", got)
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet %q should be elided on both sides", got)
+	}
+}
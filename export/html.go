@@ -0,0 +1,305 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/boolean-maybe/tiki/doki"
+)
+
+// markdown renders GitHub-flavored markdown; raw HTML in documents is not passed through
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// renderMarkdown converts the markdown of a source file to HTML for a page. Task
+// references become links, links to dokis and tikis point at their pages and
+// headings get the anchors the viewer uses, so #fragments keep working.
+func (w *siteWriter) renderMarkdown(content, source, page string) (template.HTML, error) {
+	src := []byte(doki.LinkTaskReferences(content))
+	root := markdown.Parser().Parse(text.NewReader(src))
+
+	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			node.SetAttributeString("id", []byte(doki.Slug(plainText(node, src))))
+		case *ast.Link:
+			node.Destination = []byte(w.rewriteLink(string(node.Destination), source, page))
+		case *ast.Image:
+			node.Destination = []byte(w.rewriteLink(string(node.Destination), source, page))
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, root); err != nil {
+		return "", fmt.Errorf("render markdown: %w", err)
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec // G203: goldmark escapes raw HTML by default
+}
+
+// rewriteLink maps a link target written in source to a URL relative to page. Targets
+// outside the doki directory that aren't tiki files, missing files and web links are kept as written.
+func (w *siteWriter) rewriteLink(target, source, page string) string {
+	if id, ok := doki.TaskReference(target); ok {
+		return relativeURL(page, taskPage(id))
+	}
+
+	r := doki.ResolveLink(target, source, []string{w.dokiDir})
+	if r.External || r.Err != nil || r.Path == source {
+		return target
+	}
+
+	var dest string
+	if rel, ok := within(w.dokiDir, r.Path); ok {
+		if strings.EqualFold(path.Ext(rel), ".md") {
+			dest = docPage(rel)
+		} else {
+			dest = docsDir + "/" + rel
+		}
+	} else if id, ok := w.taskIDs[r.Path]; ok {
+		dest = taskPage(id)
+	} else {
+		return target
+	}
+
+	url := relativeURL(page, dest)
+	if r.Fragment != "" {
+		url += "#" + r.Fragment
+	}
+	return url
+}
+
+// within returns the slash-separated path of p relative to dir, if p is below dir
+func within(dir, p string) (string, bool) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// plainText returns the text of a node without markup
+func plainText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := c.(type) {
+		case *ast.Text:
+			sb.Write(node.Segment.Value(src))
+		case *ast.String:
+			sb.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+type navLink struct {
+	Name   string
+	URL    string
+	Detail string
+}
+
+type layoutData struct {
+	SiteTitle string
+	Title     string
+	Style     string
+	Home      string
+	Docs      string
+	Boards    []navLink
+	Generated string
+	Body      template.HTML
+}
+
+type indexData struct {
+	Boards []navLink
+	Docs   []navLink
+	Tasks  []cardData
+}
+
+type cardData struct {
+	ID       string
+	Title    string
+	URL      string
+	Status   string
+	Type     string
+	Priority string
+	Points   int
+	Assignee string
+}
+
+type laneData struct {
+	Name  string
+	Cards []cardData
+}
+
+type boardData struct {
+	Name  string
+	Lanes []laneData
+}
+
+type commentData struct {
+	Author  string
+	Created string
+	Text    template.HTML
+}
+
+type taskData struct {
+	ID          string
+	Title       string
+	Status      string
+	Type        string
+	Priority    string
+	Points      int
+	Assignee    string
+	Tags        []string
+	Description template.HTML
+	Comments    []commentData
+}
+
+var layoutTemplate = template.Must(template.New("layout").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}{{if and .SiteTitle (ne .Title .SiteTitle)}} - {{.SiteTitle}}{{end}}</title>
+<link rel="stylesheet" href="{{.Style}}">
+</head>
+<body>
+<header>
+<nav>
+<a class="site" href="{{.Home}}">{{.SiteTitle}}</a>
+{{- range .Boards}}
+<a href="{{.URL}}">{{.Name}}</a>
+{{- end}}
+{{- if .Docs}}
+<a href="{{.Docs}}">Docs</a>
+{{- end}}
+</nav>
+</header>
+<main>
+{{.Body}}
+</main>
+<footer>Snapshot generated {{.Generated}}</footer>
+</body>
+</html>
+`))
+
+var indexTemplate = template.Must(template.New("index").Parse(`<h1>Overview</h1>
+{{- if .Boards}}
+<h2>Boards</h2>
+<ul>
+{{- range .Boards}}
+<li><a href="{{.URL}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Docs}}
+<h2>Documentation</h2>
+<ul>
+{{- range .Docs}}
+<li><a href="{{.URL}}">{{.Name}}</a> <span class="muted">{{.Detail}}</span></li>
+{{- end}}
+</ul>
+{{- end}}
+<h2>Tikis</h2>
+{{- if .Tasks}}
+<table>
+<thead><tr><th>ID</th><th>Title</th><th>Status</th><th>Type</th><th>Priority</th><th>Assignee</th></tr></thead>
+<tbody>
+{{- range .Tasks}}
+<tr><td><a href="{{.URL}}">{{.ID}}</a></td><td>{{.Title}}</td><td>{{.Status}}</td><td>{{.Type}}</td><td>{{.Priority}}</td><td>{{.Assignee}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p class="muted">No tikis</p>
+{{- end}}
+`))
+
+var boardTemplate = template.Must(template.New("board").Parse(`<h1>{{.Name}}</h1>
+<div class="board">
+{{- range .Lanes}}
+<section class="lane">
+<h2>{{.Name}} <span class="muted">{{len .Cards}}</span></h2>
+{{- range .Cards}}
+<a class="card" href="{{.URL}}">
+<span class="id">{{.ID}}</span>
+<span class="title">{{.Title}}</span>
+<span class="meta">{{.Type}} · {{.Priority}}{{if .Points}} · {{.Points}} pts{{end}}{{if .Assignee}} · {{.Assignee}}{{end}}</span>
+</a>
+{{- end}}
+</section>
+{{- end}}
+</div>
+`))
+
+var taskTemplate = template.Must(template.New("task").Parse(`<h1><span class="id">{{.ID}}</span> {{.Title}}</h1>
+<dl class="fields">
+<dt>Status</dt><dd>{{.Status}}</dd>
+<dt>Type</dt><dd>{{.Type}}</dd>
+<dt>Priority</dt><dd>{{.Priority}}</dd>
+{{- if .Points}}
+<dt>Points</dt><dd>{{.Points}}</dd>
+{{- end}}
+{{- if .Assignee}}
+<dt>Assignee</dt><dd>{{.Assignee}}</dd>
+{{- end}}
+{{- if .Tags}}
+<dt>Tags</dt><dd>{{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag}}{{end}}</dd>
+{{- end}}
+</dl>
+<article>
+{{.Description}}
+</article>
+{{- if .Comments}}
+<h2>Comments</h2>
+{{- range .Comments}}
+<section class="comment">
+<p class="muted">{{.Author}} · {{.Created}}</p>
+{{.Text}}
+</section>
+{{- end}}
+{{- end}}
+`))
+
+const stylesheet = `body { margin: 0; font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; }
+header { background: #24292f; padding: 0.6em 1.5em; }
+header a { color: #f0f6fc; margin-right: 1.2em; text-decoration: none; }
+header a.site { font-weight: 600; }
+main { padding: 1em 1.5em; max-width: 1200px; }
+footer { padding: 1em 1.5em; color: #656d76; font-size: 0.85em; }
+a { color: #0969da; }
+pre, code { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 0.9em; }
+pre { background: #f6f8fa; padding: 0.8em; overflow: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.3em 0.7em; text-align: left; }
+.muted { color: #656d76; font-weight: normal; }
+.id { color: #656d76; font-family: ui-monospace, Menlo, Consolas, monospace; }
+.board { display: flex; gap: 1em; align-items: flex-start; overflow-x: auto; }
+.lane { flex: 1 0 220px; background: #f6f8fa; padding: 0.5em; border-radius: 6px; }
+.lane h2 { font-size: 1em; margin: 0.2em 0 0.6em; }
+.card { display: block; background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 0.5em; margin-bottom: 0.5em; color: inherit; text-decoration: none; }
+.card .title { display: block; }
+.card .meta { display: block; color: #656d76; font-size: 0.85em; }
+.fields { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+.fields dt { color: #656d76; }
+.fields dd { margin: 0; }
+.comment { border-top: 1px solid #d0d7de; }
+`
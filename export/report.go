@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// Report renders a tiki view as one GitHub-flavored markdown document: a section per
+// lane with a table of the tikis the lane shows, filtered and sorted as on the board.
+// The output reads as a release note, a sprint review or a PR body.
+type Report struct {
+	Board       *plugin.TikiPlugin
+	Tasks       []*task.Task
+	CurrentUser string    // resolves "my tasks" style lane filters
+	Now         time.Time // resolves relative dates in lane filters
+	// TaskURL links each tiki ID to a page when set, e.g. the tiki file on GitHub
+	TaskURL func(id string) string
+}
+
+// Markdown renders the report
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", r.Board.Name)
+	lanes := make([][]*task.Task, len(r.Board.Lanes))
+	total, points := 0, 0
+	for i := range r.Board.Lanes {
+		lanes[i] = r.Board.LaneTasks(i, r.Tasks, r.Now, r.CurrentUser)
+		total += len(lanes[i])
+		points += sumPoints(lanes[i])
+	}
+	fmt.Fprintf(&sb, "Generated %s. %s, %s.\n\n", r.Now.Local().Format("2006-01-02 15:04"), countNoun(total, "tiki"), countNoun(points, "point"))
+
+	for i, lane := range r.Board.Lanes {
+		tasks := lanes[i]
+		fmt.Fprintf(&sb, "## %s\n\n", lane.Name)
+		if len(tasks) == 0 {
+			sb.WriteString("_No tikis._\n\n")
+			continue
+		}
+		fmt.Fprintf(&sb, "%s, %s.\n\n", countNoun(len(tasks), "tiki"), countNoun(sumPoints(tasks), "point"))
+		sb.WriteString("| ID | Title | Type | Status | Priority | Points | Assignee | Tags |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+		for _, t := range tasks {
+			estimate := "-"
+			if t.Points > 0 {
+				estimate = fmt.Sprint(t.Points)
+			}
+			tags := make([]string, len(t.Tags))
+			for j, tag := range t.Tags {
+				tags[j] = "`" + strings.ReplaceAll(tag, "`", "") + "`"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				r.taskRef(t.ID), escapeCell(t.Title), task.TypeLabel(t.Type), task.StatusLabel(t.Status),
+				task.PriorityLabel(t.Priority), estimate, escapeCell(t.Assignee), strings.Join(tags, " "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// taskRef is a tiki's ID, linked when the report has TaskURL
+func (r *Report) taskRef(id string) string {
+	if r.TaskURL == nil {
+		return id
+	}
+	return fmt.Sprintf("[%s](%s)", id, r.TaskURL(id))
+}
+
+// FindBoard returns the tiki view named name, ignoring case
+func FindBoard(plugins []plugin.Plugin, name string) (*plugin.TikiPlugin, error) {
+	var names []string
+	for _, p := range plugins {
+		tp, ok := p.(*plugin.TikiPlugin)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(tp.Name, name) {
+			return tp, nil
+		}
+		names = append(names, tp.Name)
+	}
+	return nil, fmt.Errorf("no board named %q (boards: %s)", name, strings.Join(names, ", "))
+}
+
+func sumPoints(tasks []*task.Task) int {
+	sum := 0
+	for _, t := range tasks {
+		sum += t.Points
+	}
+	return sum
+}
+
+// countNoun formats a count with its noun: 1 tiki, 3 tikis
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// escapeCell keeps text on one line and off the table's column separators
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
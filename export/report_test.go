@@ -0,0 +1,69 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func TestReportMarkdown(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "TIKI-BBB222", Title: "Low", Status: task.StatusReady, Type: task.TypeStory, Priority: 5, Points: 2},
+		{ID: "TIKI-AAA111", Title: "Fix a|b\nparser", Status: task.StatusReady, Type: task.TypeBug, Priority: 1, Points: 3,
+			Assignee: "alice", Tags: []string{"ui", "parser"}},
+		{ID: "TIKI-CCC333", Title: "Done", Status: task.StatusDone, Type: task.TypeStory, Priority: 3},
+	}
+	sortRules, _ := plugin.ParseSort("Priority")
+	board := &plugin.TikiPlugin{
+		BasePlugin: plugin.BasePlugin{Name: "Sprint", Type: "tiki"},
+		Lanes: []plugin.TikiLane{
+			{Name: "Ready", Filter: mustFilter(t, "status = 'ready'")},
+			{Name: "Review", Filter: mustFilter(t, "status = 'review'")},
+			{Name: "Done", Filter: mustFilter(t, "status = 'done'")},
+		},
+		Sort: sortRules,
+	}
+	report := &Report{Board: board, Tasks: tasks, Now: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	md := report.Markdown()
+
+	for _, want := range []string{
+		"# Sprint\n",
+		"3 tikis, 5 points.",
+		"## Ready\n\n2 tikis, 5 points.",
+		"| TIKI-AAA111 | Fix a\\|b parser | Bug | Ready | 🔴 | 3 | alice | `ui` `parser` |",
+		"## Review\n\n_No tikis._",
+		"## Done\n\n1 tiki, 0 points.",
+		"| TIKI-CCC333 | Done | Story | Done | 🟡 | - |  |  |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("report missing %q:\n%s", want, md)
+		}
+	}
+	ready := md[strings.Index(md, "## Ready"):strings.Index(md, "## Review")]
+	if a, b := strings.Index(ready, "TIKI-AAA111"), strings.Index(ready, "TIKI-BBB222"); a < 0 || b < 0 || a > b {
+		t.Errorf("ready lane should list TIKI-AAA111 before TIKI-BBB222:\n%s", ready)
+	}
+
+	report.TaskURL = func(id string) string { return "../tiki/" + strings.ToLower(id) + ".md" }
+	if md := report.Markdown(); !strings.Contains(md, "| [TIKI-CCC333](../tiki/tiki-ccc333.md) |") {
+		t.Errorf("report missing tiki links:\n%s", md)
+	}
+}
+
+func TestFindBoard(t *testing.T) {
+	plugins := []plugin.Plugin{
+		&plugin.DokiPlugin{BasePlugin: plugin.BasePlugin{Name: "Docs", Type: "doki"}},
+		&plugin.TikiPlugin{BasePlugin: plugin.BasePlugin{Name: "Kanban", Type: "tiki"}},
+	}
+	board, err := FindBoard(plugins, "kanban")
+	if err != nil || board.Name != "Kanban" {
+		t.Fatalf("FindBoard(kanban) = %v, %v", board, err)
+	}
+	_, err = FindBoard(plugins, "Docs")
+	if err == nil || !strings.Contains(err.Error(), "boards: Kanban") {
+		t.Errorf("FindBoard(Docs) error = %v, want one listing the boards", err)
+	}
+}
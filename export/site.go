@@ -0,0 +1,329 @@
+// Package export renders tikis and the doki tree into formats readable without the TUI.
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/doki"
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// Site renders the doki tree, every tiki and one board page per tiki view into a
+// static HTML site. Pages link to each other with relative paths and need no
+// network access, so the output can be opened from disk or published as is.
+type Site struct {
+	Title       string // shown in the page header, e.g. the project name
+	DokiDir     string
+	TaskFile    func(id string) string // the markdown file of a tiki, where its relative links start
+	Tasks       []*task.Task
+	Plugins     []plugin.Plugin
+	CurrentUser string    // resolves "my tasks" style lane filters
+	Now         time.Time // resolves relative dates in lane filters
+}
+
+// output layout, relative to the site root
+const (
+	docsDir   = "docs"
+	tasksDir  = "tiki"
+	boardsDir = "board"
+)
+
+// board is a tiki view rendered as a page
+type board struct {
+	Name   string
+	Page   string // site-relative page path
+	plugin *plugin.TikiPlugin
+}
+
+// Write renders the site into outDir, creating it if needed. Existing files with the
+// same names are overwritten; other files in outDir are left alone.
+func (s *Site) Write(outDir string) error {
+	dokiDir, err := filepath.Abs(s.DokiDir)
+	if err != nil {
+		return fmt.Errorf("resolve doki directory: %w", err)
+	}
+	w := &siteWriter{site: s, outDir: outDir, dokiDir: dokiDir,
+		taskSources: make(map[string]string, len(s.Tasks)), taskIDs: make(map[string]string, len(s.Tasks))}
+	for _, t := range s.Tasks {
+		source, err := filepath.Abs(s.TaskFile(t.ID))
+		if err != nil {
+			return fmt.Errorf("resolve file of %s: %w", t.ID, err)
+		}
+		w.taskSources[t.ID] = source
+		w.taskIDs[source] = t.ID
+	}
+
+	if _, err := os.Stat(dokiDir); err == nil {
+		if w.docs, err = doki.BuildIndex(dokiDir); err != nil {
+			return fmt.Errorf("index doki: %w", err)
+		}
+	} else {
+		w.docs = &doki.Index{Root: dokiDir}
+	}
+	w.boards = boardsOf(s.Plugins)
+
+	if err := w.writeFile("style.css", []byte(stylesheet)); err != nil {
+		return err
+	}
+	if err := w.writeIndex(); err != nil {
+		return err
+	}
+	for _, b := range w.boards {
+		if err := w.writeBoard(b); err != nil {
+			return fmt.Errorf("board %s: %w", b.Name, err)
+		}
+	}
+	for _, t := range s.Tasks {
+		if err := w.writeTask(t); err != nil {
+			return fmt.Errorf("tiki %s: %w", t.ID, err)
+		}
+	}
+	for _, doc := range w.docs.Documents {
+		if err := w.writeDoc(doc); err != nil {
+			return fmt.Errorf("doki %s: %w", doc.Path, err)
+		}
+	}
+	return w.copyAssets()
+}
+
+// boardsOf returns a board for every tiki view, with unique page names
+func boardsOf(plugins []plugin.Plugin) []board {
+	var boards []board
+	used := make(map[string]bool)
+	for _, p := range plugins {
+		tp, ok := p.(*plugin.TikiPlugin)
+		if !ok {
+			continue
+		}
+		name := doki.Slug(tp.Name)
+		if name == "" {
+			name = "board"
+		}
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s-%d", doki.Slug(tp.Name), i)
+		}
+		used[name] = true
+		boards = append(boards, board{Name: tp.Name, Page: boardsDir + "/" + name + ".html", plugin: tp})
+	}
+	return boards
+}
+
+// taskPage returns the site-relative page of a task: TIKI-ABC123 -> tiki/tiki-abc123.html.
+// A workspace tiki gets a directory per repository: api:TIKI-ABC123 -> tiki/api/tiki-abc123.html
+func taskPage(id string) string {
+	return tasksDir + "/" + strings.ReplaceAll(strings.ToLower(id), ":", "/") + ".html"
+}
+
+// docPage returns the site-relative page of a doki document: doc/a.md -> docs/doc/a.html
+func docPage(docPath string) string {
+	return docsDir + "/" + strings.TrimSuffix(docPath, path.Ext(docPath)) + ".html"
+}
+
+// relativeURL returns the link from one site-relative page to another
+func relativeURL(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
+
+// siteWriter holds the state of one Write call
+type siteWriter struct {
+	site    *Site
+	outDir  string
+	dokiDir string
+	docs    *doki.Index
+	// the markdown file of each tiki by ID, and the reverse
+	taskSources map[string]string
+	taskIDs     map[string]string
+	boards      []board
+}
+
+func (w *siteWriter) writeFile(name string, data []byte) error {
+	target := filepath.Join(w.outDir, filepath.FromSlash(name))
+	//nolint:gosec // G301: 0755 is appropriate for a published site
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	//nolint:gosec // G306: 0644 is appropriate for a published site
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writePage wraps a page body in the site layout
+func (w *siteWriter) writePage(page, title string, body template.HTML) error {
+	data := layoutData{
+		SiteTitle: w.site.Title,
+		Title:     title,
+		Style:     relativeURL(page, "style.css"),
+		Home:      relativeURL(page, "index.html"),
+		Generated: w.site.Now.Format("2006-01-02 15:04"),
+		Body:      body,
+	}
+	if len(w.docs.Documents) > 0 {
+		data.Docs = relativeURL(page, docPage(w.homeDoc().Path))
+	}
+	for _, b := range w.boards {
+		data.Boards = append(data.Boards, navLink{Name: b.Name, URL: relativeURL(page, b.Page)})
+	}
+
+	var sb strings.Builder
+	if err := layoutTemplate.Execute(&sb, data); err != nil {
+		return fmt.Errorf("render %s: %w", page, err)
+	}
+	return w.writeFile(page, []byte(sb.String()))
+}
+
+// homeDoc is the doki entry page: index.md when there is one, else the first document
+func (w *siteWriter) homeDoc() *doki.Document {
+	if doc := w.docs.Document("index.md"); doc != nil {
+		return doc
+	}
+	return w.docs.Documents[0]
+}
+
+func (w *siteWriter) writeIndex() error {
+	const page = "index.html"
+	data := indexData{}
+	for _, b := range w.boards {
+		data.Boards = append(data.Boards, navLink{Name: b.Name, URL: relativeURL(page, b.Page)})
+	}
+	for _, doc := range w.docs.Documents {
+		data.Docs = append(data.Docs, navLink{Name: doc.Title, URL: relativeURL(page, docPage(doc.Path)), Detail: doc.Path})
+	}
+	for _, t := range sortedByID(w.site.Tasks) {
+		data.Tasks = append(data.Tasks, w.card(page, t))
+	}
+
+	var sb strings.Builder
+	if err := indexTemplate.Execute(&sb, data); err != nil {
+		return fmt.Errorf("render index: %w", err)
+	}
+	return w.writePage(page, w.site.Title, template.HTML(sb.String())) //nolint:gosec // G203: rendered by html/template
+}
+
+func (w *siteWriter) writeBoard(b board) error {
+	data := boardData{Name: b.Name}
+	for i, lane := range b.plugin.Lanes {
+		col := laneData{Name: lane.Name}
+		for _, t := range b.plugin.LaneTasks(i, w.site.Tasks, w.site.Now, w.site.CurrentUser) {
+			col.Cards = append(col.Cards, w.card(b.Page, t))
+		}
+		data.Lanes = append(data.Lanes, col)
+	}
+
+	var sb strings.Builder
+	if err := boardTemplate.Execute(&sb, data); err != nil {
+		return err
+	}
+	return w.writePage(b.Page, b.Name, template.HTML(sb.String())) //nolint:gosec // G203: rendered by html/template
+}
+
+func (w *siteWriter) writeTask(t *task.Task) error {
+	page := taskPage(t.ID)
+	source := w.taskSources[t.ID]
+
+	description, err := w.renderMarkdown(t.Description, source, page)
+	if err != nil {
+		return err
+	}
+	data := taskData{
+		ID:          t.ID,
+		Title:       t.Title,
+		Status:      task.StatusLabel(t.Status),
+		Type:        task.TypeLabel(t.Type),
+		Priority:    task.PriorityLabel(t.Priority),
+		Points:      t.Points,
+		Assignee:    t.Assignee,
+		Tags:        t.Tags,
+		Description: description,
+	}
+	for _, c := range t.Comments {
+		text, err := w.renderMarkdown(c.Text, source, page)
+		if err != nil {
+			return err
+		}
+		data.Comments = append(data.Comments, commentData{
+			Author:  c.Author,
+			Created: c.CreatedAt.Format("2006-01-02 15:04"),
+			Text:    text,
+		})
+	}
+
+	var sb strings.Builder
+	if err := taskTemplate.Execute(&sb, data); err != nil {
+		return err
+	}
+	return w.writePage(page, t.ID+" "+t.Title, template.HTML(sb.String())) //nolint:gosec // G203: rendered by html/template
+}
+
+func (w *siteWriter) writeDoc(doc *doki.Document) error {
+	source := w.docs.AbsPath(doc)
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	page := docPage(doc.Path)
+	body, err := w.renderMarkdown(string(content), source, page)
+	if err != nil {
+		return err
+	}
+	return w.writePage(page, doc.Title, body)
+}
+
+// copyAssets copies the non-markdown files of the doki tree, such as images, next to the pages
+func (w *siteWriter) copyAssets() error {
+	if len(w.docs.Documents) == 0 {
+		return nil
+	}
+	return filepath.WalkDir(w.dokiDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.EqualFold(filepath.Ext(p), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(w.dokiDir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return w.writeFile(docsDir+"/"+filepath.ToSlash(rel), data)
+	})
+}
+
+// card summarizes a task for boards and the index
+func (w *siteWriter) card(page string, t *task.Task) cardData {
+	return cardData{
+		ID:       t.ID,
+		Title:    t.Title,
+		URL:      relativeURL(page, taskPage(t.ID)),
+		Status:   task.StatusLabel(t.Status),
+		Type:     task.TypeLabel(t.Type),
+		Priority: task.PriorityLabel(t.Priority),
+		Points:   t.Points,
+		Assignee: t.Assignee,
+	}
+}
+
+func sortedByID(tasks []*task.Task) []*task.Task {
+	sorted := make([]*task.Task, len(tasks))
+	copy(sorted, tasks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
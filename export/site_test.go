@@ -0,0 +1,173 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/plugin/filter"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readPage(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatalf("page %s not written: %v", name, err)
+	}
+	return string(data)
+}
+
+func mustFilter(t *testing.T, expr string) filter.FilterExpr {
+	t.Helper()
+	f, err := filter.ParseFilter(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestSiteWrite(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"doki/index.md":       "# Home\n\nRead the [config guide](doc/config.md#sprints) and see TIKI-AAA111.\n\n![logo](img/logo.png)\n",
+		"doki/doc/config.md":  "# Config\n\n## Sprints\n\nBack [home](../index.md) or [away](https://example.com).\n",
+		"doki/img/logo.png":   "png",
+		"tiki/tiki-aaa111.md": "---\ntitle: First\n---\n",
+	})
+
+	tasks := []*task.Task{
+		{ID: "TIKI-BBB222", Title: "Low", Status: task.StatusReady, Type: task.TypeStory, Priority: 5},
+		{ID: "TIKI-AAA111", Title: "First <b>", Status: task.StatusReady, Type: task.TypeBug, Priority: 1,
+			Description: "Depends on [[tiki-bbb222]], documented in [config](../doki/doc/config.md)."},
+		{ID: "TIKI-CCC333", Title: "Done", Status: task.StatusDone, Type: task.TypeStory, Priority: 3},
+	}
+	sortRules, _ := plugin.ParseSort("Priority")
+	board := &plugin.TikiPlugin{
+		BasePlugin: plugin.BasePlugin{Name: "Kanban", Type: "tiki"},
+		Lanes: []plugin.TikiLane{
+			{Name: "Ready", Filter: mustFilter(t, "status = 'ready'")},
+			{Name: "Done", Filter: mustFilter(t, "status = 'done'")},
+		},
+		Sort: sortRules,
+	}
+
+	site := &Site{
+		Title:   "project",
+		DokiDir: filepath.Join(root, "doki"),
+		TaskFile: func(id string) string {
+			return filepath.Join(root, "tiki", strings.ToLower(id)+".md")
+		},
+		Tasks:   tasks,
+		Plugins: []plugin.Plugin{board, &plugin.DokiPlugin{BasePlugin: plugin.BasePlugin{Name: "Docs", Type: "doki"}}},
+		Now:     time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+	}
+	out := filepath.Join(root, "site")
+	if err := site.Write(out); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	home := readPage(t, out, "docs/index.html")
+	for _, want := range []string{
+		`href="doc/config.html#sprints"`,
+		`href="../tiki/tiki-aaa111.html"`,
+		`src="img/logo.png"`,
+		`href="../style.css"`,
+		`href="../board/kanban.html"`,
+	} {
+		if !strings.Contains(home, want) {
+			t.Errorf("docs/index.html missing %s:\n%s", want, home)
+		}
+	}
+	readPage(t, out, "docs/img/logo.png")
+
+	config := readPage(t, out, "docs/doc/config.html")
+	for _, want := range []string{`<h2 id="sprints">`, `href="../index.html"`, `href="https://example.com"`} {
+		if !strings.Contains(config, want) {
+			t.Errorf("docs/doc/config.html missing %s:\n%s", want, config)
+		}
+	}
+
+	detail := readPage(t, out, "tiki/tiki-aaa111.html")
+	for _, want := range []string{`First &lt;b&gt;`, `href="tiki-bbb222.html"`, `href="../docs/doc/config.html"`} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("tiki page missing %s:\n%s", want, detail)
+		}
+	}
+
+	kanban := readPage(t, out, "board/kanban.html")
+	ready := kanban[strings.Index(kanban, ">Ready "):strings.Index(kanban, ">Done ")]
+	if strings.Contains(ready, "TIKI-CCC333") {
+		t.Error("done task shown in the ready lane")
+	}
+	if a, b := strings.Index(ready, "TIKI-AAA111"), strings.Index(ready, "TIKI-BBB222"); a < 0 || b < 0 || a > b {
+		t.Errorf("ready lane should list TIKI-AAA111 before TIKI-BBB222:\n%s", ready)
+	}
+
+	index := readPage(t, out, "index.html")
+	if !strings.Contains(index, `href="board/kanban.html"`) || !strings.Contains(index, `href="tiki/tiki-ccc333.html"`) {
+		t.Errorf("index missing board or tiki links:\n%s", index)
+	}
+}
+
+func TestTaskPage(t *testing.T) {
+	if got := taskPage("TIKI-ABC123"); got != "tiki/tiki-abc123.html" {
+		t.Errorf("taskPage(TIKI-ABC123) = %s", got)
+	}
+	// a workspace ID must not become a URL scheme in relative links
+	if got := taskPage("api:TIKI-ABC123"); got != "tiki/api/tiki-abc123.html" {
+		t.Errorf("taskPage(api:TIKI-ABC123) = %s", got)
+	}
+}
+
+func TestSiteWriteWorkspaceTasks(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"api/.doc/tiki/tiki-aaa111.md": "---\ntitle: Endpoint\n---\n",
+		"api/.doc/tiki/tiki-ccc333.md": "---\ntitle: Client\n---\n",
+		"web/.doc/tiki/tiki-ccc333.md": "---\ntitle: Page\n---\n",
+	})
+	files := map[string]string{
+		"api:TIKI-AAA111": filepath.Join(root, "api", ".doc", "tiki", "tiki-aaa111.md"),
+		"api:TIKI-CCC333": filepath.Join(root, "api", ".doc", "tiki", "tiki-ccc333.md"),
+		"web:TIKI-CCC333": filepath.Join(root, "web", ".doc", "tiki", "tiki-ccc333.md"),
+	}
+	site := &Site{
+		Title:    "workspace",
+		DokiDir:  filepath.Join(root, "doki"),
+		TaskFile: func(id string) string { return files[id] },
+		Tasks: []*task.Task{
+			{ID: "api:TIKI-AAA111", Title: "Endpoint", Status: task.StatusReady, Type: task.TypeStory, Priority: 3,
+				Description: "Needs [the client](tiki-ccc333.md)."},
+			{ID: "api:TIKI-CCC333", Title: "Client", Status: task.StatusReady, Type: task.TypeStory, Priority: 3},
+			{ID: "web:TIKI-CCC333", Title: "Page", Status: task.StatusReady, Type: task.TypeStory, Priority: 3},
+		},
+		Now: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+	}
+	out := filepath.Join(root, "site")
+	if err := site.Write(out); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	// relative links start from the tiki's own file, in its repository
+	detail := readPage(t, out, "tiki/api/tiki-aaa111.html")
+	if !strings.Contains(detail, `href="tiki-ccc333.html"`) {
+		t.Errorf("link to the sibling tiki not rewritten to its page:\n%s", detail)
+	}
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// githubIssue is an issue as printed by `gh issue list --json` with any of these fields.
+// gh prints state in upper case ("OPEN", "CLOSED").
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// ParseGitHubIssues reads a JSON dump from
+//
+//	gh issue list --state all --json number,title,body,state,url,labels,assignees,milestone,author
+//
+// and maps each issue to a tiki: closed issues are done and open ones backlog, unless a
+// label names a status ("in progress", "review"); labels become tags and a label naming
+// a type ("bug", "spike") sets the type; the first assignee is the assignee, and the
+// milestone and author go to the "milestone" and "author" fields. repo ("owner/name") names the repository when
+// the dump has no issue URLs.
+func ParseGitHubIssues(r io.Reader, repo string) ([]*task.Task, error) {
+	var issues []githubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("parse GitHub issues: %w", err)
+	}
+
+	tasks := make([]*task.Task, 0, len(issues))
+	for _, issue := range issues {
+		externalID, err := githubExternalID(issue, repo)
+		if err != nil {
+			return nil, err
+		}
+		t := &task.Task{
+			Title:       strings.TrimSpace(issue.Title),
+			Description: strings.TrimSpace(strings.ReplaceAll(issue.Body, "\r\n", "\n")),
+			Type:        task.TypeStory,
+			Status:      task.StatusBacklog,
+			ExternalID:  externalID,
+		}
+		if strings.EqualFold(issue.State, "closed") {
+			t.Status = task.StatusDone
+		}
+
+		for _, label := range issue.Labels {
+			name := strings.TrimSpace(label.Name)
+			if name == "" {
+				continue
+			}
+			t.Tags = append(t.Tags, tagFromLabel(name))
+			if taskType, ok := task.ParseType(name); ok {
+				t.Type = taskType
+			}
+			if status, ok := task.ParseStatus(name); ok && t.Status != task.StatusDone {
+				t.Status = status
+			}
+		}
+
+		if len(issue.Assignees) > 0 {
+			t.Assignee = issue.Assignees[0].Login
+		}
+		if len(issue.Assignees) > 1 {
+			logins := make([]string, 0, len(issue.Assignees))
+			for _, a := range issue.Assignees {
+				logins = append(logins, a.Login)
+			}
+			setField(t, "assignees", strings.Join(logins, ", "))
+		}
+		if issue.Author != nil && issue.Author.Login != "" {
+			setField(t, "author", issue.Author.Login)
+		}
+		if issue.Milestone != nil && issue.Milestone.Title != "" {
+			setField(t, "milestone", issue.Milestone.Title)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// githubExternalID is "github:owner/name#number", with the repository taken from the
+// issue URL, or repo when there is none
+func githubExternalID(issue githubIssue, repo string) (string, error) {
+	if issue.Number == 0 {
+		return "", fmt.Errorf("GitHub issue %q has no number", issue.Title)
+	}
+	if issue.URL != "" {
+		u, err := url.Parse(issue.URL)
+		if err == nil {
+			// /owner/name/issues/42
+			parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+			if len(parts) >= 2 {
+				repo = parts[0] + "/" + parts[1]
+			}
+		}
+	}
+	if repo == "" {
+		return "", fmt.Errorf("GitHub issue #%d: no url in the dump, give the repository with --repo", issue.Number)
+	}
+	return fmt.Sprintf("github:%s#%d", repo, issue.Number), nil
+}
+
+func setField(t *task.Task, name, value string) {
+	if t.Fields == nil {
+		t.Fields = make(map[string]string)
+	}
+	t.Fields[name] = value
+}
@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store/tikistore"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func TestParseGitHubIssues(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "gh-issues.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	tasks, err := ParseGitHubIssues(f, "")
+	if err != nil {
+		t.Fatalf("ParseGitHubIssues: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(tasks))
+	}
+
+	bug := tasks[0]
+	if bug.ExternalID != "github:acme/shop#12" {
+		t.Errorf("external ID = %q", bug.ExternalID)
+	}
+	if bug.Type != task.TypeBug || bug.Status != task.StatusBacklog {
+		t.Errorf("type %q status %q, want bug backlog", bug.Type, bug.Status)
+	}
+	if !reflect.DeepEqual(bug.Tags, []string{"bug", "good-first-issue"}) {
+		t.Errorf("tags = %v", bug.Tags)
+	}
+	if bug.Description != "The session cookie is dropped\nafter the redirect." {
+		t.Errorf("description = %q", bug.Description)
+	}
+	if bug.Assignee != "alice" {
+		t.Errorf("assignee = %q, want alice", bug.Assignee)
+	}
+	wantFields := map[string]string{"milestone": "v1.2", "assignees": "alice, bob", "author": "carol"}
+	if !reflect.DeepEqual(bug.Fields, wantFields) {
+		t.Errorf("fields = %v, want %v", bug.Fields, wantFields)
+	}
+
+	// a status label moves open issues; closed ones stay done
+	if tasks[1].Status != task.StatusInProgress {
+		t.Errorf("labelled open issue status = %q, want in_progress", tasks[1].Status)
+	}
+	if !reflect.DeepEqual(tasks[1].Fields, map[string]string{"author": "alice"}) {
+		t.Errorf("fields = %v, want the author only", tasks[1].Fields)
+	}
+	if tasks[2].Status != task.StatusDone {
+		t.Errorf("closed issue status = %q, want done", tasks[2].Status)
+	}
+}
+
+func TestParseGitHubIssuesWithoutURL(t *testing.T) {
+	input := `[{"number": 3, "title": "No URL", "state": "OPEN"}]`
+	if _, err := ParseGitHubIssues(strings.NewReader(input), ""); err == nil {
+		t.Error("expected an error without url or repo")
+	}
+	tasks, err := ParseGitHubIssues(strings.NewReader(input), "acme/shop")
+	if err != nil {
+		t.Fatalf("ParseGitHubIssues: %v", err)
+	}
+	if tasks[0].ExternalID != "github:acme/shop#3" {
+		t.Errorf("external ID = %q", tasks[0].ExternalID)
+	}
+
+	if _, err := ParseGitHubIssues(strings.NewReader(`{"number": 3}`), "acme/shop"); err == nil {
+		t.Error("expected an error for a dump that isn't a list")
+	}
+}
+
+func TestWriteGitHubIssues(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	fixture, err := os.ReadFile(filepath.Join(originalDir, "testdata", "gh-issues.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	defer config.ResetPathManager()
+
+	store, err := tikistore.NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+	parse := func(data string) []*task.Task {
+		t.Helper()
+		tasks, err := ParseGitHubIssues(strings.NewReader(data), "")
+		if err != nil {
+			t.Fatalf("ParseGitHubIssues: %v", err)
+		}
+		return tasks
+	}
+
+	result, err := Write(store, parse(string(fixture)))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(result.Created) != 3 || len(result.Updated) != 0 {
+		t.Fatalf("first import: %s", result)
+	}
+
+	// edits made in tiki to fields the tracker doesn't own survive a re-import
+	first := store.GetTask(result.Created[0])
+	edited := first.Clone()
+	edited.Priority = 1
+	if err := store.UpdateTask(edited); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = Write(store, parse(string(fixture)))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if result.String() != "0 created, 0 updated, 3 unchanged" {
+		t.Errorf("re-import: %s", result)
+	}
+
+	// the issue was closed and retitled upstream
+	changed := strings.Replace(string(fixture), `"title": "Login fails on Safari"`, `"title": "Login fails on Safari 17"`, 1)
+	changed = strings.Replace(changed, `"state": "OPEN"`, `"state": "CLOSED"`, 1)
+	result, err = Write(store, parse(changed))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !reflect.DeepEqual(result.Updated, []string{first.ID}) || len(result.Unchanged) != 2 {
+		t.Fatalf("update: %s (updated %v)", result, result.Updated)
+	}
+	if len(store.GetAllTasks()) != 3 {
+		t.Errorf("got %d tikis, want 3", len(store.GetAllTasks()))
+	}
+
+	// the tiki as saved, read back from disk
+	reloaded, err := tikistore.NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+	got := reloaded.GetTask(first.ID)
+	if got == nil {
+		t.Fatalf("tiki %s not found on disk", first.ID)
+	}
+	if got.Title != "Login fails on Safari 17" || got.Status != task.StatusDone || got.Priority != 1 {
+		t.Errorf("title %q status %q priority %d", got.Title, got.Status, got.Priority)
+	}
+	if got.ExternalID != "github:acme/shop#12" || got.Fields["milestone"] != "v1.2" {
+		t.Errorf("external ID %q fields %v", got.ExternalID, got.Fields)
+	}
+}
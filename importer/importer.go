@@ -0,0 +1,184 @@
+// Package importer brings issues from other trackers into tiki. Each imported tiki keeps
+// the issue's ID in external_id, so importing the same export again updates those tikis
+// instead of creating duplicates.
+package importer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// Store is what an import reads existing tikis from and writes to
+type Store interface {
+	GetAllTasks() []*task.Task
+	CreateTask(task *task.Task) error
+	UpdateTask(task *task.Task) error
+}
+
+// Result lists the IDs of the tikis an import touched
+type Result struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+}
+
+// String summarizes the result, e.g. "3 created, 1 updated, 5 unchanged"
+func (r Result) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d unchanged", len(r.Created), len(r.Updated), len(r.Unchanged))
+}
+
+// Action is what an import does to one tiki
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the planned import of one issue
+type Change struct {
+	Action Action
+	Task   *task.Task // the tiki as it will be written; a created one has no ID yet
+}
+
+// Write creates a tiki for each imported task, or updates the tiki imported earlier with
+// the same external ID. The tracker owns what it maps (title, description, type, status,
+// tags, assignee and custom fields); priority and points set in tiki are kept.
+// Tasks are validated before any is written.
+func Write(s Store, imported []*task.Task) (Result, error) {
+	changes, err := Plan(s, imported)
+	if err != nil {
+		return Result{}, err
+	}
+	return Apply(s, changes)
+}
+
+// Plan works out what Write would do without writing anything, for a dry run. The
+// imported tasks are left unchanged; the changes hold copies.
+func Plan(s Store, imported []*task.Task) ([]Change, error) {
+	existing := make(map[string]*task.Task)
+	for _, t := range s.GetAllTasks() {
+		if t.ExternalID != "" {
+			existing[t.ExternalID] = t
+		}
+	}
+
+	changes := make([]Change, 0, len(imported))
+	planned := make(map[string]bool)
+	for _, in := range imported {
+		// planned on a copy: a dry run leaves the caller's tasks as they were read
+		t := in.Clone()
+		if t.ExternalID == "" {
+			return nil, fmt.Errorf("imported task %q has no external ID", t.Title)
+		}
+		if planned[t.ExternalID] {
+			return nil, fmt.Errorf("%s appears twice in the export", t.ExternalID)
+		}
+		planned[t.ExternalID] = true
+		// trackers without priorities or estimates leave them zero; tiki's own are kept
+		ownsPriority, ownsPoints := t.Priority != 0, t.Points != 0
+		if t.Priority == 0 {
+			t.Priority = task.DefaultPriority
+		}
+		if errs := t.Validate(); errs.HasErrors() {
+			return nil, fmt.Errorf("%s: validation failed: %s", t.ExternalID, errs.Error())
+		}
+
+		current, ok := existing[t.ExternalID]
+		if !ok {
+			changes = append(changes, Change{Action: ActionCreate, Task: t})
+			continue
+		}
+
+		updated := current.Clone()
+		updated.Title = t.Title
+		updated.Description = t.Description
+		updated.Type = t.Type
+		updated.Status = t.Status
+		updated.Tags = t.Tags
+		updated.Assignee = t.Assignee
+		if ownsPriority {
+			updated.Priority = t.Priority
+		}
+		if ownsPoints {
+			updated.Points = t.Points
+		}
+		updated.Fields = t.Fields
+		action := ActionUpdate
+		if sameImportedFields(current, updated) {
+			action = ActionUnchanged
+		}
+		changes = append(changes, Change{Action: action, Task: updated})
+	}
+	return changes, nil
+}
+
+// Apply writes planned changes
+func Apply(s Store, changes []Change) (Result, error) {
+	var result Result
+	for _, c := range changes {
+		switch c.Action {
+		case ActionCreate:
+			if err := s.CreateTask(c.Task); err != nil {
+				return result, fmt.Errorf("create %s: %w", c.Task.ExternalID, err)
+			}
+			result.Created = append(result.Created, c.Task.ID)
+		case ActionUpdate:
+			if err := s.UpdateTask(c.Task); err != nil {
+				return result, fmt.Errorf("update %s (%s): %w", c.Task.ExternalID, c.Task.ID, err)
+			}
+			result.Updated = append(result.Updated, c.Task.ID)
+		default:
+			result.Unchanged = append(result.Unchanged, c.Task.ID)
+		}
+	}
+	return result, nil
+}
+
+// FormatPreview lists planned changes one per line, for a dry run
+func FormatPreview(changes []Change) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	counts := make(map[Action]int)
+	for _, c := range changes {
+		counts[c.Action]++
+		t := c.Task
+		id := t.ID
+		if id == "" {
+			id = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\tP%d\t%s\n", c.Action, t.ExternalID, id, t.Type, t.Status, t.Priority, t.Title)
+	}
+	_ = w.Flush()
+	fmt.Fprintf(&b, "dry run: %d to create, %d to update, %d unchanged\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionUnchanged])
+	return b.String()
+}
+
+func sameImportedFields(a, b *task.Task) bool {
+	return a.Title == b.Title &&
+		strings.TrimSpace(a.Description) == strings.TrimSpace(b.Description) &&
+		a.Type == b.Type &&
+		a.Status == b.Status &&
+		sameTags(a.Tags, b.Tags) &&
+		a.Assignee == b.Assignee &&
+		a.Priority == b.Priority &&
+		a.Points == b.Points &&
+		maps.Equal(a.Fields, b.Fields)
+}
+
+// sameTags compares tags ignoring order: they are saved sorted
+func sameTags(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+// tagFromLabel turns a tracker label into a tag: tags can't hold spaces
+func tagFromLabel(label string) string {
+	return strings.Join(strings.Fields(label), "-")
+}
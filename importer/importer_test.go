@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// fakeStore holds tasks in memory and fails on writes, for plans that mustn't write
+type fakeStore struct {
+	tasks []*task.Task
+}
+
+func (s *fakeStore) GetAllTasks() []*task.Task { return s.tasks }
+
+func (s *fakeStore) CreateTask(t *task.Task) error {
+	panic("unexpected create of " + t.ExternalID)
+}
+
+func (s *fakeStore) UpdateTask(t *task.Task) error {
+	panic("unexpected update of " + t.ExternalID)
+}
+
+func TestPlan(t *testing.T) {
+	store := &fakeStore{tasks: []*task.Task{
+		{ID: "TIKI-AAA111", Title: "Old title", Type: task.TypeStory, Status: task.StatusBacklog, Priority: 2, ExternalID: "jira:A-1"},
+		{ID: "TIKI-BBB222", Title: "Same", Type: task.TypeStory, Status: task.StatusDone, Priority: 3, ExternalID: "jira:A-2"},
+		{ID: "TIKI-CCC333", Title: "Local tiki", Type: task.TypeStory, Status: task.StatusReady, Priority: 3},
+	}}
+	imported := []*task.Task{
+		{Title: "New title", Type: task.TypeStory, Status: task.StatusBacklog, ExternalID: "jira:A-1"},
+		{Title: "Same", Type: task.TypeStory, Status: task.StatusDone, ExternalID: "jira:A-2"},
+		{Title: "Brand new", Type: task.TypeBug, Status: task.StatusReady, ExternalID: "jira:A-3"},
+	}
+
+	changes, err := Plan(store, imported)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	wantActions := []Action{ActionUpdate, ActionUnchanged, ActionCreate}
+	for i, want := range wantActions {
+		if changes[i].Action != want {
+			t.Errorf("change %d = %s, want %s", i, changes[i].Action, want)
+		}
+	}
+	// an update keeps the tiki's ID, and its priority when the export has none
+	if changes[0].Task.ID != "TIKI-AAA111" || changes[0].Task.Priority != 2 || changes[0].Task.Title != "New title" {
+		t.Errorf("update = %+v", changes[0].Task)
+	}
+	if store.tasks[0].Title != "Old title" {
+		t.Error("Plan modified the stored tiki")
+	}
+	if imported[2].Priority != 0 || changes[2].Task == imported[2] {
+		t.Errorf("Plan modified the imported task: %+v", imported[2])
+	}
+
+	preview := FormatPreview(changes)
+	for _, want := range []string{"update", "TIKI-AAA111", "create", "jira:A-3", "Brand new", "dry run: 1 to create, 1 to update, 1 unchanged"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("preview missing %q:\n%s", want, preview)
+		}
+	}
+
+	duplicate := []*task.Task{
+		{Title: "One", Type: task.TypeStory, Status: task.StatusBacklog, ExternalID: "jira:A-9"},
+		{Title: "Two", Type: task.TypeStory, Status: task.StatusBacklog, ExternalID: "jira:A-9"},
+	}
+	if _, err := Plan(store, duplicate); err == nil {
+		t.Error("expected an error for an issue exported twice")
+	}
+	if _, err := Plan(store, []*task.Task{{Title: "", Type: task.TypeStory, Status: task.StatusBacklog, ExternalID: "jira:A-4"}}); err == nil {
+		t.Error("expected a validation error for an empty title")
+	}
+}
+
+func TestPlanReimportPriorityAndPoints(t *testing.T) {
+	store := &fakeStore{tasks: []*task.Task{
+		{ID: "TIKI-AAA111", Title: "Login", Type: task.TypeBug, Status: task.StatusReady, Priority: 3, Points: 2, ExternalID: "jira:A-1"},
+		{ID: "TIKI-BBB222", Title: "Docs", Type: task.TypeStory, Status: task.StatusReady, Priority: 3, Points: 2, ExternalID: "jira:A-2"},
+		{ID: "TIKI-CCC333", Title: "Same", Type: task.TypeStory, Status: task.StatusReady, Priority: 4, Points: 5, ExternalID: "jira:A-3"},
+		{ID: "TIKI-DDD444", Title: "Untracked", Type: task.TypeStory, Status: task.StatusReady, Priority: 1, Points: 3, ExternalID: "github:acme/shop#4"},
+	}}
+	imported := []*task.Task{
+		{Title: "Login", Type: task.TypeBug, Status: task.StatusReady, Priority: 1, Points: 2, ExternalID: "jira:A-1"},
+		{Title: "Docs", Type: task.TypeStory, Status: task.StatusReady, Priority: 3, Points: 8, ExternalID: "jira:A-2"},
+		{Title: "Same", Type: task.TypeStory, Status: task.StatusReady, Priority: 4, Points: 5, ExternalID: "jira:A-3"},
+		// a tracker without priorities or estimates
+		{Title: "Untracked", Type: task.TypeStory, Status: task.StatusReady, ExternalID: "github:acme/shop#4"},
+	}
+
+	changes, err := Plan(store, imported)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if changes[0].Action != ActionUpdate || changes[0].Task.Priority != 1 {
+		t.Errorf("priority change = %s %+v, want an update to priority 1", changes[0].Action, changes[0].Task)
+	}
+	if changes[1].Action != ActionUpdate || changes[1].Task.Points != 8 {
+		t.Errorf("points change = %s %+v, want an update to 8 points", changes[1].Action, changes[1].Task)
+	}
+	if changes[2].Action != ActionUnchanged {
+		t.Errorf("unchanged issue = %s, want unchanged", changes[2].Action)
+	}
+	if changes[3].Action != ActionUnchanged || changes[3].Task.Priority != 1 || changes[3].Task.Points != 3 {
+		t.Errorf("issue without priority or points = %s %+v, want the tiki's kept", changes[3].Action, changes[3].Task)
+	}
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// ParseJiraCSV reads a Jira CSV export (Filters > Export > CSV, all fields) and maps each
+// issue to a tiki through m: Summary and Description become the title and description,
+// Status, Issue Type and Priority go through the mapping, the m.Tags columns become tags,
+// the m.Points column the points and the m.Fields columns custom fields. Jira repeats a
+// column for multi-value fields such as Labels; every copy is read. Returns warnings for
+// the values m couldn't map.
+func ParseJiraCSV(r io.Reader, m *Mapping) ([]*task.Task, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse Jira CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("parse Jira CSV: empty file")
+	}
+
+	cols := newJiraColumns(records[0])
+	for _, required := range []string{"Issue key", "Summary"} {
+		if len(cols[strings.ToLower(required)]) == 0 {
+			return nil, nil, fmt.Errorf("parse Jira CSV: no %q column", required)
+		}
+	}
+
+	u := &unmapped{}
+	tasks := make([]*task.Task, 0, len(records)-1)
+	for _, record := range records[1:] {
+		key := cols.value(record, "Issue key")
+		if key == "" {
+			continue
+		}
+		t := &task.Task{
+			Title:       cols.value(record, "Summary"),
+			Description: strings.TrimSpace(strings.ReplaceAll(cols.value(record, "Description"), "\r\n", "\n")),
+			Type:        m.taskType(cols.value(record, "Issue Type"), u),
+			Status:      m.status(cols.value(record, "Status"), u),
+			Priority:    m.priority(cols.value(record, "Priority"), u),
+			Assignee:    cols.value(record, "Assignee"),
+			ExternalID:  "jira:" + key,
+		}
+
+		for _, column := range m.Tags {
+			for _, value := range cols.values(record, column) {
+				if tag := tagFromLabel(value); !slices.Contains(t.Tags, tag) {
+					t.Tags = append(t.Tags, tag)
+				}
+			}
+		}
+		if m.Points != "" {
+			t.Points = jiraPoints(cols.value(record, m.Points), u)
+		}
+		for _, field := range sortedKeys(m.Fields) {
+			if values := cols.values(record, m.Fields[field]); len(values) > 0 {
+				setField(t, field, strings.Join(values, ", "))
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, u.warnings(), nil
+}
+
+// jiraPoints reads story points, which Jira keeps as a float ("3.0"). Estimates over the
+// configured maximum are left out.
+func jiraPoints(value string, u *unmapped) int {
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		u.add("points", value, "no estimate")
+		return 0
+	}
+	points := int(math.Ceil(f))
+	if points > config.GetMaxPoints() {
+		u.add("points", value, "no estimate")
+		return 0
+	}
+	return points
+}
+
+// jiraColumns indexes a Jira CSV header by lower-cased column name
+type jiraColumns map[string][]int
+
+func newJiraColumns(header []string) jiraColumns {
+	cols := make(jiraColumns)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		cols[name] = append(cols[name], i)
+	}
+	return cols
+}
+
+// value returns the first non-empty value of a column
+func (c jiraColumns) value(record []string, column string) string {
+	values := c.values(record, column)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// values returns the non-empty values of every copy of a column
+func (c jiraColumns) values(record []string, column string) []string {
+	var values []string
+	for _, i := range c[strings.ToLower(column)] {
+		if i < len(record) {
+			if v := strings.TrimSpace(record[i]); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
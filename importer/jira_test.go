@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func TestParseJiraCSV(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "jira.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	tasks, warnings, err := ParseJiraCSV(f, DefaultJiraMapping())
+	if err != nil {
+		t.Fatalf("ParseJiraCSV: %v", err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("got %d tasks, want 4", len(tasks))
+	}
+
+	bug := tasks[0]
+	if bug.ExternalID != "jira:SHOP-12" || bug.Title != "Login fails on Safari" {
+		t.Errorf("external ID %q title %q", bug.ExternalID, bug.Title)
+	}
+	if bug.Description != "The session cookie is dropped\nafter the redirect." {
+		t.Errorf("description = %q", bug.Description)
+	}
+	if bug.Type != task.TypeBug || bug.Status != task.StatusInProgress || bug.Priority != 2 || bug.Points != 3 {
+		t.Errorf("type %q status %q priority %d points %d", bug.Type, bug.Status, bug.Priority, bug.Points)
+	}
+	// both Labels columns and the components
+	if !reflect.DeepEqual(bug.Tags, []string{"auth", "ui", "Web-App"}) {
+		t.Errorf("tags = %v", bug.Tags)
+	}
+	wantFields := map[string]string{"sprint": "Sprint 14", "reporter": "carol", "created": "2021-03-04 10:00"}
+	if bug.Assignee != "alice" || !reflect.DeepEqual(bug.Fields, wantFields) {
+		t.Errorf("assignee %q fields %v", bug.Assignee, bug.Fields)
+	}
+
+	done := tasks[2]
+	if done.Type != task.TypeStory || done.Status != task.StatusDone || done.Priority != 5 {
+		t.Errorf("type %q status %q priority %d", done.Type, done.Status, done.Priority)
+	}
+	if done.Points != 0 {
+		t.Errorf("points over the maximum should be dropped, got %d", done.Points)
+	}
+
+	want := []string{
+		`points "40" is not mapped, using no estimate (1 issue)`,
+		`priority "P0" is not mapped, using 3 (1 issue)`,
+		`status "Blocked" is not mapped, using backlog (1 issue)`,
+		`status "Selected for Development" is not mapped, using backlog (1 issue)`,
+		`type "Incident" is not mapped, using story (1 issue)`,
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestParseJiraCSVMapping(t *testing.T) {
+	mappingPath := filepath.Join(t.TempDir(), "mapping.yaml")
+	mapping := "status:\n  Selected for Development: ready\n  blocked: in_progress\ntype:\n  Incident: bug\npriority:\n  P0: 1\ntags: [Sprint]\n"
+	if err := os.WriteFile(mappingPath, []byte(mapping), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := DefaultJiraMapping()
+	if err := m.LoadMapping(mappingPath); err != nil {
+		t.Fatalf("LoadMapping: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join("testdata", "jira.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	tasks, warnings, err := ParseJiraCSV(f, m)
+	if err != nil {
+		t.Fatalf("ParseJiraCSV: %v", err)
+	}
+
+	if tasks[1].Status != task.StatusReady {
+		t.Errorf("mapped status = %q, want ready", tasks[1].Status)
+	}
+	// mapping keys match regardless of case
+	incident := tasks[3]
+	if incident.Status != task.StatusInProgress || incident.Type != task.TypeBug || incident.Priority != 1 {
+		t.Errorf("status %q type %q priority %d", incident.Status, incident.Type, incident.Priority)
+	}
+	// the file's tag columns replace the default ones
+	if !reflect.DeepEqual(tasks[0].Tags, []string{"Sprint-14"}) {
+		t.Errorf("tags = %v", tasks[0].Tags)
+	}
+	// the default priorities are kept
+	if tasks[0].Priority != 2 {
+		t.Errorf("priority = %d, want 2", tasks[0].Priority)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], `points "40"`) {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+func TestParseJiraCSVErrors(t *testing.T) {
+	if _, _, err := ParseJiraCSV(strings.NewReader(""), DefaultJiraMapping()); err == nil {
+		t.Error("expected an error for an empty file")
+	}
+	if _, _, err := ParseJiraCSV(strings.NewReader("Key,Title\nA-1,x\n"), DefaultJiraMapping()); err == nil {
+		t.Error("expected an error without the Issue key column")
+	}
+	// a byte order mark before the first column
+	tasks, _, err := ParseJiraCSV(strings.NewReader("\ufeffSummary,Issue key\nFirst,A-1\n"), DefaultJiraMapping())
+	if err != nil {
+		t.Fatalf("ParseJiraCSV: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "First" {
+		t.Errorf("tasks = %v", tasks)
+	}
+}
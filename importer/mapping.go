@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/task"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping says how the values of a Jira or Trello export become tiki fields. A mapping
+// file is YAML with the same keys; what it sets adds to or replaces the defaults:
+//
+//	status:              # Jira status or Trello list -> tiki status
+//	  Selected for Development: ready
+//	  Doing: in_progress
+//	type:                # Jira issue type or Trello label -> tiki type
+//	  Improvement: story
+//	priority:            # Jira priority or Trello label -> 1 (highest) to 5
+//	  Blocker: 1
+//	tags: [Labels, Component/s]   # Jira columns whose values become tags
+//	points: Custom field (Story Points)
+//	fields:              # Jira columns kept as custom fields
+//	  sprint: Sprint
+//
+// Values missing from the mapping are read as tiki values when they can be ("In Progress",
+// "Bug", "High"); the rest fall back to backlog, story and medium and are reported.
+type Mapping struct {
+	Status   map[string]string             `yaml:"status"`
+	Type     map[string]string             `yaml:"type"`
+	Priority map[string]task.PriorityValue `yaml:"priority"`
+	Tags     []string                      `yaml:"tags"`
+	Points   string                        `yaml:"points"`
+	Fields   map[string]string             `yaml:"fields"`
+}
+
+// DefaultJiraMapping maps Jira's stock priorities and columns
+func DefaultJiraMapping() *Mapping {
+	return &Mapping{
+		Status: map[string]string{},
+		Type:   map[string]string{"Sub-task": "story", "Subtask": "story"},
+		Priority: map[string]task.PriorityValue{
+			"Highest": 1, "Blocker": 1, "Critical": 1,
+			"High": 2, "Major": 2,
+			"Medium": 3,
+			"Low":    4, "Minor": 4,
+			"Lowest": 5, "Trivial": 5,
+		},
+		Tags:   []string{"Labels", "Component/s"},
+		Points: "Custom field (Story Points)",
+		Fields: map[string]string{
+			"sprint":      "Sprint",
+			"fix_version": "Fix Version/s",
+			"reporter":    "Reporter",
+			"created":     "Created",
+			"resolved":    "Resolved",
+		},
+	}
+}
+
+// DefaultTrelloMapping maps the lists of Trello's board templates
+func DefaultTrelloMapping() *Mapping {
+	return &Mapping{
+		Status: map[string]string{
+			"Doing":             "in_progress",
+			"Things to do":      "ready",
+			"Up next":           "ready",
+			"Done!":             "done",
+			"Ideas":             "backlog",
+			"Project Resources": "backlog",
+		},
+		Type:     map[string]string{},
+		Priority: map[string]task.PriorityValue{},
+	}
+}
+
+// LoadMapping reads a mapping file over m
+func (m *Mapping) LoadMapping(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read mapping: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(m); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse mapping %s: %w", path, err)
+	}
+	for from, to := range m.Status {
+		if _, ok := task.ParseStatus(to); !ok || to == "" {
+			return fmt.Errorf("mapping %s: status %q of %q is not a tiki status", path, to, from)
+		}
+	}
+	for from, to := range m.Type {
+		if _, ok := task.ParseType(to); !ok {
+			return fmt.Errorf("mapping %s: type %q of %q is not a tiki type", path, to, from)
+		}
+	}
+	return nil
+}
+
+// status maps a status or list name, reporting values it can't read to u
+func (m *Mapping) status(value string, u *unmapped) task.Status {
+	if to, ok := lookup(m.Status, value); ok {
+		return task.NormalizeStatus(to)
+	}
+	status, ok := task.ParseStatus(value)
+	if !ok {
+		u.add("status", value, string(status))
+	}
+	return status
+}
+
+// taskType maps an issue type, reporting values it can't read to u
+func (m *Mapping) taskType(value string, u *unmapped) task.Type {
+	if value == "" {
+		return task.TypeStory
+	}
+	if to, ok := lookup(m.Type, value); ok {
+		return task.NormalizeType(to)
+	}
+	taskType, ok := task.ParseType(value)
+	if !ok {
+		u.add("type", value, string(taskType))
+	}
+	return taskType
+}
+
+// priority maps a priority name, reporting values it can't read to u
+func (m *Mapping) priority(value string, u *unmapped) int {
+	if value == "" {
+		return task.DefaultPriority
+	}
+	if to, ok := lookup(m.Priority, value); ok {
+		return int(to)
+	}
+	if p := task.NormalizePriority(value); p > 0 {
+		return p
+	}
+	u.add("priority", value, fmt.Sprint(task.DefaultPriority))
+	return task.DefaultPriority
+}
+
+// lookup finds key in m, ignoring case when there's no exact match
+func lookup[V any](m map[string]V, key string) (V, bool) {
+	key = strings.TrimSpace(key)
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// unmapped collects the export values a mapping couldn't read, with how often each appeared
+type unmapped struct {
+	counts map[string]int
+	order  []string
+}
+
+func (u *unmapped) add(kind, value, fallback string) {
+	if u.counts == nil {
+		u.counts = make(map[string]int)
+	}
+	key := fmt.Sprintf("%s %q is not mapped, using %s", kind, value, fallback)
+	if u.counts[key] == 0 {
+		u.order = append(u.order, key)
+	}
+	u.counts[key]++
+}
+
+// warnings lists the unmapped values, e.g. `status "Blocked" is not mapped, using backlog (4 issues)`
+func (u *unmapped) warnings() []string {
+	warnings := make([]string, 0, len(u.order))
+	for _, key := range u.order {
+		n := u.counts[key]
+		noun := "issues"
+		if n == 1 {
+			noun = "issue"
+		}
+		warnings = append(warnings, fmt.Sprintf("%s (%d %s)", key, n, noun))
+	}
+	slices.Sort(warnings)
+	return warnings
+}
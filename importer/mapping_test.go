@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMappingErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown status", "status:\n  Doing: working\n"},
+		{"empty status", "status:\n  Doing: \"\"\n"},
+		{"unknown type", "type:\n  Incident: outage\n"},
+		{"misspelt key", "statuses:\n  Doing: in_progress\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mapping.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := DefaultJiraMapping().LoadMapping(path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := DefaultJiraMapping()
+	if err := m.LoadMapping(empty); err != nil {
+		t.Errorf("empty mapping: %v", err)
+	}
+	if m.Priority["Highest"] != 1 {
+		t.Error("an empty mapping should keep the defaults")
+	}
+}
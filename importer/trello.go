@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// trelloBoard is the part of a Trello board export (Menu > Print, export and share >
+// Export as JSON) an import reads
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID        string  `json:"id"`
+		ShortLink string  `json:"shortLink"`
+		Name      string  `json:"name"`
+		Desc      string  `json:"desc"`
+		IDList    string  `json:"idList"`
+		Closed    bool    `json:"closed"`
+		Due       *string `json:"due"`
+		Labels    []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		IDMembers []string `json:"idMembers"`
+	} `json:"cards"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Checklists []struct {
+		IDCard     string            `json:"idCard"`
+		Name       string            `json:"name"`
+		CheckItems []trelloCheckItem `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"` // "complete" or "incomplete"
+	Pos   float64 `json:"pos"`
+}
+
+// ParseTrelloBoard reads a Trello board JSON export and maps each card to a tiki: the
+// card's list gives its status through m, archived cards are done, labels become tags and
+// a label the mapping names as a type or priority sets it. The first member is the
+// assignee, checklists are appended to the description as task lists and the due date
+// goes to the "due" field. Returns warnings for the lists m couldn't map.
+func ParseTrelloBoard(r io.Reader, m *Mapping) ([]*task.Task, []string, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, nil, fmt.Errorf("parse Trello board: %w", err)
+	}
+
+	lists := make(map[string]string)
+	archivedLists := make(map[string]bool)
+	for _, l := range board.Lists {
+		lists[l.ID] = l.Name
+		archivedLists[l.ID] = l.Closed
+	}
+	members := make(map[string]string)
+	for _, member := range board.Members {
+		members[member.ID] = member.Username
+	}
+
+	u := &unmapped{}
+	tasks := make([]*task.Task, 0, len(board.Cards))
+	for _, card := range board.Cards {
+		externalID := card.ShortLink
+		if externalID == "" {
+			externalID = card.ID
+		}
+		t := &task.Task{
+			Title:       strings.TrimSpace(card.Name),
+			Description: strings.TrimSpace(card.Desc),
+			Type:        task.TypeStory,
+			Priority:    task.DefaultPriority,
+			ExternalID:  "trello:" + externalID,
+		}
+		if card.Closed || archivedLists[card.IDList] {
+			t.Status = task.StatusDone
+		} else {
+			t.Status = m.status(lists[card.IDList], u)
+		}
+
+		for _, label := range card.Labels {
+			name := strings.TrimSpace(label.Name)
+			if name == "" {
+				continue // a colour without a name
+			}
+			t.Tags = append(t.Tags, tagFromLabel(name))
+			if to, ok := lookup(m.Type, name); ok {
+				t.Type = task.NormalizeType(to)
+			} else if taskType, ok := task.ParseType(name); ok {
+				t.Type = taskType
+			}
+			if to, ok := lookup(m.Priority, name); ok {
+				t.Priority = int(to)
+			}
+		}
+
+		for _, id := range card.IDMembers {
+			if login := members[id]; login != "" {
+				t.Assignee = login
+				break
+			}
+		}
+		if card.Due != nil && *card.Due != "" {
+			setField(t, "due", *card.Due)
+		}
+
+		for _, checklist := range board.Checklists {
+			if checklist.IDCard != card.ID {
+				continue
+			}
+			t.Description = strings.TrimSpace(t.Description + "\n\n" + formatChecklist(checklist.Name, checklist.CheckItems))
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, u.warnings(), nil
+}
+
+// formatChecklist renders a Trello checklist as a markdown task list under its name
+func formatChecklist(name string, items []trelloCheckItem) string {
+	items = slices.Clone(items)
+	slices.SortStableFunc(items, func(a, b trelloCheckItem) int {
+		return cmp.Compare(a.Pos, b.Pos)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n", name)
+	for _, item := range items {
+		mark := " "
+		if item.State == "complete" {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", mark, item.Name)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
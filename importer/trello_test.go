@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func TestParseTrelloBoard(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "trello.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	m := DefaultTrelloMapping()
+	m.Priority["urgent"] = 1
+	tasks, warnings, err := ParseTrelloBoard(f, m)
+	if err != nil {
+		t.Fatalf("ParseTrelloBoard: %v", err)
+	}
+	if len(tasks) != 5 {
+		t.Fatalf("got %d tasks, want 5", len(tasks))
+	}
+
+	card := tasks[0]
+	if card.ExternalID != "trello:aB3dE5" {
+		t.Errorf("external ID = %q", card.ExternalID)
+	}
+	if card.Status != task.StatusInProgress || card.Type != task.TypeBug || card.Priority != 1 {
+		t.Errorf("status %q type %q priority %d", card.Status, card.Type, card.Priority)
+	}
+	if !reflect.DeepEqual(card.Tags, []string{"Bug", "Urgent"}) {
+		t.Errorf("tags = %v", card.Tags)
+	}
+	if card.Assignee != "bob" || card.Fields["due"] != "2021-06-01T12:00:00.000Z" {
+		t.Errorf("assignee %q fields %v", card.Assignee, card.Fields)
+	}
+	wantDesc := "The session cookie is dropped\n\n### Steps\n- [x] Reproduce\n- [ ] Fix the cookie"
+	if card.Description != wantDesc {
+		t.Errorf("description = %q, want %q", card.Description, wantDesc)
+	}
+
+	wantStatus := []task.Status{task.StatusInProgress, task.StatusReady, task.StatusDone, task.StatusBacklog, task.StatusDone}
+	for i, want := range wantStatus {
+		if tasks[i].Status != want {
+			t.Errorf("card %d status = %q, want %q", i, tasks[i].Status, want)
+		}
+	}
+	if !reflect.DeepEqual(warnings, []string{`status "Parked" is not mapped, using backlog (1 issue)`}) {
+		t.Errorf("warnings = %q", warnings)
+	}
+
+	if _, _, err := ParseTrelloBoard(strings.NewReader("[]"), m); err == nil {
+		t.Error("expected an error for a file that isn't a board")
+	}
+}
@@ -3,50 +3,74 @@ package background
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/rivo/tview"
 
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin/filter"
 	"github.com/boolean-maybe/tiki/store"
 	"github.com/boolean-maybe/tiki/store/tikistore"
+	"github.com/boolean-maybe/tiki/task"
 )
 
-// StartBurndownHistoryBuilder starts a background job to build burndown history
-// and publish results into HeaderConfig.
+// burndownConfigurer is a store that builds its burndown itself, such as the sqlite store
+// from its revisions, once told the sprint, scope and unit
+type burndownConfigurer interface {
+	SetBurndownOptions(options store.BurndownOptions)
+}
+
+// StartBurndownHistoryBuilder starts a background job to build the burndown history of
+// each repository's task store and publish the combined burndown of taskStore into
+// HeaderConfig.
 func StartBurndownHistoryBuilder(
 	ctx context.Context,
-	tikiStore *tikistore.TikiStore,
+	taskStore store.Store,
+	repos []*tikistore.TikiStore,
 	headerConfig *model.HeaderConfig,
 	app *tview.Application,
 ) {
 	go func() {
-		select {
-		case <-ctx.Done():
-			return
-		default:
+		options := burndownOptions(taskStore)
+		built := false
+		if configurer, ok := taskStore.(burndownConfigurer); ok {
+			configurer.SetBurndownOptions(options)
+			built = true
 		}
+		for _, repo := range repos {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
 
-		gitUtil := tikiStore.GetGitOps()
-		if gitUtil == nil {
-			slog.Warn("skipping burndown: git not available")
-			return
-		}
+			gitUtil := repo.GetGitOps()
+			if gitUtil == nil {
+				slog.Warn("skipping burndown: git not available", "dir", repo.Dir())
+				continue
+			}
 
-		history := store.NewTaskHistory(config.GetTaskDir(), gitUtil)
-		if history == nil {
-			return
-		}
+			history := store.NewTaskHistory(repo.Dir(), gitUtil)
+			if history == nil {
+				continue
+			}
+			history.SetBurndownOptions(options)
 
-		slog.Info("building burndown history in background")
-		if err := history.Build(); err != nil {
-			slog.Warn("failed to build task history", "error", err)
+			slog.Info("building burndown history in background", "dir", repo.Dir())
+			if err := history.Build(); err != nil {
+				slog.Warn("failed to build task history", "dir", repo.Dir(), "error", err)
+				continue
+			}
+
+			slog.Info("burndown history built successfully", "dir", repo.Dir())
+			repo.SetTaskHistory(history)
+			built = true
+		}
+		if !built {
 			return
 		}
 
-		slog.Info("burndown history built successfully")
-		tikiStore.SetTaskHistory(history)
-
 		select {
 		case <-ctx.Done():
 			return
@@ -54,7 +78,34 @@ func StartBurndownHistoryBuilder(
 		}
 
 		app.QueueUpdateDraw(func() {
-			headerConfig.SetBurndown(history.Burndown())
+			headerConfig.SetBurndown(taskStore.GetBurndown())
 		})
 	}()
 }
+
+// burndownOptions reads the sprint, scope and unit from config. Invalid settings are
+// logged and fall back to the defaults so the header chart still renders.
+func burndownOptions(taskStore store.Store) store.BurndownOptions {
+	options := store.BurndownOptions{Points: config.GetBurndownPoints()}
+
+	start, end, err := config.GetBurndownSprint()
+	if err != nil {
+		slog.Warn("ignoring burndown sprint", "error", err)
+	} else {
+		options.Start, options.End = start, end
+	}
+
+	if expr := config.GetBurndownFilter(); expr != "" {
+		scope, err := filter.ParseFilter(expr)
+		if err != nil {
+			slog.Warn("ignoring burndown filter", "filter", expr, "error", err)
+		} else if scope != nil {
+			currentUser, _, _ := taskStore.GetCurrentUser()
+			options.Scope = func(t *task.Task) bool {
+				return scope.Evaluate(t, time.Now(), currentUser)
+			}
+		}
+	}
+
+	return options
+}
@@ -11,9 +11,10 @@ import (
 
 // Controllers holds all application controllers.
 type Controllers struct {
-	Nav     *controller.NavigationController
-	Task    *controller.TaskController
-	Plugins map[string]controller.PluginControllerInterface
+	Nav        *controller.NavigationController
+	Task       *controller.TaskController
+	Plugins    map[string]controller.PluginControllerInterface
+	References *controller.ReferenceController
 }
 
 // BuildControllers constructs navigation/domain/plugin controllers for the application.
@@ -38,13 +39,18 @@ func BuildControllers(
 			continue
 		}
 		if dp, ok := p.(*plugin.DokiPlugin); ok {
-			pluginControllers[p.GetName()] = controller.NewDokiController(dp, navController)
+			pluginControllers[p.GetName()] = controller.NewDokiController(dp, navController, taskStore)
+			continue
+		}
+		if cp, ok := p.(*plugin.ChartPlugin); ok {
+			pluginControllers[p.GetName()] = controller.NewChartController(cp, navController)
 		}
 	}
 
 	return &Controllers{
-		Nav:     navController,
-		Task:    taskController,
-		Plugins: pluginControllers,
+		Nav:        navController,
+		Task:       taskController,
+		Plugins:    pluginControllers,
+		References: controller.NewReferenceController(navController, plugins),
 	}
 }
@@ -27,7 +27,7 @@ type Result struct {
 	// Fields include: OS, Architecture, TermType, DetectedTheme, ColorSupport, ColorCount.
 	// Collected early using terminfo lookup (no screen initialization needed).
 	SystemInfo       *sysinfo.SystemInfo
-	TikiStore        *tikistore.TikiStore
+	TikiStore        *tikistore.TikiStore // nil when the store backend has no TikiStore
 	TaskStore        store.Store
 	HeaderConfig     *model.HeaderConfig
 	LayoutModel      *model.LayoutModel
@@ -90,7 +90,7 @@ func Bootstrap(tikiSkillContent, dokiSkillContent string) (*Result, error) {
 
 	// Phase 5: Model initialization
 	headerConfig, layoutModel := InitHeaderAndLayoutModels()
-	InitHeaderBaseStats(headerConfig, tikiStore)
+	InitHeaderBaseStats(headerConfig, taskStore)
 
 	// Phase 6: Plugin system
 	plugins, err := LoadPlugins()
@@ -123,16 +123,17 @@ func Bootstrap(tikiSkillContent, dokiSkillContent string) (*Result, error) {
 	// Phase 9: View factory and layout
 	viewFactory := view.NewViewFactory(taskStore)
 	viewFactory.SetPlugins(pluginConfigs, pluginDefs, controllers.Plugins)
+	viewFactory.SetUpdateQueue(func(f func()) { application.QueueUpdateDraw(f) })
 
 	headerWidget := header.NewHeaderWidget(headerConfig)
 	rootLayout := view.NewRootLayout(headerWidget, headerConfig, layoutModel, viewFactory, taskStore, application)
 
 	// Phase 10: View wiring
-	wireOnViewActivated(rootLayout, application)
+	wireOnViewActivated(rootLayout, application, controllers.References)
 
 	// Phase 11: Background tasks
 	ctx, cancel := context.WithCancel(context.Background())
-	background.StartBurndownHistoryBuilder(ctx, tikiStore, headerConfig, application)
+	background.StartBurndownHistoryBuilder(ctx, taskStore, repoStores(taskStore), headerConfig, application)
 
 	// Phase 12: Navigation and input wiring
 	wireNavigation(controllers.Nav, layoutModel, rootLayout)
@@ -195,8 +196,8 @@ func convertPluginActions(registry *controller.ActionRegistry) []model.HeaderAct
 	return result
 }
 
-// wireOnViewActivated wires focus setters into views as they become active.
-func wireOnViewActivated(rootLayout *view.RootLayout, app *tview.Application) {
+// wireOnViewActivated wires focus setters and cross-reference links into views as they become active.
+func wireOnViewActivated(rootLayout *view.RootLayout, app *tview.Application, references *controller.ReferenceController) {
 	rootLayout.SetOnViewActivated(func(v controller.View) {
 		// generic focus settable check (covers TaskEditView and any other view with focus needs)
 		if focusSettable, ok := v.(controller.FocusSettable); ok {
@@ -204,6 +205,12 @@ func wireOnViewActivated(rootLayout *view.RootLayout, app *tview.Application) {
 				app.SetFocus(p)
 			})
 		}
+		if taskLinkView, ok := v.(controller.TaskLinkView); ok {
+			taskLinkView.SetTaskLinkHandler(references.OpenTask)
+		}
+		if dokiLinkView, ok := v.(controller.DokiLinkView); ok {
+			dokiLinkView.SetDokiLinkHandler(references.OpenDokiPage)
+		}
 	})
 }
 
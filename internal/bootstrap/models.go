@@ -3,7 +3,7 @@ package bootstrap
 import (
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/model"
-	"github.com/boolean-maybe/tiki/store/tikistore"
+	"github.com/boolean-maybe/tiki/store"
 )
 
 // InitHeaderAndLayoutModels creates the header config and layout model with
@@ -21,11 +21,11 @@ func InitHeaderAndLayoutModels() (*model.HeaderConfig, *model.LayoutModel) {
 }
 
 // InitHeaderBaseStats initializes base header stats that are always visible regardless of view.
-func InitHeaderBaseStats(headerConfig *model.HeaderConfig, tikiStore *tikistore.TikiStore) {
+func InitHeaderBaseStats(headerConfig *model.HeaderConfig, taskStore store.Store) {
 	headerConfig.SetBaseStat("Version", config.Version, 0)
 	headerConfig.SetBaseStat("Mode", "kanban", 1)
 	headerConfig.SetBaseStat("Store", "local", 2)
-	for _, stat := range tikiStore.GetStats() {
+	for _, stat := range taskStore.GetStats() {
 		headerConfig.SetBaseStat(stat.Name, stat.Value, stat.Order)
 	}
 }
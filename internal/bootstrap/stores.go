@@ -2,18 +2,45 @@ package bootstrap
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/store"
 	"github.com/boolean-maybe/tiki/store/tikistore"
 )
 
-// InitStores initializes the task stores.
-// Returns the tikiStore, a generic store interface, and any error.
+// InitStores opens the task store with the backend configured by store.backend.
+// Returns the TikiStore over the project's task directory, or the first repository's in a
+// workspace (nil for backends without one), the store itself, and any error.
 func InitStores() (*tikistore.TikiStore, store.Store, error) {
-	tikiStore, err := tikistore.NewTikiStore(config.GetTaskDir())
+	backend := config.GetStoreBackend()
+	dirs := config.GetStoreDirs()
+	if backend == store.DefaultBackend && len(dirs) > 1 {
+		slog.Warn("store.dirs is only read by the multidir backend", "backend", backend)
+	}
+
+	taskStore, err := store.OpenBackend(backend, store.BackendOptions{Dirs: dirs})
 	if err != nil {
 		return nil, nil, fmt.Errorf("initialize task store: %w", err)
 	}
-	return tikiStore, tikiStore, nil
+
+	var tikiStore *tikistore.TikiStore
+	if repos := repoStores(taskStore); len(repos) > 0 {
+		tikiStore = repos[0]
+	}
+	return tikiStore, taskStore, nil
+}
+
+// repoStores returns the TikiStore of each task directory behind a store, which build
+// their own burndown histories
+func repoStores(taskStore store.Store) []*tikistore.TikiStore {
+	switch s := taskStore.(type) {
+	case *tikistore.TikiStore:
+		return []*tikistore.TikiStore{s}
+	case *tikistore.MultiDirStore:
+		return s.Stores()
+	case *tikistore.WorkspaceStore:
+		return s.Repos()
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/internal/bootstrap"
+	taskpkg "github.com/boolean-maybe/tiki/task"
 )
 
 // IsPipedInput reports whether stdin is connected to a pipe or redirected file
@@ -39,7 +40,7 @@ func HasPositionalArgs(args []string) bool {
 			return true
 		}
 		if strings.HasPrefix(arg, "-") {
-			if arg == "--log-level" {
+			if arg == "--log-level" || arg == "--template" {
 				skipNext = true
 			}
 			continue
@@ -49,9 +50,29 @@ func HasPositionalArgs(args []string) bool {
 	return false
 }
 
-// CreateTaskFromReader reads piped input, parses it into title/description,
-// and creates a new tiki task. Returns the task ID (e.g. "TIKI-ABC123").
-func CreateTaskFromReader(r io.Reader) (string, error) {
+// TemplateFlag returns the value of --template (or --template=name) in args, the named
+// template to create the task from. Empty when the flag isn't given.
+func TemplateFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--template" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, "--template="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// CreateTasksFromReader reads piped input and creates a tiki for each task in it, starting
+// from the named template (new.md when empty). The input is plain text (title on the first
+// line), tiki markdown with frontmatter, or a YAML/JSON list; see parseTasks for batches.
+// Every task is validated before any is created. Returns the created IDs (e.g. "TIKI-ABC123"),
+// including those created before a failure.
+func CreateTasksFromReader(r io.Reader, template string) ([]string, error) {
 	// Suppress info/debug logs for the non-interactive pipe path.
 	// The pipe path bypasses bootstrap (which normally configures logging),
 	// so the default slog handler would write INFO+ messages to stderr.
@@ -61,46 +82,76 @@ func CreateTaskFromReader(r io.Reader) (string, error) {
 
 	data, err := io.ReadAll(r)
 	if err != nil {
-		return "", fmt.Errorf("read input: %w", err)
+		return nil, fmt.Errorf("read input: %w", err)
 	}
 
 	input := strings.TrimSpace(string(data))
 	if input == "" {
-		return "", fmt.Errorf("empty input: title is required")
+		return nil, fmt.Errorf("empty input: title is required")
 	}
 
-	title, description := parseInput(input)
+	piped, err := parseTasks(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(piped) == 0 {
+		return nil, fmt.Errorf("empty input: no tasks found")
+	}
 
 	if err := bootstrap.EnsureGitRepo(); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !config.IsProjectInitialized() {
-		return "", fmt.Errorf("project not initialized: run 'tiki init' first")
+		return nil, fmt.Errorf("project not initialized: run 'tiki init' first")
 	}
 
-	tikiStore, _, err := bootstrap.InitStores()
+	_, taskStore, err := bootstrap.InitStores()
 	if err != nil {
-		return "", fmt.Errorf("initialize store: %w", err)
+		return nil, fmt.Errorf("initialize store: %w", err)
 	}
 
-	task, err := tikiStore.NewTaskTemplate()
-	if err != nil {
-		return "", fmt.Errorf("create task template: %w", err)
-	}
-
-	task.Title = title
-	task.Description = description
+	tasks := make([]*taskpkg.Task, 0, len(piped))
+	ids := make(map[string]bool, len(piped))
+	for i, pt := range piped {
+		// prompt fields can't be asked for with stdin taken by the pipe: they stay empty
+		task, err := taskStore.NewTaskFromTemplate(template, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create task template: %w", err)
+		}
+		// IDs are checked against files on disk, not the rest of the batch
+		for ids[task.ID] {
+			if task, err = taskStore.NewTaskFromTemplate(template, nil); err != nil {
+				return nil, fmt.Errorf("create task template: %w", err)
+			}
+		}
+		ids[task.ID] = true
 
-	if errs := task.Validate(); errs.HasErrors() {
-		return "", fmt.Errorf("validation failed: %s", errs.Error())
+		if err := pt.apply(task); err != nil {
+			return nil, fmt.Errorf("%s: %w", describeTask(i, len(piped), pt), err)
+		}
+		if errs := task.Validate(); errs.HasErrors() {
+			return nil, fmt.Errorf("%s: validation failed: %s", describeTask(i, len(piped), pt), errs.Error())
+		}
+		tasks = append(tasks, task)
 	}
 
-	if err := tikiStore.CreateTask(task); err != nil {
-		return "", fmt.Errorf("create task: %w", err)
+	created := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if err := taskStore.CreateTask(task); err != nil {
+			return created, fmt.Errorf("create task %q: %w", task.Title, err)
+		}
+		created = append(created, task.ID)
 	}
+	return created, nil
+}
 
-	return task.ID, nil
+// describeTask names a piped task in errors: its title, and its position in a batch
+func describeTask(i, count int, pt pipedTask) string {
+	if count == 1 {
+		return fmt.Sprintf("task %q", pt.Title)
+	}
+	return fmt.Sprintf("task %d (%q)", i+1, pt.Title)
 }
 
 // parseInput splits piped text into title and description.
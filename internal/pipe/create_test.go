@@ -81,6 +81,8 @@ func TestHasPositionalArgs(t *testing.T) {
 		{name: "stdin dash", args: []string{"-"}, want: true},
 		{name: "flag then positional", args: []string{"--log-level", "debug", "file.md"}, want: true},
 		{name: "double dash", args: []string{"--", "file.md"}, want: true},
+		{name: "template flag with value", args: []string{"--template", "bug"}, want: false},
+		{name: "template=value", args: []string{"--template=bug"}, want: false},
 	}
 
 	for _, tt := range tests {
@@ -91,3 +93,25 @@ func TestHasPositionalArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no flag", args: []string{"--log-level", "debug"}, want: ""},
+		{name: "flag with value", args: []string{"--template", "bug"}, want: "bug"},
+		{name: "flag=value", args: []string{"--log-level=info", "--template=spike"}, want: "spike"},
+		{name: "missing value", args: []string{"--template"}, want: ""},
+		{name: "after double dash", args: []string{"--", "--template", "bug"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TemplateFlag(tt.args); got != tt.want {
+				t.Errorf("TemplateFlag(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
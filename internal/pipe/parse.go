@@ -0,0 +1,174 @@
+package pipe
+
+import (
+	"fmt"
+	"strings"
+
+	taskpkg "github.com/boolean-maybe/tiki/task"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pipedTask is one task read from piped input: an item of a YAML or JSON list, or a tiki
+// markdown document whose frontmatter holds the fields and whose body is the description
+type pipedTask struct {
+	Title       string                `yaml:"title"`
+	Description string                `yaml:"description"`
+	Type        string                `yaml:"type"`
+	Status      string                `yaml:"status"`
+	Tags        taskpkg.TagsValue     `yaml:"tags"`
+	Assignee    string                `yaml:"assignee"`
+	Priority    taskpkg.PriorityValue `yaml:"priority"`
+	Points      int                   `yaml:"points"`
+}
+
+// parseTasks reads piped input as one or more tasks, given as:
+//   - a YAML or JSON list of tasks (items can be plain titles), or a single JSON object
+//   - tiki markdown with frontmatter; in a batch each task starts with its own frontmatter
+//   - plain text: a single task, title first; --- lines are part of the description
+func parseTasks(input string) ([]pipedTask, error) {
+	first, _, _ := strings.Cut(input, "\n")
+	first = strings.TrimSpace(first)
+
+	switch {
+	case looksLikeJSON(input):
+		return parseTaskList(input)
+	case first == "-" || strings.HasPrefix(first, "- "):
+		// a markdown list item is also a title: only a list that parses is a batch
+		if tasks, err := parseTaskList(input); err == nil {
+			return tasks, nil
+		}
+	case first == "---":
+		return parseMarkdownTasks(input)
+	}
+	return parsePlainTask(input), nil
+}
+
+// looksLikeJSON reports whether input opens like a JSON object or list rather than
+// a title such as "[ui] fix the menu"
+func looksLikeJSON(input string) bool {
+	if strings.HasPrefix(input, "{") {
+		return true
+	}
+	if !strings.HasPrefix(input, "[") {
+		return false
+	}
+	rest := strings.TrimLeft(input[1:], " \t\r\n")
+	return rest == "" || strings.ContainsAny(rest[:1], `{["]`)
+}
+
+// parseTaskList parses a YAML or JSON list of tasks; JSON is read as YAML
+func parseTaskList(input string) ([]pipedTask, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(input), &root); err != nil {
+		return nil, fmt.Errorf("parse task list: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	doc := root.Content[0]
+	items := []*yaml.Node{doc}
+	if doc.Kind == yaml.SequenceNode {
+		items = doc.Content
+	}
+
+	tasks := make([]pipedTask, 0, len(items))
+	for i, item := range items {
+		var pt pipedTask
+		switch item.Kind {
+		case yaml.ScalarNode:
+			pt.Title = item.Value
+		case yaml.MappingNode:
+			if err := item.Decode(&pt); err != nil {
+				return nil, fmt.Errorf("task %d: %w", i+1, err)
+			}
+		default:
+			return nil, fmt.Errorf("task %d: expected a title or an object", i+1)
+		}
+		tasks = append(tasks, pt)
+	}
+	return tasks, nil
+}
+
+// parseMarkdownTasks parses tiki markdown documents following each other. A --- line
+// opens and closes each frontmatter, so a description can't hold --- lines of its own.
+func parseMarkdownTasks(input string) ([]pipedTask, error) {
+	chunks := splitOnSeparators(input)
+	// chunks[0] is what comes before the first ---, empty by construction
+	var tasks []pipedTask
+	for i := 1; i < len(chunks); i += 2 {
+		var pt pipedTask
+		if err := yaml.Unmarshal([]byte(chunks[i]), &pt); err != nil {
+			return nil, fmt.Errorf("task %d: parse frontmatter: %w", len(tasks)+1, err)
+		}
+		if i+1 < len(chunks) {
+			if body := strings.TrimSpace(chunks[i+1]); body != "" {
+				pt.Description = body
+			}
+		}
+		tasks = append(tasks, pt)
+	}
+	return tasks, nil
+}
+
+// parsePlainTask reads plain text as one task with its title on the first line. A ---
+// line is a horizontal rule of the description, not the start of another task.
+func parsePlainTask(input string) []pipedTask {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil
+	}
+	title, description := parseInput(input)
+	return []pipedTask{{Title: title, Description: description}}
+}
+
+// splitOnSeparators splits input on lines holding only ---
+func splitOnSeparators(input string) []string {
+	var chunks []string
+	var current []string
+	for _, line := range strings.Split(input, "\n") {
+		if strings.TrimSpace(line) == "---" {
+			chunks = append(chunks, strings.Join(current, "\n"))
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	return append(chunks, strings.Join(current, "\n"))
+}
+
+// apply sets the fields given for the piped task on a task started from a template
+func (pt pipedTask) apply(task *taskpkg.Task) error {
+	task.Title = strings.TrimSpace(pt.Title)
+	if pt.Description != "" {
+		task.Description = pt.Description
+	}
+	if pt.Type != "" {
+		taskType, ok := taskpkg.ParseType(pt.Type)
+		if !ok {
+			return fmt.Errorf("unknown type %q", pt.Type)
+		}
+		task.Type = taskType
+	}
+	if pt.Status != "" {
+		status, ok := taskpkg.ParseStatus(pt.Status)
+		if !ok {
+			return fmt.Errorf("unknown status %q", pt.Status)
+		}
+		task.Status = status
+	}
+	if len(pt.Tags) > 0 {
+		task.Tags = pt.Tags.ToStringSlice()
+	}
+	if pt.Assignee != "" {
+		task.Assignee = pt.Assignee
+	}
+	if pt.Priority != 0 {
+		task.Priority = int(pt.Priority)
+	}
+	if pt.Points != 0 {
+		task.Points = pt.Points
+	}
+	return nil
+}
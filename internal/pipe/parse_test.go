@@ -0,0 +1,99 @@
+package pipe
+
+import (
+	"reflect"
+	"testing"
+
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+func TestParseTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []pipedTask
+	}{
+		{
+			name:  "plain title and description",
+			input: "Fix login\n\nSafari only",
+			want:  []pipedTask{{Title: "Fix login", Description: "Safari only"}},
+		},
+		{
+			name:  "plain description with a horizontal rule",
+			input: "Crash on save\n\nSteps\n\n---\n\nStack trace\n---\n",
+			want:  []pipedTask{{Title: "Crash on save", Description: "Steps\n\n---\n\nStack trace\n---"}},
+		},
+		{
+			name:  "frontmatter",
+			input: "---\ntitle: Fix login\nstatus: ready\ntags: [auth, ui]\npriority: high\n---\nSafari only",
+			want: []pipedTask{{Title: "Fix login", Status: "ready", Tags: taskpkg.TagsValue{"auth", "ui"},
+				Priority: 1, Description: "Safari only"}},
+		},
+		{
+			name:  "frontmatter batch",
+			input: "---\ntitle: One\n---\nbody one\n---\ntitle: Two\npoints: 3\n---\n",
+			want:  []pipedTask{{Title: "One", Description: "body one"}, {Title: "Two", Points: 3}},
+		},
+		{
+			name:  "yaml list",
+			input: "- title: One\n  type: bug\n- Two",
+			want:  []pipedTask{{Title: "One", Type: "bug"}, {Title: "Two"}},
+		},
+		{
+			name:  "json list",
+			input: `[{"title": "One", "tags": ["x"]}, {"title": "Two", "description": "more"}]`,
+			want:  []pipedTask{{Title: "One", Tags: taskpkg.TagsValue{"x"}}, {Title: "Two", Description: "more"}},
+		},
+		{
+			name:  "json object",
+			input: `{"title": "Only", "assignee": "ann"}`,
+			want:  []pipedTask{{Title: "Only", Assignee: "ann"}},
+		},
+		{
+			name:  "title in brackets is not json",
+			input: "[ui] fix the menu",
+			want:  []pipedTask{{Title: "[ui] fix the menu"}},
+		},
+		{
+			name:  "markdown list item is a title",
+			input: "- [ ] fix: the menu",
+			want:  []pipedTask{{Title: "- [ ] fix: the menu"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTasks(tt.input)
+			if err != nil {
+				t.Fatalf("parseTasks: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTasks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTasksInvalidJSON(t *testing.T) {
+	if _, err := parseTasks(`[{"title": "One",]`); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+}
+
+func TestPipedTaskApply(t *testing.T) {
+	task := &taskpkg.Task{Type: taskpkg.TypeStory, Status: taskpkg.StatusBacklog, Priority: 3, Description: "template body"}
+	pt := pipedTask{Title: " Fix login ", Type: "bug", Status: "in progress", Tags: taskpkg.TagsValue{"auth"}, Priority: 1}
+	if err := pt.apply(task); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if task.Title != "Fix login" || task.Type != taskpkg.TypeBug || task.Status != taskpkg.StatusInProgress || task.Priority != 1 {
+		t.Errorf("fields not applied: %+v", task)
+	}
+	if task.Description != "template body" {
+		t.Errorf("template description replaced: %q", task.Description)
+	}
+
+	if err := (pipedTask{Title: "x", Status: "someday"}).apply(task); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
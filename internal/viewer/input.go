@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
 )
 
 // viewer input parsing: decides whether to run the markdown viewer and resolves a
@@ -30,6 +33,7 @@ type InputSpec struct {
 	Raw         string
 	Candidates  []string
 	SearchRoots []string
+	Anchor      string // heading to open at, from a trailing #fragment
 }
 
 // ErrMultipleInputs is returned when more than one input is provided.
@@ -56,7 +60,8 @@ func ParseViewerInput(args []string, reservedCommands map[string]struct{}) (Inpu
 		return InputSpec{}, false, nil
 	}
 
-	spec, err := buildInputSpec(raw)
+	// config.yaml isn't loaded yet: hosts from GITLAB_HOST only
+	spec, err := buildInputSpec(raw, config.GetGitLabHosts())
 	if err != nil {
 		return InputSpec{}, false, err
 	}
@@ -119,7 +124,8 @@ func isValidLogLevel(value string) bool {
 }
 
 // buildInputSpec classifies the raw input and builds candidate sources.
-func buildInputSpec(raw string) (InputSpec, error) {
+// gitlabHosts are the hosts whose links are read as GitLab repositories.
+func buildInputSpec(raw string, gitlabHosts []string) (InputSpec, error) {
 	if raw == "-" {
 		return InputSpec{
 			Kind: InputStdin,
@@ -127,10 +133,23 @@ func buildInputSpec(raw string) (InputSpec, error) {
 		}, nil
 	}
 
+	source, anchor := splitAnchor(raw)
+	spec, err := buildSourceSpec(source, gitlabHosts)
+	if err != nil {
+		return InputSpec{}, err
+	}
+	spec.Raw = raw
+	spec.Anchor = anchor
+	return spec, nil
+}
+
+// buildSourceSpec builds candidate sources for a file path or link without its #fragment.
+// gitlabHosts are the hosts whose links are read as GitLab repositories.
+func buildSourceSpec(raw string, gitlabHosts []string) (InputSpec, error) {
 	if spec, ok := parseGitHub(raw); ok {
 		return spec, nil
 	}
-	if spec, ok := parseGitLab(raw); ok {
+	if spec, ok := parseGitLab(raw, gitlabHosts); ok {
 		return spec, nil
 	}
 	if spec, ok := parseHTTPURL(raw); ok {
@@ -150,6 +169,19 @@ func buildInputSpec(raw string) (InputSpec, error) {
 	}, nil
 }
 
+// splitAnchor separates a trailing #fragment naming the heading to open at.
+// An existing file with '#' in its name is taken as it is.
+func splitAnchor(raw string) (string, string) {
+	i := strings.LastIndex(raw, "#")
+	if i < 0 {
+		return raw, ""
+	}
+	if _, err := os.Stat(raw); err == nil {
+		return raw, ""
+	}
+	return raw[:i], raw[i+1:]
+}
+
 // parseHTTPURL detects explicit http(s) links.
 func parseHTTPURL(raw string) (InputSpec, bool) {
 	parsed, err := url.Parse(raw)
@@ -208,13 +240,24 @@ func parseGitHub(raw string) (InputSpec, bool) {
 	}, true
 }
 
-// parseGitLab maps GitLab inputs to raw content URLs.
-func parseGitLab(raw string) (InputSpec, bool) {
-	pathPart, ok := extractHostPath(raw, "gitlab.com")
-	if !ok {
-		return InputSpec{}, false
+// parseGitLab maps GitLab inputs on any of hosts to raw content URLs.
+func parseGitLab(raw string, hosts []string) (InputSpec, bool) {
+	for _, host := range hosts {
+		pathPart, ok := extractHostPath(raw, host)
+		if !ok {
+			continue
+		}
+		scheme := "https"
+		if strings.HasPrefix(raw, "http://") {
+			scheme = "http"
+		}
+		return parseGitLabPath(raw, scheme+"://"+host, pathPart)
 	}
+	return InputSpec{}, false
+}
 
+// parseGitLabPath maps a repository path on a GitLab instance at base to raw content URLs.
+func parseGitLabPath(raw, base, pathPart string) (InputSpec, bool) {
 	segments := splitPath(pathPart)
 	if len(segments) < 2 {
 		return InputSpec{}, false
@@ -232,7 +275,7 @@ func parseGitLab(raw string) (InputSpec, bool) {
 	}
 
 	if ref != "" {
-		rawURL := fmt.Sprintf("https://gitlab.com/%s/%s/-/raw/%s/%s", namespace, repo, ref, filePath)
+		rawURL := fmt.Sprintf("%s/%s/%s/-/raw/%s/%s", base, namespace, repo, ref, filePath)
 		return InputSpec{
 			Kind:       InputGitLab,
 			Raw:        raw,
@@ -244,8 +287,8 @@ func parseGitLab(raw string) (InputSpec, bool) {
 		Kind: InputGitLab,
 		Raw:  raw,
 		Candidates: []string{
-			fmt.Sprintf("https://gitlab.com/%s/%s/-/raw/main/%s", namespace, repo, filePath),
-			fmt.Sprintf("https://gitlab.com/%s/%s/-/raw/master/%s", namespace, repo, filePath),
+			fmt.Sprintf("%s/%s/%s/-/raw/main/%s", base, namespace, repo, filePath),
+			fmt.Sprintf("%s/%s/%s/-/raw/master/%s", base, namespace, repo, filePath),
 		},
 	}, true
 }
@@ -149,3 +149,34 @@ func TestParseViewerInputUnknownFlag(t *testing.T) {
 		t.Fatalf("expected viewer mode to be false")
 	}
 }
+
+func TestParseViewerInputAnchor(t *testing.T) {
+	spec, ok, err := ParseViewerInput([]string{"docs/design.md#data-model"}, map[string]struct{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected viewer mode")
+	}
+	if spec.Anchor != "data-model" {
+		t.Fatalf("expected anchor data-model, got %q", spec.Anchor)
+	}
+	abs, err := filepath.Abs("docs/design.md")
+	if err != nil {
+		t.Fatalf("abs path error: %v", err)
+	}
+	if len(spec.Candidates) != 1 || spec.Candidates[0] != abs {
+		t.Fatalf("unexpected candidates: %v", spec.Candidates)
+	}
+
+	spec, _, err = ParseViewerInput([]string{"github.com/owner/repo#usage"}, map[string]struct{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Kind != InputGitHub || spec.Anchor != "usage" {
+		t.Fatalf("expected github input with anchor usage, got %s %q", spec.Kind, spec.Anchor)
+	}
+	if spec.Candidates[0] != "https://raw.githubusercontent.com/owner/repo/main/README.md" {
+		t.Fatalf("unexpected candidates: %v", spec.Candidates)
+	}
+}
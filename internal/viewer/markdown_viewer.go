@@ -8,9 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/boolean-maybe/navidown/loaders"
 	nav "github.com/boolean-maybe/navidown/navidown"
-	navtview "github.com/boolean-maybe/navidown/navidown/tview"
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/util"
 	"github.com/boolean-maybe/tiki/view"
@@ -26,8 +24,20 @@ func Run(input InputSpec) error {
 		return err
 	}
 
+	// self-hosted GitLab instances from config.yaml are only known now
+	if hosts := config.GetGitLabHosts(); input.Kind == InputFile || input.Kind == InputURL {
+		if spec, err := buildInputSpec(input.Raw, hosts); err == nil {
+			input = spec
+		}
+	}
+
 	app := tview.NewApplication()
-	provider := &loaders.FileHTTP{SearchRoots: input.SearchRoots}
+	provider := newContentProvider(input.SearchRoots, remoteAuth{
+		githubToken: config.GetGitHubToken(),
+		gitlabToken: config.GetGitLabToken(),
+		githubHosts: []string{"raw.githubusercontent.com"},
+		gitlabHosts: config.GetGitLabHosts(),
+	})
 
 	// create status bar
 	statusBar := tview.NewTextView()
@@ -36,11 +46,12 @@ func Run(input InputSpec) error {
 
 	// Create NavigableMarkdown - OnStateChange is set after creation to avoid forward reference
 	md := view.NewNavigableMarkdown(view.NavigableMarkdownConfig{
-		Provider:    provider,
-		SearchRoots: input.SearchRoots,
+		Provider:      provider,
+		SearchRoots:   input.SearchRoots,
+		ResolveSource: provider.ResolvePath,
 	})
 	md.SetStateChangedHandler(func() {
-		updateStatusBar(statusBar, md.Viewer())
+		updateStatusBar(statusBar, md)
 	})
 
 	content, sourcePath, err := loadInitialContent(input, provider)
@@ -55,17 +66,91 @@ func Run(input InputSpec) error {
 	}
 
 	// initial status bar update
-	updateStatusBar(statusBar, md.Viewer())
+	updateStatusBar(statusBar, md)
+
+	if input.Anchor != "" {
+		md.ScrollToHeading(input.Anchor)
+	}
 
 	// create flex layout with status bar
-	flex := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(md.Viewer(), 0, 1, true).
-		AddItem(statusBar, 1, 0, false)
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	// the outline of headings takes the document's place while it is open,
+	// and the find box sits above the status bar while a pattern is typed
+	var outlineList *tview.List
+	var findBox *view.SearchBox
+	layout := func() {
+		flex.Clear()
+		if outlineList != nil {
+			flex.AddItem(outlineList, 0, 1, true)
+			app.SetFocus(outlineList)
+		} else {
+			flex.AddItem(md.Viewer(), 0, 1, findBox == nil)
+			app.SetFocus(md.Viewer())
+		}
+		if findBox != nil {
+			flex.AddItem(findBox, config.SearchBoxHeight, 0, true)
+			app.SetFocus(findBox)
+		}
+		flex.AddItem(statusBar, 1, 0, false)
+	}
+	closeOutline := func() {
+		outlineList = nil
+		layout()
+	}
+	closeFind := func() {
+		findBox = nil
+		layout()
+	}
+	layout()
 
 	// key handlers
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if findBox != nil {
+			// the find box handles its own keys: Enter searches, Esc cancels
+			return event
+		}
+		if outlineList != nil {
+			// the list handles its own keys; o and Esc close it
+			if event.Key() == tcell.KeyEscape || event.Rune() == 'o' {
+				closeOutline()
+				return nil
+			}
+			if event.Rune() == 'q' {
+				app.Stop()
+				return nil
+			}
+			return event
+		}
+		if event.Key() == tcell.KeyEscape {
+			md.ClearFind()
+			return nil
+		}
 		switch event.Rune() {
+		case '/':
+			findBox = view.NewSearchBox()
+			findBox.SetSubmitHandler(func(text string) {
+				closeFind()
+				md.Find(strings.TrimSpace(text))
+			})
+			findBox.SetCancelHandler(closeFind)
+			layout()
+			return nil
+		case 'n':
+			md.NextMatch()
+			return nil
+		case 'N':
+			md.PreviousMatch()
+			return nil
+		case 'o':
+			outlineList = md.Outline(closeOutline)
+			layout()
+			return nil
+		case ']':
+			md.NextHeading()
+			return nil
+		case '[':
+			md.PreviousHeading()
+			return nil
 		case 'q':
 			app.Stop()
 			return nil
@@ -89,7 +174,7 @@ func Run(input InputSpec) error {
 				return nil
 			}
 			md.SetMarkdownWithSource(string(data), srcPath, false)
-			updateStatusBar(statusBar, md.Viewer())
+			updateStatusBar(statusBar, md)
 			return nil
 		}
 		return event
@@ -102,7 +187,7 @@ func Run(input InputSpec) error {
 	return nil
 }
 
-func loadInitialContent(input InputSpec, provider *loaders.FileHTTP) (string, string, error) {
+func loadInitialContent(input InputSpec, provider nav.ContentProvider) (string, string, error) {
 	if input.Kind == InputStdin {
 		content, err := io.ReadAll(os.Stdin)
 		if err != nil {
@@ -150,8 +235,8 @@ func resolveInitialSource(candidate string, searchRoots []string) string {
 }
 
 // updateStatusBar refreshes the status bar with current viewer state.
-func updateStatusBar(statusBar *tview.TextView, v *navtview.TextViewViewer) {
-	core := v.Core()
+func updateStatusBar(statusBar *tview.TextView, md *view.NavigableMarkdown) {
+	core := md.Viewer().Core()
 	srcPath := core.SourceFilePath()
 	fileName := filepath.Base(srcPath)
 	if fileName == "" || fileName == "." {
@@ -175,7 +260,16 @@ func updateStatusBar(statusBar *tview.TextView, v *navtview.TextViewViewer) {
 	} else {
 		status += "[gray]▶[-]"
 	}
-	status += fmt.Sprintf(" | Scroll:[%s]j/k[-] Top/End:[%s]g/G[-] Edit:[%s]e[-] Quit:[%s]q[-]", keyColor, keyColor, keyColor, keyColor)
+	if query, current, total := md.FindStatus(); query != "" {
+		if total == 0 {
+			status += fmt.Sprintf(" | [red]/%s: no matches[-]", tview.Escape(query))
+		} else {
+			status += fmt.Sprintf(" | [yellow]/%s %d/%d[-] Next/Prev:[%s]n/N[-]", tview.Escape(query), current, total, keyColor)
+		}
+	} else {
+		status += fmt.Sprintf(" | Find:[%s]/[-]", keyColor)
+	}
+	status += fmt.Sprintf(" | Scroll:[%s]j/k[-] Top/End:[%s]g/G[-] Heading:[%s]%s[-] Outline:[%s]o[-] Edit:[%s]e[-] Quit:[%s]q[-]", keyColor, keyColor, keyColor, tview.Escape("]/["), keyColor, keyColor, keyColor)
 
 	statusBar.SetText(status)
 }
@@ -0,0 +1,231 @@
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/boolean-maybe/navidown/loaders"
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+// remote sources: raw files of GitHub and GitLab repositories, fetched with a token when
+// one is configured so that private repositories open like public ones. A remote document's
+// relative links resolve against its raw URL, which keeps them in the same repo and ref.
+
+// remoteAuth holds the tokens for private repositories and the hosts they apply to.
+type remoteAuth struct {
+	githubToken string
+	gitlabToken string
+	githubHosts []string // hosts serving raw GitHub content
+	gitlabHosts []string
+}
+
+// contentProvider reads local files through FileHTTP and remote sources over http.
+type contentProvider struct {
+	local  *loaders.FileHTTP
+	client *http.Client
+	auth   remoteAuth
+}
+
+func newContentProvider(searchRoots []string, auth remoteAuth) *contentProvider {
+	return &contentProvider{
+		local: &loaders.FileHTTP{SearchRoots: searchRoots},
+		client: &http.Client{
+			// Authorization is dropped on redirects to another host by net/http; the
+			// GitLab header has to be dropped here, and both leaving https
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				if req.URL.Host != via[0].URL.Host || req.URL.Scheme != via[0].URL.Scheme {
+					req.Header.Del("PRIVATE-TOKEN")
+				}
+				if req.URL.Scheme != "https" {
+					req.Header.Del("Authorization")
+				}
+				return nil
+			},
+		},
+		auth: auth,
+	}
+}
+
+// FetchContent fetches a link relative to the document it appears in.
+func (p *contentProvider) FetchContent(elem nav.NavElement) (string, error) {
+	if elem.URL == "" {
+		return "", nil
+	}
+	if !isHTTP(elem.URL) && !isHTTP(elem.SourceFilePath) {
+		return p.local.FetchContent(elem)
+	}
+	return p.fetch(p.ResolvePath(elem.URL, elem.SourceFilePath))
+}
+
+// ResolvePath returns the file or URL a link points at. Links in remote documents resolve
+// against the document's URL; GitHub and GitLab page links become raw content URLs.
+func (p *contentProvider) ResolvePath(link, sourceFile string) string {
+	if isHTTP(link) {
+		if spec, ok := parseGitHub(link); ok && len(spec.Candidates) == 1 {
+			return spec.Candidates[0]
+		}
+		if spec, ok := parseGitLab(link, p.auth.gitlabHosts); ok && len(spec.Candidates) == 1 {
+			return spec.Candidates[0]
+		}
+		return link
+	}
+	if isHTTP(sourceFile) {
+		base, err := url.Parse(sourceFile)
+		if err != nil {
+			return link
+		}
+		ref, err := url.Parse(link)
+		if err != nil {
+			return link
+		}
+		// repository links starting with / are relative to the repo root, not the host
+		if strings.HasPrefix(ref.Path, "/") && ref.Host == "" {
+			if root, ok := p.repoRoot(base); ok {
+				ref.Path = root + ref.Path
+			}
+		}
+		return base.ResolveReference(ref).String()
+	}
+	resolved, err := nav.ResolveMarkdownPath(link, sourceFile, p.local.SearchRoots)
+	if err != nil || resolved == "" {
+		return link
+	}
+	return resolved
+}
+
+func (p *contentProvider) fetch(rawURL string) (content string, err error) {
+	req, tokenSent, err := p.request(rawURL)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", closeErr)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound && !tokenSent && p.isRepoHost(req.URL.Host) && req.URL.Scheme != "https":
+		return "", fmt.Errorf("%s: %w (tokens for private repositories are only sent over https)", rawURL, nav.ErrFileNotFound)
+	case resp.StatusCode == http.StatusNotFound && !tokenSent && p.isRepoHost(req.URL.Host):
+		// private repositories look missing without a token
+		return "", fmt.Errorf("%s: %w (set GITHUB_TOKEN or GITLAB_TOKEN for private repositories)", rawURL, nav.ErrFileNotFound)
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%s: %w", rawURL, nav.ErrFileNotFound)
+	default:
+		return "", fmt.Errorf("server returned non-200 status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return string(body), nil
+}
+
+// request builds the request for a raw URL, with the token of its host if there is one.
+// Tokens are only sent over https. With a token, GitLab files are read through the API:
+// its raw web route doesn't accept tokens. Reports whether a token was sent.
+func (p *contentProvider) request(rawURL string) (*http.Request, bool, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+
+	target := rawURL
+	header, token := "", ""
+	switch {
+	case parsed.Scheme != "https":
+	case p.auth.githubToken != "" && containsHost(p.auth.githubHosts, parsed.Host):
+		header, token = "Authorization", "Bearer "+p.auth.githubToken
+	case p.auth.gitlabToken != "" && containsHost(p.auth.gitlabHosts, parsed.Host):
+		if apiURL, ok := gitLabAPIURL(parsed); ok {
+			target = apiURL
+		}
+		header, token = "PRIVATE-TOKEN", p.auth.gitlabToken
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid URL %q: %w", target, err)
+	}
+	if header != "" {
+		req.Header.Set(header, token)
+	}
+	return req, header != "", nil
+}
+
+// repoRoot returns the path of the repository root at the ref of a raw file URL:
+// /org/repo/ref on GitHub (refs/heads/branch counts as the ref) and
+// /ns/repo/-/raw/ref on GitLab
+func (p *contentProvider) repoRoot(raw *url.URL) (string, bool) {
+	path := strings.TrimPrefix(raw.Path, "/")
+	switch {
+	case containsHost(p.auth.githubHosts, raw.Host):
+		parts := strings.Split(path, "/")
+		n := 3
+		if len(parts) > 2 && parts[2] == "refs" {
+			n = 5
+		}
+		if len(parts) <= n {
+			return "", false
+		}
+		return "/" + strings.Join(parts[:n], "/"), true
+	case containsHost(p.auth.gitlabHosts, raw.Host):
+		project, rest, ok := strings.Cut(path, "/-/raw/")
+		if !ok {
+			return "", false
+		}
+		ref, _, ok := strings.Cut(rest, "/")
+		if !ok {
+			return "", false
+		}
+		return "/" + project + "/-/raw/" + ref, true
+	}
+	return "", false
+}
+
+func (p *contentProvider) isRepoHost(host string) bool {
+	return containsHost(p.auth.githubHosts, host) || containsHost(p.auth.gitlabHosts, host)
+}
+
+// gitLabAPIURL maps https://host/ns/repo/-/raw/ref/path to the files API of the same
+// instance: https://host/api/v4/projects/ns%2Frepo/repository/files/path/raw?ref=ref
+func gitLabAPIURL(raw *url.URL) (string, bool) {
+	project, rest, ok := strings.Cut(strings.TrimPrefix(raw.Path, "/"), "/-/raw/")
+	if !ok || project == "" {
+		return "", false
+	}
+	ref, filePath, ok := strings.Cut(rest, "/")
+	if !ok || ref == "" || filePath == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s://%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
+		raw.Scheme, raw.Host, url.PathEscape(project), url.PathEscape(filePath), url.QueryEscape(ref)), true
+}
+
+func containsHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTP(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
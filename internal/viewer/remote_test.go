@@ -0,0 +1,189 @@
+package viewer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+func TestContentProviderGitHubToken(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("# Private"))
+	}))
+	defer srv.Close()
+	host := mustHost(t, srv.URL)
+
+	p := newContentProvider(nil, remoteAuth{githubToken: "secret", githubHosts: []string{host}})
+	p.client.Transport = srv.Client().Transport
+	content, err := p.FetchContent(nav.NavElement{URL: srv.URL + "/org/repo/main/README.md"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "# Private" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Fatalf("expected bearer token, got %q", gotAuth)
+	}
+
+	// other hosts never see the token
+	p = newContentProvider(nil, remoteAuth{githubToken: "secret", githubHosts: []string{"raw.githubusercontent.com"}})
+	p.client.Transport = srv.Client().Transport
+	if _, err := p.FetchContent(nav.NavElement{URL: srv.URL + "/README.md"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "" {
+		t.Fatalf("token sent to unrelated host: %q", gotAuth)
+	}
+}
+
+func TestContentProviderGitLabToken(t *testing.T) {
+	var gotToken, gotPath, gotRef string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		gotPath = r.URL.EscapedPath()
+		gotRef = r.URL.Query().Get("ref")
+		_, _ = w.Write([]byte("# Internal"))
+	}))
+	defer srv.Close()
+	host := mustHost(t, srv.URL)
+
+	p := newContentProvider(nil, remoteAuth{gitlabToken: "glpat", gitlabHosts: []string{host}})
+	p.client.Transport = srv.Client().Transport
+	if _, err := p.FetchContent(nav.NavElement{URL: srv.URL + "/group/sub/repo/-/raw/dev/docs/intro.md"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotToken != "glpat" {
+		t.Fatalf("expected private token, got %q", gotToken)
+	}
+	if gotPath != "/api/v4/projects/group%2Fsub%2Frepo/repository/files/docs%2Fintro.md/raw" {
+		t.Fatalf("unexpected API path: %s", gotPath)
+	}
+	if gotRef != "dev" {
+		t.Fatalf("expected ref dev, got %q", gotRef)
+	}
+}
+
+func TestContentProviderRelativeLinks(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte("# Linked"))
+	}))
+	defer srv.Close()
+
+	p := newContentProvider(nil, remoteAuth{})
+	source := srv.URL + "/org/repo/v1.2/docs/guide/index.md"
+	if _, err := p.FetchContent(nav.NavElement{URL: "../api.md", SourceFilePath: source}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/org/repo/v1.2/docs/api.md" {
+		t.Fatalf("link left the repo ref: %s", gotPath)
+	}
+
+	tests := []struct {
+		link, source, want string
+	}{
+		{"setup.md", "https://raw.githubusercontent.com/org/repo/main/docs/README.md",
+			"https://raw.githubusercontent.com/org/repo/main/docs/setup.md"},
+		{"/CONTRIBUTING.md", "https://raw.githubusercontent.com/org/repo/main/docs/README.md",
+			"https://raw.githubusercontent.com/org/repo/main/CONTRIBUTING.md"},
+		{"/CONTRIBUTING.md", "https://raw.githubusercontent.com/org/repo/refs/heads/dev/README.md",
+			"https://raw.githubusercontent.com/org/repo/refs/heads/dev/CONTRIBUTING.md"},
+		{"/docs/b.md#usage", "https://gitlab.com/ns/repo/-/raw/v2/docs/a.md",
+			"https://gitlab.com/ns/repo/-/raw/v2/docs/b.md#usage"},
+		{"https://github.com/org/other/blob/main/NOTES.md", "",
+			"https://raw.githubusercontent.com/org/other/main/NOTES.md"},
+		{"https://gitlab.com/org/repo/-/blob/main/a.md", "",
+			"https://gitlab.com/org/repo/-/raw/main/a.md"},
+	}
+	p = newContentProvider(nil, remoteAuth{
+		githubHosts: []string{"raw.githubusercontent.com"},
+		gitlabHosts: []string{"gitlab.com"},
+	})
+	for _, tt := range tests {
+		if got := p.ResolvePath(tt.link, tt.source); got != tt.want {
+			t.Errorf("ResolvePath(%q, %q) = %q, want %q", tt.link, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestContentProviderNotFoundHint(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	host := mustHost(t, srv.URL)
+
+	p := newContentProvider(nil, remoteAuth{githubHosts: []string{host}})
+	p.client.Transport = srv.Client().Transport
+	_, err := p.FetchContent(nav.NavElement{URL: srv.URL + "/org/private/main/README.md"})
+	if !errors.Is(err, nav.ErrFileNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("expected token hint, got %v", err)
+	}
+
+	p = newContentProvider(nil, remoteAuth{githubToken: "secret", githubHosts: []string{host}})
+	p.client.Transport = srv.Client().Transport
+	_, err = p.FetchContent(nav.NavElement{URL: srv.URL + "/org/private/main/README.md"})
+	if err == nil || strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("expected plain not found with a token, got %v", err)
+	}
+}
+
+func TestContentProviderTokensOnlyOverHTTPS(t *testing.T) {
+	var gotAuth, gotToken string
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotToken = r.Header.Get("Authorization"), r.Header.Get("PRIVATE-TOKEN")
+		_, _ = w.Write([]byte("# Plain"))
+	}))
+	defer plain.Close()
+	// the https host redirects to http on the same host name
+	redirecting := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL+"/README.md", http.StatusFound)
+	}))
+	defer redirecting.Close()
+
+	auth := remoteAuth{
+		githubToken: "secret", githubHosts: []string{mustHost(t, plain.URL), mustHost(t, redirecting.URL)},
+		gitlabToken: "glpat", gitlabHosts: []string{mustHost(t, plain.URL), mustHost(t, redirecting.URL)},
+	}
+
+	p := newContentProvider(nil, auth)
+	if _, err := p.FetchContent(nav.NavElement{URL: plain.URL + "/org/repo/main/README.md"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "" || gotToken != "" {
+		t.Errorf("token sent over http: Authorization %q, PRIVATE-TOKEN %q", gotAuth, gotToken)
+	}
+
+	for _, only := range []remoteAuth{
+		{githubToken: auth.githubToken, githubHosts: auth.githubHosts},
+		{gitlabToken: auth.gitlabToken, gitlabHosts: auth.gitlabHosts},
+	} {
+		p = newContentProvider(nil, only)
+		p.client.Transport = redirecting.Client().Transport
+		if _, err := p.FetchContent(nav.NavElement{URL: redirecting.URL + "/ns/repo/-/raw/main/README.md"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotAuth != "" || gotToken != "" {
+			t.Errorf("token kept on a redirect to http: Authorization %q, PRIVATE-TOKEN %q", gotAuth, gotToken)
+		}
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse %s: %v", rawURL, err)
+	}
+	return u.Host
+}
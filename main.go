@@ -1,17 +1,32 @@
 package main
 
 import (
+	"context"
 	_ "embed"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/doki"
+	"github.com/boolean-maybe/tiki/export"
+	"github.com/boolean-maybe/tiki/importer"
 	"github.com/boolean-maybe/tiki/internal/app"
 	"github.com/boolean-maybe/tiki/internal/bootstrap"
 	"github.com/boolean-maybe/tiki/internal/pipe"
 	"github.com/boolean-maybe/tiki/internal/viewer"
+	"github.com/boolean-maybe/tiki/mcp"
+	"github.com/boolean-maybe/tiki/metrics"
+	"github.com/boolean-maybe/tiki/server"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
 	"github.com/boolean-maybe/tiki/util/sysinfo"
 )
 
@@ -45,23 +60,87 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Handle piped stdin: create a task and exit without launching TUI
+	// Handle piped stdin: create tasks and exit without launching TUI
 	if pipe.IsPipedInput() && !pipe.HasPositionalArgs(os.Args[1:]) {
-		taskID, err := pipe.CreateTaskFromReader(os.Stdin)
+		taskIDs, err := pipe.CreateTasksFromReader(os.Stdin, pipe.TemplateFlag(os.Args[1:]))
+		for _, taskID := range taskIDs {
+			fmt.Println(taskID)
+		}
 		if err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
 			os.Exit(1)
 		}
-		fmt.Println(taskID)
 		return
 	}
 
+	// Handle metrics command: print the flow metrics report and exit
+	if len(os.Args) > 1 && os.Args[1] == "metrics" {
+		if err := runMetrics(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle doctor command: report broken doki and tiki links and exit
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		if err := runDoctor(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle export command: render dokis, tikis and boards to a static HTML site and exit
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle serve command: serve the task store over a local HTTP API until interrupted
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle mcp command: serve tiki tools to an AI agent over stdio until it disconnects
+	if len(os.Args) > 1 && os.Args[1] == "mcp" {
+		if err := runMCP(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle import command: create or update tikis from another tracker's export and exit
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle workspace command: launch the TUI below over every repository of the workspace
+	if len(os.Args) > 1 && os.Args[1] == "workspace" {
+		if err := runWorkspace(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	}
+
 	// Handle init command
 	initRequested := len(os.Args) > 1 && os.Args[1] == "init"
 
 	// Handle viewer mode (standalone markdown viewer)
-	// "init" is reserved to prevent treating it as a markdown file
-	viewerInput, runViewer, err := viewer.ParseViewerInput(os.Args[1:], map[string]struct{}{"init": {}})
+	// commands are reserved to prevent treating them as markdown files
+	viewerInput, runViewer, err := viewer.ParseViewerInput(os.Args[1:], map[string]struct{}{"init": {}, "metrics": {}, "doctor": {}, "export": {}, "import": {}, "serve": {}, "mcp": {}, "workspace": {}})
 	if err != nil {
 		if errors.Is(err, viewer.ErrMultipleInputs) {
 			_, _ = fmt.Fprintln(os.Stderr, "error:", err)
@@ -131,6 +210,383 @@ func runSysInfo() error {
 	return nil
 }
 
+// runMetrics handles the metrics command, printing lead/cycle time analytics as markdown.
+func runMetrics() error {
+	// keep stdout clean for the report; the store logs at info level while loading
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	_, taskStore, err := bootstrap.InitStores()
+	if err != nil {
+		return err
+	}
+
+	report, err := metrics.Generate(taskStore, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(report.Markdown())
+	return nil
+}
+
+// runDoctor handles the doctor command, listing broken relative links and missing
+// anchors across the doki tree and tiki descriptions.
+func runDoctor() error {
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	report, err := doki.Doctor(config.GetDokiDir(), config.GetTaskDir())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(report.String())
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("%d broken links", len(report.Problems))
+	}
+	return nil
+}
+
+// runExport handles the export command. By default it writes a static HTML site with the
+// doki tree, every tiki and a board page per tiki view to the given directory (default
+// "site"); with --board it prints that board as a markdown report instead.
+func runExport(args []string) error {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	const usage = "usage: tiki export [directory] | tiki export --board name [--output file.md] [--link-base URL]"
+	var positional []string
+	var boardName, output, linkBase string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--board", "--output", "--link-base":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return errors.New(usage)
+				}
+				value = args[i+1]
+				i++
+			}
+			switch name {
+			case "--board":
+				boardName = value
+			case "--output":
+				output = value
+			default:
+				linkBase = value
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown flag %s\n%s", arg, usage)
+			}
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) > 1 || (boardName != "" && len(positional) > 0) || (boardName == "" && (output != "" || linkBase != "")) {
+		return errors.New(usage)
+	}
+
+	// boards come from the workflow views, installed on first run like the TUI does
+	if err := config.InstallDefaultWorkflow(); err != nil {
+		slog.Warn("failed to install default workflow", "error", err)
+	}
+	if _, err := bootstrap.LoadConfig(); err != nil {
+		return err
+	}
+	_, taskStore, err := bootstrap.InitStores()
+	if err != nil {
+		return err
+	}
+	plugins, err := bootstrap.LoadPlugins()
+	if err != nil {
+		return err
+	}
+	currentUser, _, _ := taskStore.GetCurrentUser()
+
+	if boardName != "" {
+		board, err := export.FindBoard(plugins, boardName)
+		if err != nil {
+			return err
+		}
+		report := &export.Report{
+			Board:       board,
+			Tasks:       taskStore.GetAllTasks(),
+			CurrentUser: currentUser,
+			Now:         time.Now(),
+		}
+		if linkBase != "" {
+			base := strings.TrimSuffix(linkBase, "/")
+			report.TaskURL = func(id string) string { return base + "/" + strings.ToLower(id) + ".md" }
+		}
+		if output == "" {
+			fmt.Print(report.Markdown())
+			return nil
+		}
+		//nolint:gosec // G306: 0644 is appropriate for a report
+		if err := os.WriteFile(output, []byte(report.Markdown()), 0644); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+		return nil
+	}
+
+	outDir := "site"
+	if len(positional) == 1 {
+		outDir = positional[0]
+	}
+
+	title := "tiki"
+	if wd, err := os.Getwd(); err == nil {
+		title = filepath.Base(wd)
+	}
+
+	site := &export.Site{
+		Title:       title,
+		DokiDir:     config.GetDokiDir(),
+		TaskFile:    exportTaskFile(taskStore),
+		Tasks:       taskStore.GetAllTasks(),
+		Plugins:     plugins,
+		CurrentUser: currentUser,
+		Now:         time.Now(),
+	}
+	if err := site.Write(outDir); err != nil {
+		return err
+	}
+	fmt.Printf("site written to %s\n", outDir)
+	return nil
+}
+
+// exportTaskFile locates the markdown file of a tiki, in the directory or repository its
+// store loaded it from. Stores without files get the file it would have in the task directory.
+func exportTaskFile(taskStore store.Store) func(id string) string {
+	if locator, ok := taskStore.(interface{ TaskFilePath(id string) string }); ok {
+		return locator.TaskFilePath
+	}
+	return func(id string) string {
+		return filepath.Join(config.GetTaskDir(), strings.ToLower(id)+".md")
+	}
+}
+
+// runImport handles the import command, writing a tiki per issue of an export file.
+// Tikis imported earlier are updated in place, so importing again is safe.
+func runImport(args []string) error {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	})))
+
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	const usage = "usage: tiki import github|jira|trello <file> [--mapping file.yaml] [--repo owner/name] [--dry-run]"
+	var positional []string
+	var repo, mappingPath string
+	dryRun := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case (arg == "--repo" || arg == "--mapping") && i+1 < len(args):
+			if arg == "--repo" {
+				repo = args[i+1]
+			} else {
+				mappingPath = args[i+1]
+			}
+			i++
+		case strings.HasPrefix(arg, "--repo="):
+			repo = strings.TrimPrefix(arg, "--repo=")
+		case strings.HasPrefix(arg, "--mapping="):
+			mappingPath = strings.TrimPrefix(arg, "--mapping=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag %s\n%s", arg, usage)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		return errors.New(usage)
+	}
+	source, path := positional[0], positional[1]
+
+	var mapping *importer.Mapping
+	switch source {
+	case "github":
+		if mappingPath != "" {
+			return fmt.Errorf("--mapping applies to jira and trello imports")
+		}
+	case "jira":
+		mapping = importer.DefaultJiraMapping()
+	case "trello":
+		mapping = importer.DefaultTrelloMapping()
+	default:
+		return errors.New(usage)
+	}
+	if mappingPath != "" {
+		if err := mapping.LoadMapping(mappingPath); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open export: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var tasks []*task.Task
+	var warnings []string
+	switch source {
+	case "github":
+		tasks, err = importer.ParseGitHubIssues(f, repo)
+	case "jira":
+		tasks, warnings, err = importer.ParseJiraCSV(f, mapping)
+	case "trello":
+		tasks, warnings, err = importer.ParseTrelloBoard(f, mapping)
+	}
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		_, _ = fmt.Fprintln(os.Stderr, "warning:", w)
+	}
+
+	_, taskStore, err := bootstrap.InitStores()
+	if err != nil {
+		return err
+	}
+	changes, err := importer.Plan(taskStore, tasks)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Print(importer.FormatPreview(changes))
+		return nil
+	}
+	result, err := importer.Apply(taskStore, changes)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// runServe handles the serve command, exposing the task store over a JSON API on a local
+// address until interrupted.
+func runServe(args []string) error {
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	const usage = "usage: tiki serve [--addr 127.0.0.1:7474]"
+	addr := "127.0.0.1:7474"
+	switch {
+	case len(args) == 0:
+	case len(args) == 2 && args[0] == "--addr":
+		addr = args[1]
+	case len(args) == 1 && strings.HasPrefix(args[0], "--addr="):
+		addr = strings.TrimPrefix(args[0], "--addr=")
+	default:
+		return errors.New(usage)
+	}
+
+	// the store logs every change at info level
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	})))
+
+	if _, err := bootstrap.LoadConfig(); err != nil {
+		return err
+	}
+	_, taskStore, err := bootstrap.InitStores()
+	if err != nil {
+		return err
+	}
+
+	api := server.New(taskStore, addr)
+	defer api.Close()
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+		// no write timeout: event streams stay open
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		api.Close()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("serving tikis on http://%s/api/tasks\n", addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// runMCP handles the mcp command, answering Model Context Protocol requests on stdin and
+// stdout so that AI agents work with tikis through validated tools
+func runMCP(args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: tiki mcp")
+	}
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("project not initialized: run 'tiki init' first")
+	}
+
+	// stdout carries the protocol, so logs go to stderr only
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	})))
+
+	if err := config.InstallDefaultWorkflow(); err != nil {
+		slog.Warn("failed to install default workflow", "error", err)
+	}
+	if _, err := bootstrap.LoadConfig(); err != nil {
+		return err
+	}
+	_, taskStore, err := bootstrap.InitStores()
+	if err != nil {
+		return err
+	}
+	plugins, err := bootstrap.LoadPlugins()
+	if err != nil {
+		return err
+	}
+	return mcp.New(taskStore, plugins, config.Version).Serve(os.Stdin, os.Stdout)
+}
+
+// runWorkspace handles the workspace command, selecting the store over the repositories
+// listed under workspace.repos in the user config.yaml. The boards are the current project's.
+func runWorkspace(args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: tiki workspace")
+	}
+	if !config.IsProjectInitialized() {
+		return fmt.Errorf("run 'tiki workspace' in a tiki project: its boards show the workspace")
+	}
+	config.SetStoreBackend("workspace")
+	return nil
+}
+
 // printUsage prints usage information when tiki is run in an uninitialized repo.
 func printUsage() {
 	fmt.Print(`tiki - Terminal-based task and documentation management
@@ -139,7 +595,20 @@ Usage:
   tiki                  Launch TUI in initialized repo
   tiki init             Initialize project in current git repo
   tiki file.md/URL      View markdown file
-  echo "Title" | tiki   Create task from piped input
+  echo "Title" | tiki   Create task from piped input (markdown, or a batch as YAML/JSON list)
+    --template name     Start it from a named template (.doc/templates/name.md)
+  tiki metrics          Print cycle and lead time metrics
+  tiki doctor           Report broken links in dokis and tikis
+  tiki export [dir]     Render dokis, tikis and boards to a static HTML site
+  tiki export --board name
+                        Print a board as a markdown report (--output file.md to save it)
+  tiki import github|jira|trello file
+                        Create or update tikis from a GitHub, Jira or Trello export
+    --mapping file.yaml How Jira or Trello values map to tiki fields
+    --dry-run           List the changes without writing them
+  tiki serve            Serve tikis over a local HTTP/JSON API (--addr host:port)
+  tiki mcp              Serve tiki tools to AI agents over MCP on stdio
+  tiki workspace        Open one board over the repositories listed in the user config
   tiki sysinfo          Display system information
   tiki --version        Show version
 
@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/plugin/filter"
+	"github.com/boolean-maybe/tiki/store/tikistore"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// newTestServer serves a TikiStore in a temporary project with a two-lane board
+func newTestServer(t *testing.T) (*Server, *tikistore.TikiStore) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	t.Cleanup(config.ResetPathManager)
+
+	s, err := tikistore.NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+	ready, _ := filter.ParseFilter("status = 'ready'")
+	review, _ := filter.ParseFilter("status = 'review'")
+	toReview, err := plugin.ParseLaneAction("status=review, tags+=[reviewing]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := &plugin.TikiPlugin{
+		BasePlugin: plugin.BasePlugin{Name: "Kanban", Type: "tiki"},
+		Lanes: []plugin.TikiLane{
+			{Name: "Ready", Filter: ready},
+			{Name: "Review", Filter: review, Action: toReview},
+		},
+	}
+	return New(s, []plugin.Plugin{board}, "test"), s
+}
+
+// session sends the messages to the server and returns its responses
+func session(t *testing.T, srv *Server, messages ...string) []response {
+	t.Helper()
+	var out bytes.Buffer
+	if err := srv.Serve(strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var responses []response
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp response
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+// call runs a tool and returns its result text and whether it failed
+func call(t *testing.T, srv *Server, name, args string) (string, bool) {
+	t.Helper()
+	msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + name + `","arguments":` + args + `}}`
+	responses := session(t, srv, msg)
+	if len(responses) != 1 || responses[0].Error != nil {
+		t.Fatalf("%s: unexpected responses %+v", name, responses)
+	}
+	result := responses[0].Result.(map[string]any)
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+	isError, _ := result["isError"].(bool)
+	return text, isError
+}
+
+func TestProtocol(t *testing.T) {
+	srv, _ := newTestServer(t)
+	responses := session(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"drop_tables","arguments":{}}}`,
+	)
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses (none for the notification), got %d: %+v", len(responses), responses)
+	}
+
+	init := responses[0].Result.(map[string]any)
+	if init["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's 2025-03-26", init["protocolVersion"])
+	}
+	if info := init["serverInfo"].(map[string]any); info["name"] != "tiki" || info["version"] != "test" {
+		t.Errorf("serverInfo = %v", info)
+	}
+
+	var names []string
+	for _, tl := range responses[1].Result.(map[string]any)["tools"].([]any) {
+		names = append(names, tl.(map[string]any)["name"].(string))
+	}
+	if got := strings.Join(names, ","); got != "list_views,search_tikis,get_tiki,create_tiki,update_tiki,move_tiki" {
+		t.Errorf("tools = %s", got)
+	}
+
+	if e := responses[2].Error; e == nil || e.Code != codeMethodNotFound {
+		t.Errorf("unknown method: error = %+v, want %d", e, codeMethodNotFound)
+	}
+	if e := responses[3].Error; e == nil || e.Code != codeParseError || string(responses[3].ID) != "null" {
+		t.Errorf("bad JSON: %+v, want a parse error with null id", responses[3])
+	}
+	if e := responses[4].Error; e == nil || e.Code != codeInvalidParams || string(responses[4].ID) != "4" {
+		t.Errorf("unknown tool: %+v, want invalid params for id 4", responses[4])
+	}
+}
+
+func TestTools(t *testing.T) {
+	srv, s := newTestServer(t)
+
+	text, isError := call(t, srv, "create_tiki", `{"title":"Fix login","type":"bug","status":"ready","priority":2,"tags":["auth"]}`)
+	if isError {
+		t.Fatalf("create_tiki failed: %s", text)
+	}
+	var created tikiDetail
+	if err := json.Unmarshal([]byte(text), &created); err != nil {
+		t.Fatal(err)
+	}
+	stored := s.GetTask(created.ID)
+	if stored == nil || stored.Type != task.TypeBug || stored.Status != task.StatusReady || stored.Priority != 2 {
+		t.Fatalf("stored tiki = %+v", stored)
+	}
+
+	text, _ = call(t, srv, "list_views", `{}`)
+	if !strings.Contains(text, `"name": "Ready"`) || !strings.Contains(text, created.ID) {
+		t.Errorf("list_views should show %s in Ready:\n%s", created.ID, text)
+	}
+
+	text, _ = call(t, srv, "search_tikis", `{"filter":"type = 'bug'","query":"login"}`)
+	if !strings.Contains(text, created.ID) || !strings.Contains(text, `"total": 1`) {
+		t.Errorf("search_tikis should find %s:\n%s", created.ID, text)
+	}
+	text, _ = call(t, srv, "search_tikis", `{"view":"kanban","lane":"review"}`)
+	if !strings.Contains(text, `"total": 0`) {
+		t.Errorf("Review lane should be empty:\n%s", text)
+	}
+
+	if text, isError = call(t, srv, "update_tiki", `{"id":"`+created.ID+`","assignee":"alice","points":3}`); isError {
+		t.Fatalf("update_tiki failed: %s", text)
+	}
+	if stored := s.GetTask(created.ID); stored.Assignee != "alice" || stored.Points != 3 || stored.Title != "Fix login" {
+		t.Errorf("after update: %+v", stored)
+	}
+
+	if text, isError = call(t, srv, "move_tiki", `{"id":"`+created.ID+`","lane":"Review"}`); isError {
+		t.Fatalf("move_tiki to lane failed: %s", text)
+	}
+	stored = s.GetTask(created.ID)
+	if stored.Status != task.StatusReview || !strings.Contains(strings.Join(stored.Tags, ","), "reviewing") {
+		t.Errorf("move to Review should apply the lane action: %+v", stored)
+	}
+	if text, isError = call(t, srv, "move_tiki", `{"id":"`+created.ID+`","status":"done"}`); isError {
+		t.Fatalf("move_tiki to status failed: %s", text)
+	}
+	if stored := s.GetTask(created.ID); stored.Status != task.StatusDone {
+		t.Errorf("status = %s, want done", stored.Status)
+	}
+
+	text, _ = call(t, srv, "get_tiki", `{"id":"`+strings.ToLower(created.ID)+`"}`)
+	if !strings.Contains(text, `"status": "done"`) {
+		t.Errorf("get_tiki:\n%s", text)
+	}
+}
+
+func TestToolErrors(t *testing.T) {
+	srv, s := newTestServer(t)
+
+	tests := []struct {
+		name string
+		tool string
+		args string
+		want string
+	}{
+		{"missing title", "create_tiki", `{"type":"bug"}`, "title is required"},
+		{"unknown type", "create_tiki", `{"title":"x","type":"chore"}`, `unknown type "chore"`},
+		{"invalid priority", "create_tiki", `{"title":"x","priority":9}`, "validation failed"},
+		{"unknown argument", "create_tiki", `{"title":"x","colour":"red"}`, "unknown field"},
+		{"missing tiki", "get_tiki", `{"id":"TIKI-NOPE00"}`, "no tiki TIKI-NOPE00"},
+		{"bad filter", "search_tikis", `{"filter":"status ="}`, "filter"},
+		{"move missing tiki", "move_tiki", `{"id":"TIKI-NOPE00","lane":"Ready"}`, "no tiki"},
+		{"unknown lane", "search_tikis", `{"lane":"Nowhere"}`, `no lane "Nowhere"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := call(t, srv, tt.tool, tt.args)
+			if !isError || !strings.Contains(text, tt.want) {
+				t.Errorf("got %q (isError %v), want an error containing %q", text, isError, tt.want)
+			}
+		})
+	}
+
+	text, _ := call(t, srv, "create_tiki", `{"title":"Ready one","status":"ready"}`)
+	var created tikiDetail
+	_ = json.Unmarshal([]byte(text), &created)
+	text, isError := call(t, srv, "move_tiki", `{"id":"`+created.ID+`","lane":"Ready"}`)
+	if !isError || !strings.Contains(text, "give a status instead") {
+		t.Errorf("moving to a lane without an action: %q", text)
+	}
+	if len(s.GetAllTasks()) != 1 {
+		t.Errorf("failed calls should create nothing, have %d tikis", len(s.GetAllTasks()))
+	}
+}
@@ -0,0 +1,543 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/plugin/filter"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// tool is a tool as listed to clients
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	handler     func(srv *Server, args json.RawMessage) (any, error)
+}
+
+// defaultSearchLimit caps search results unless the agent asks for more
+const defaultSearchLimit = 50
+
+var tools = []tool{
+	{
+		Name:        "list_views",
+		Description: "List the board views with their lanes and the IDs of the tikis in each lane.",
+		InputSchema: object(nil),
+		handler:     (*Server).listViews,
+	},
+	{
+		Name:        "search_tikis",
+		Description: "Find tikis by title, filter expression and/or board lane. Returns summaries without descriptions.",
+		InputSchema: object(map[string]any{
+			"query":  str("Case-insensitive text to find in titles"),
+			"filter": str(`Filter expression, e.g. "status = 'ready' and type = 'bug'" or "tags in ['ui'] and priority <= 2"`),
+			"view":   str("Board view name, from list_views"),
+			"lane":   str("Lane of the view; the tikis it shows, in board order"),
+			"limit":  integer("Maximum number of results, default 50", 1, 1000),
+		}),
+		handler: (*Server).searchTikis,
+	},
+	{
+		Name:        "get_tiki",
+		Description: "Get a tiki with its description and comments.",
+		InputSchema: object(map[string]any{"id": str("Tiki ID, e.g. TIKI-ABC123")}, "id"),
+		handler:     (*Server).getTiki,
+	},
+	{
+		Name:        "create_tiki",
+		Description: "Create a tiki. Fields left out come from the template (new.md, or the named one).",
+		InputSchema: object(withFields(map[string]any{
+			"template": str("Named task template to start from, e.g. bug"),
+		}), "title"),
+		handler: (*Server).createTiki,
+	},
+	{
+		Name:        "update_tiki",
+		Description: "Change fields of a tiki. Fields left out keep their value.",
+		InputSchema: object(withFields(map[string]any{"id": str("Tiki ID")}), "id"),
+		handler:     (*Server).updateTiki,
+	},
+	{
+		Name:        "move_tiki",
+		Description: "Move a tiki to a status, or to a lane of a board view the way the board moves it (applying the lane's action).",
+		InputSchema: object(map[string]any{
+			"id":     str("Tiki ID"),
+			"status": statusSchema(),
+			"view":   str("Board view name, with lane"),
+			"lane":   str("Lane to move the tiki to"),
+		}, "id"),
+		handler: (*Server).moveTiki,
+	},
+}
+
+func toolList() []tool {
+	return tools
+}
+
+// callTool runs a tool. Failures of the tool itself are results flagged as errors, so
+// the agent sees them; an unknown tool is a protocol error.
+func (srv *Server) callTool(name string, args json.RawMessage) (any, error) {
+	for _, t := range tools {
+		if t.Name != name {
+			continue
+		}
+		// the TUI or a person may have changed tikis since the last call
+		if err := srv.store.Reload(); err != nil {
+			return toolError(fmt.Errorf("reload tikis: %w", err)), nil
+		}
+		result, err := t.handler(srv, args)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return toolResult(result), nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+}
+
+func toolResult(v any) map[string]any {
+	text, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return toolError(err)
+	}
+	return map[string]any{
+		"content":           []map[string]any{{"type": "text", "text": string(text)}},
+		"structuredContent": v,
+	}
+}
+
+func toolError(err error) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
+
+// decodeArgs reads tool arguments strictly: a misspelt field is an error, not ignored
+func decodeArgs(args json.RawMessage, v any) error {
+	if len(bytes.TrimSpace(args)) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// tikiSummary is a tiki in lists
+type tikiSummary struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Type     string   `json:"type"`
+	Status   string   `json:"status"`
+	Priority int      `json:"priority"`
+	Points   int      `json:"points"`
+	Assignee string   `json:"assignee,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// tikiDetail is a tiki with everything it holds
+type tikiDetail struct {
+	tikiSummary
+	Description string            `json:"description"`
+	ExternalID  string            `json:"external_id,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Comments    []task.Comment    `json:"comments,omitempty"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at,omitzero"`
+	UpdatedAt   time.Time         `json:"updated_at,omitzero"`
+}
+
+func summarize(t *task.Task) tikiSummary {
+	return tikiSummary{
+		ID:       t.ID,
+		Title:    t.Title,
+		Type:     string(t.Type),
+		Status:   string(t.Status),
+		Priority: t.Priority,
+		Points:   t.Points,
+		Assignee: t.Assignee,
+		Tags:     t.Tags,
+	}
+}
+
+func detail(t *task.Task) tikiDetail {
+	return tikiDetail{
+		tikiSummary: summarize(t),
+		Description: t.Description,
+		ExternalID:  t.ExternalID,
+		Fields:      t.Fields,
+		Comments:    t.Comments,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+type laneView struct {
+	Name  string   `json:"name"`
+	Tikis []string `json:"tikis"`
+}
+
+type boardView struct {
+	Name  string     `json:"name"`
+	Lanes []laneView `json:"lanes"`
+}
+
+func (srv *Server) listViews(args json.RawMessage) (any, error) {
+	if err := decodeArgs(args, &struct{}{}); err != nil {
+		return nil, err
+	}
+	tasks := srv.store.GetAllTasks()
+	now := srv.now()
+	currentUser := srv.currentUser()
+
+	views := []boardView{}
+	for _, p := range srv.plugins {
+		board, ok := p.(*plugin.TikiPlugin)
+		if !ok {
+			continue
+		}
+		view := boardView{Name: board.Name}
+		for i, lane := range board.Lanes {
+			ids := []string{}
+			for _, t := range board.LaneTasks(i, tasks, now, currentUser) {
+				ids = append(ids, t.ID)
+			}
+			view.Lanes = append(view.Lanes, laneView{Name: lane.Name, Tikis: ids})
+		}
+		views = append(views, view)
+	}
+	return map[string]any{"views": views}, nil
+}
+
+func (srv *Server) searchTikis(args json.RawMessage) (any, error) {
+	var in struct {
+		Query  string `json:"query"`
+		Filter string `json:"filter"`
+		View   string `json:"view"`
+		Lane   string `json:"lane"`
+		Limit  int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+
+	tasks := srv.store.GetAllTasks()
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	if in.View != "" || in.Lane != "" {
+		board, lane, err := srv.findLane(in.View, in.Lane)
+		if err != nil {
+			return nil, err
+		}
+		tasks = board.LaneTasks(lane, tasks, srv.now(), srv.currentUser())
+	}
+	if in.Query != "" {
+		matches := make(map[string]bool)
+		for _, result := range srv.store.Search(in.Query, nil) {
+			matches[result.Task.ID] = true
+		}
+		tasks = keep(tasks, func(t *task.Task) bool { return matches[t.ID] })
+	}
+	if in.Filter != "" {
+		f, err := filter.ParseFilter(in.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		if f != nil {
+			now, currentUser := srv.now(), srv.currentUser()
+			tasks = keep(tasks, func(t *task.Task) bool { return f.Evaluate(t, now, currentUser) })
+		}
+	}
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	total := len(tasks)
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	summaries := make([]tikiSummary, 0, len(tasks))
+	for _, t := range tasks {
+		summaries = append(summaries, summarize(t))
+	}
+	return map[string]any{"tikis": summaries, "total": total}, nil
+}
+
+func (srv *Server) getTiki(args json.RawMessage) (any, error) {
+	var in struct {
+		ID string `json:"id"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	t, err := srv.lookup(in.ID)
+	if err != nil {
+		return nil, err
+	}
+	return detail(t), nil
+}
+
+func (srv *Server) createTiki(args json.RawMessage) (any, error) {
+	var in struct {
+		tikiFields
+		Template string `json:"template"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Title == nil || strings.TrimSpace(*in.Title) == "" {
+		return nil, errors.New("title is required")
+	}
+
+	t, err := srv.store.NewTaskFromTemplate(in.Template, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.apply(t); err != nil {
+		return nil, err
+	}
+	if errs := task.QuickValidate(t); errs.HasErrors() {
+		return nil, fmt.Errorf("validation failed: %s", errs.Error())
+	}
+	if err := srv.store.CreateTask(t); err != nil {
+		return nil, saveError(err)
+	}
+	return detail(t), nil
+}
+
+func (srv *Server) updateTiki(args json.RawMessage) (any, error) {
+	var in struct {
+		tikiFields
+		ID string `json:"id"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	current, err := srv.lookup(in.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := current.Clone()
+	if err := in.apply(updated); err != nil {
+		return nil, err
+	}
+	return srv.save(updated)
+}
+
+func (srv *Server) moveTiki(args json.RawMessage) (any, error) {
+	var in struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		View   string `json:"view"`
+		Lane   string `json:"lane"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	current, err := srv.lookup(in.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case in.Status != "" && (in.View != "" || in.Lane != ""):
+		return nil, errors.New("give either a status or a view and lane")
+	case in.Status != "":
+		status, ok := task.ParseStatus(in.Status)
+		if !ok {
+			return nil, fmt.Errorf("unknown status %q", in.Status)
+		}
+		updated := current.Clone()
+		updated.Status = status
+		return srv.save(updated)
+	case in.Lane != "":
+		board, lane, err := srv.findLane(in.View, in.Lane)
+		if err != nil {
+			return nil, err
+		}
+		action := board.Lanes[lane].Action
+		if len(action.Ops) == 0 {
+			return nil, fmt.Errorf("lane %q of %q doesn't change tikis moved to it: give a status instead", board.Lanes[lane].Name, board.Name)
+		}
+		updated, err := plugin.ApplyLaneAction(current, action, srv.currentUser())
+		if err != nil {
+			return nil, err
+		}
+		return srv.save(updated)
+	default:
+		return nil, errors.New("give a status, or a view and lane to move the tiki to")
+	}
+}
+
+// save validates and writes an updated tiki
+func (srv *Server) save(updated *task.Task) (any, error) {
+	if errs := task.QuickValidate(updated); errs.HasErrors() {
+		return nil, fmt.Errorf("validation failed: %s", errs.Error())
+	}
+	if err := srv.store.UpdateTask(updated); err != nil {
+		return nil, saveError(err)
+	}
+	return detail(updated), nil
+}
+
+func saveError(err error) error {
+	if errors.Is(err, store.ErrConflict) {
+		return fmt.Errorf("%w: get the tiki again and retry", err)
+	}
+	return err
+}
+
+func (srv *Server) lookup(id string) (*task.Task, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id is required")
+	}
+	t := srv.store.GetTask(id)
+	if t == nil {
+		return nil, fmt.Errorf("no tiki %s", strings.ToUpper(id))
+	}
+	return t, nil
+}
+
+// findLane finds a lane by name in the named view, or in the only view that has it
+func (srv *Server) findLane(viewName, laneName string) (*plugin.TikiPlugin, int, error) {
+	if laneName == "" {
+		return nil, 0, errors.New("lane is required with view")
+	}
+	var found *plugin.TikiPlugin
+	foundLane := -1
+	for _, p := range srv.plugins {
+		board, ok := p.(*plugin.TikiPlugin)
+		if !ok || (viewName != "" && !strings.EqualFold(board.Name, viewName)) {
+			continue
+		}
+		for i, lane := range board.Lanes {
+			if !strings.EqualFold(lane.Name, laneName) {
+				continue
+			}
+			if found != nil {
+				return nil, 0, fmt.Errorf("lane %q is in %q and %q: give the view", laneName, found.Name, board.Name)
+			}
+			found, foundLane = board, i
+		}
+	}
+	if found == nil {
+		if viewName != "" {
+			return nil, 0, fmt.Errorf("no lane %q in view %q: see list_views", laneName, viewName)
+		}
+		return nil, 0, fmt.Errorf("no lane %q: see list_views", laneName)
+	}
+	return found, foundLane, nil
+}
+
+func (srv *Server) currentUser() string {
+	name, _, _ := srv.store.GetCurrentUser()
+	return name
+}
+
+func keep(tasks []*task.Task, match func(*task.Task) bool) []*task.Task {
+	var kept []*task.Task
+	for _, t := range tasks {
+		if match(t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// tikiFields are the fields create_tiki and update_tiki set; those left out are unchanged
+type tikiFields struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Type        *string   `json:"type"`
+	Status      *string   `json:"status"`
+	Tags        *[]string `json:"tags"`
+	Assignee    *string   `json:"assignee"`
+	Priority    *int      `json:"priority"`
+	Points      *int      `json:"points"`
+}
+
+func (f tikiFields) apply(t *task.Task) error {
+	if f.Title != nil {
+		t.Title = strings.TrimSpace(*f.Title)
+	}
+	if f.Description != nil {
+		t.Description = *f.Description
+	}
+	if f.Type != nil {
+		taskType, ok := task.ParseType(*f.Type)
+		if !ok {
+			return fmt.Errorf("unknown type %q", *f.Type)
+		}
+		t.Type = taskType
+	}
+	if f.Status != nil {
+		status, ok := task.ParseStatus(*f.Status)
+		if !ok || *f.Status == "" {
+			return fmt.Errorf("unknown status %q", *f.Status)
+		}
+		t.Status = status
+	}
+	if f.Tags != nil {
+		t.Tags = *f.Tags
+	}
+	if f.Assignee != nil {
+		t.Assignee = *f.Assignee
+	}
+	if f.Priority != nil {
+		t.Priority = *f.Priority
+	}
+	if f.Points != nil {
+		t.Points = *f.Points
+	}
+	return nil
+}
+
+// JSON Schema helpers for tool inputs
+
+func object(properties map[string]any, required ...string) map[string]any {
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func str(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func integer(description string, minimum, maximum int) map[string]any {
+	return map[string]any{"type": "integer", "description": description, "minimum": minimum, "maximum": maximum}
+}
+
+func statusSchema() map[string]any {
+	return map[string]any{"type": "string", "enum": []string{"backlog", "ready", "in_progress", "review", "done"}}
+}
+
+// withFields adds the tiki fields to a tool's own properties
+func withFields(properties map[string]any) map[string]any {
+	properties["title"] = str("One-line title")
+	properties["description"] = str("Markdown description")
+	properties["type"] = map[string]any{"type": "string", "enum": []string{"story", "bug", "spike", "epic"}}
+	properties["status"] = statusSchema()
+	properties["tags"] = map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	properties["assignee"] = str("Assignee name")
+	properties["priority"] = integer("1 (highest) to 5 (lowest)", 1, 5)
+	properties["points"] = map[string]any{"type": "integer", "description": "Story points, 0 when not estimated", "minimum": 0}
+	return properties
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// CFDStatuses is the bottom-to-top stacking order of a cumulative flow diagram.
+var CFDStatuses = []task.Status{
+	task.StatusDone,
+	task.StatusReview,
+	task.StatusInProgress,
+	task.StatusReady,
+	task.StatusBacklog,
+}
+
+// FlowPoint holds the number of tasks in each status at the end of a day.
+type FlowPoint struct {
+	Date   time.Time
+	Counts map[task.Status]int
+}
+
+// Total returns the number of tasks that existed at the point.
+func (p FlowPoint) Total() int {
+	total := 0
+	for _, n := range p.Counts {
+		total += n
+	}
+	return total
+}
+
+// CumulativeFlow samples the status of every task at the end of each of the
+// `days` days ending with the day that contains `end` (local time).
+func CumulativeFlow(transitions map[string][]store.StatusChange, end time.Time, days int) []FlowPoint {
+	if days <= 0 {
+		return nil
+	}
+
+	lastDay := dayStart(end)
+	points := make([]FlowPoint, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		date := lastDay.AddDate(0, 0, -i)
+		cutoff := date.AddDate(0, 0, 1)
+		point := FlowPoint{Date: date, Counts: make(map[task.Status]int)}
+		for _, changes := range transitions {
+			if status, ok := statusAt(changes, cutoff); ok {
+				point.Counts[status]++
+			}
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// statusAt returns the status a task had just before cutoff; false if it did not exist yet.
+func statusAt(changes []store.StatusChange, cutoff time.Time) (task.Status, bool) {
+	var status task.Status
+	found := false
+	for _, change := range changes {
+		if !change.At.Before(cutoff) {
+			break
+		}
+		status = change.To
+		found = true
+	}
+	return status, found
+}
+
+func dayStart(t time.Time) time.Time {
+	local := t.Local()
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func TestCumulativeFlow(t *testing.T) {
+	day1 := time.Date(2024, 6, 1, 10, 0, 0, 0, time.Local)
+	transitions := map[string][]store.StatusChange{
+		"TIKI-1": {
+			change("TIKI-1", "", task.StatusBacklog, day1),
+			change("TIKI-1", task.StatusBacklog, task.StatusInProgress, day1.AddDate(0, 0, 1)),
+			change("TIKI-1", task.StatusInProgress, task.StatusDone, day1.AddDate(0, 0, 2)),
+		},
+		"TIKI-2": {
+			change("TIKI-2", "", task.StatusReady, day1.AddDate(0, 0, 1)),
+		},
+	}
+
+	points := CumulativeFlow(transitions, day1.AddDate(0, 0, 2), 4)
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(points))
+	}
+
+	// the day before anything existed
+	if points[0].Total() != 0 {
+		t.Errorf("expected empty first day, got %v", points[0].Counts)
+	}
+	if points[1].Counts[task.StatusBacklog] != 1 || points[1].Total() != 1 {
+		t.Errorf("day 1: unexpected counts %v", points[1].Counts)
+	}
+	if points[2].Counts[task.StatusInProgress] != 1 || points[2].Counts[task.StatusReady] != 1 {
+		t.Errorf("day 2: unexpected counts %v", points[2].Counts)
+	}
+	if points[3].Counts[task.StatusDone] != 1 || points[3].Total() != 2 {
+		t.Errorf("day 3: unexpected counts %v", points[3].Counts)
+	}
+	if !points[3].Date.Equal(dayStart(day1.AddDate(0, 0, 2))) {
+		t.Errorf("last point should be the end day, got %v", points[3].Date)
+	}
+}
@@ -0,0 +1,258 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// Flow metrics derived from task status history: lead time (created → done),
+// cycle time (first in progress → done) and time spent in each status.
+
+const (
+	unknownType        = "unknown"
+	unassignedAssignee = "unassigned"
+)
+
+// TaskFlow holds the flow metrics of a single task.
+type TaskFlow struct {
+	TaskID       string
+	Title        string
+	Type         string
+	Assignee     string
+	Status       task.Status
+	Created      time.Time
+	Started      time.Time // first entry into in_progress, zero if never started
+	Done         time.Time // entry into done, zero unless the task is currently done
+	LeadTime     time.Duration
+	CycleTime    time.Duration
+	TimeInStatus map[task.Status]time.Duration
+}
+
+// IsDone reports whether the task is completed and has a lead time.
+func (f TaskFlow) IsDone() bool {
+	return !f.Done.IsZero()
+}
+
+// HasCycleTime reports whether the task went through in progress before completion.
+func (f TaskFlow) HasCycleTime() bool {
+	return f.IsDone() && !f.Started.IsZero()
+}
+
+// Percentiles summarizes a duration distribution.
+type Percentiles struct {
+	P50 time.Duration
+	P85 time.Duration
+	P95 time.Duration
+}
+
+// GroupStats aggregates completed tasks that share a type or an assignee.
+type GroupStats struct {
+	Name       string
+	Completed  int
+	CycleCount int
+	Lead       Percentiles
+	Cycle      Percentiles
+}
+
+// Report is the complete set of flow metrics for a project.
+type Report struct {
+	GeneratedAt    time.Time
+	Tasks          []TaskFlow
+	Overall        GroupStats
+	ByType         []GroupStats
+	ByAssignee     []GroupStats
+	TimeInStatus   map[task.Status]time.Duration // average per task that visited the status
+	FlowEfficiency float64                       // in-progress time over lead time of completed tasks
+}
+
+// Compute builds a report from full status histories (see store.NewFullTaskHistory).
+// Tasks supply titles, types and assignees; histories of deleted tasks are still counted.
+func Compute(transitions map[string][]store.StatusChange, tasks []*task.Task, now time.Time) *Report {
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	report := &Report{
+		GeneratedAt:  now,
+		TimeInStatus: make(map[task.Status]time.Duration),
+	}
+
+	statusTotals := make(map[task.Status]time.Duration)
+	statusVisits := make(map[task.Status]int)
+	var workTime, leadTime time.Duration
+
+	for taskID, changes := range transitions {
+		if len(changes) == 0 {
+			continue
+		}
+		flow := computeTaskFlow(taskID, changes, now)
+		if t, ok := byID[taskID]; ok {
+			flow.Title = t.Title
+			flow.Type = string(t.Type)
+			flow.Assignee = t.Assignee
+		}
+		if flow.Type == "" {
+			flow.Type = unknownType
+		}
+		if flow.Assignee == "" {
+			flow.Assignee = unassignedAssignee
+		}
+
+		for status, d := range flow.TimeInStatus {
+			statusTotals[status] += d
+			statusVisits[status]++
+		}
+		if flow.IsDone() {
+			workTime += flow.TimeInStatus[task.StatusInProgress]
+			leadTime += flow.LeadTime
+		}
+
+		report.Tasks = append(report.Tasks, flow)
+	}
+
+	for status, total := range statusTotals {
+		report.TimeInStatus[status] = total / time.Duration(statusVisits[status])
+	}
+	if leadTime > 0 {
+		report.FlowEfficiency = float64(workTime) / float64(leadTime)
+	}
+
+	// most recently completed first, open tasks last
+	sort.SliceStable(report.Tasks, func(i, j int) bool {
+		a, b := report.Tasks[i], report.Tasks[j]
+		if !a.Done.Equal(b.Done) {
+			return a.Done.After(b.Done)
+		}
+		return a.TaskID < b.TaskID
+	})
+
+	report.Overall = groupStats("all", report.Tasks)
+	report.ByType = groupBy(report.Tasks, func(f TaskFlow) string { return f.Type })
+	report.ByAssignee = groupBy(report.Tasks, func(f TaskFlow) string { return f.Assignee })
+
+	return report
+}
+
+// Generate loads the full status history from the store and computes the report.
+func Generate(s store.Store, now time.Time) (*Report, error) {
+	transitions, err := s.GetStatusTransitions()
+	if err != nil {
+		return nil, fmt.Errorf("load status history: %w", err)
+	}
+	return Compute(transitions, s.GetAllTasks(), now), nil
+}
+
+// computeTaskFlow walks a task's ordered status changes.
+func computeTaskFlow(taskID string, changes []store.StatusChange, now time.Time) TaskFlow {
+	flow := TaskFlow{
+		TaskID:       taskID,
+		Created:      changes[0].At,
+		Status:       changes[len(changes)-1].To,
+		TimeInStatus: make(map[task.Status]time.Duration),
+	}
+
+	for i, change := range changes {
+		if change.To == task.StatusInProgress && flow.Started.IsZero() {
+			flow.Started = change.At
+		}
+
+		end := now
+		if i+1 < len(changes) {
+			end = changes[i+1].At
+		} else if change.To == task.StatusDone {
+			flow.Done = change.At
+			continue // time spent done is not part of the flow
+		}
+		if end.After(change.At) {
+			flow.TimeInStatus[change.To] += end.Sub(change.At)
+		}
+	}
+
+	if flow.IsDone() {
+		flow.LeadTime = flow.Done.Sub(flow.Created)
+		if !flow.Started.IsZero() && flow.Started.Before(flow.Done) {
+			flow.CycleTime = flow.Done.Sub(flow.Started)
+		} else {
+			flow.Started = time.Time{}
+		}
+	}
+
+	return flow
+}
+
+// groupBy partitions flows by key and computes stats per group, sorted by name.
+func groupBy(flows []TaskFlow, key func(TaskFlow) string) []GroupStats {
+	groups := make(map[string][]TaskFlow)
+	for _, f := range flows {
+		if !f.IsDone() {
+			continue
+		}
+		groups[key(f)] = append(groups[key(f)], f)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]GroupStats, 0, len(names))
+	for _, name := range names {
+		result = append(result, groupStats(name, groups[name]))
+	}
+	return result
+}
+
+// groupStats computes percentiles over the completed flows in the group.
+func groupStats(name string, flows []TaskFlow) GroupStats {
+	var leads, cycles []time.Duration
+	for _, f := range flows {
+		if !f.IsDone() {
+			continue
+		}
+		leads = append(leads, f.LeadTime)
+		if f.HasCycleTime() {
+			cycles = append(cycles, f.CycleTime)
+		}
+	}
+
+	return GroupStats{
+		Name:       name,
+		Completed:  len(leads),
+		CycleCount: len(cycles),
+		Lead:       percentiles(leads),
+		Cycle:      percentiles(cycles),
+	}
+}
+
+func percentiles(durations []time.Duration) Percentiles {
+	return Percentiles{
+		P50: Percentile(durations, 50),
+		P85: Percentile(durations, 85),
+		P95: Percentile(durations, 95),
+	}
+}
+
+// Percentile returns the nearest-rank percentile p (0-100) of durations, or 0 when empty.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func change(id string, from, to task.Status, at time.Time) store.StatusChange {
+	return store.StatusChange{TaskID: id, From: from, To: to, At: at}
+}
+
+func TestCompute(t *testing.T) {
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	now := base.Add(30 * day)
+
+	transitions := map[string][]store.StatusChange{
+		"TIKI-1": {
+			change("TIKI-1", "", task.StatusBacklog, base),
+			change("TIKI-1", task.StatusBacklog, task.StatusInProgress, base.Add(2*day)),
+			change("TIKI-1", task.StatusInProgress, task.StatusDone, base.Add(5*day)),
+		},
+		"TIKI-2": {
+			change("TIKI-2", "", task.StatusReady, base),
+			change("TIKI-2", task.StatusReady, task.StatusDone, base.Add(4*day)),
+		},
+		"TIKI-3": {
+			change("TIKI-3", "", task.StatusInProgress, base.Add(10*day)),
+		},
+	}
+	tasks := []*task.Task{
+		{ID: "TIKI-1", Title: "Story one", Type: task.TypeStory, Assignee: "alice"},
+		{ID: "TIKI-2", Title: "Bug two", Type: task.TypeBug},
+		{ID: "TIKI-3", Title: "Open work", Type: task.TypeStory, Assignee: "alice"},
+	}
+
+	report := Compute(transitions, tasks, now)
+
+	if report.Overall.Completed != 2 || report.Overall.CycleCount != 1 {
+		t.Fatalf("unexpected overall stats: %+v", report.Overall)
+	}
+	if report.Overall.Lead.P50 != 4*day || report.Overall.Lead.P95 != 5*day {
+		t.Errorf("unexpected lead percentiles: %+v", report.Overall.Lead)
+	}
+	if report.Overall.Cycle.P50 != 3*day {
+		t.Errorf("expected cycle p50 of 3 days, got %v", report.Overall.Cycle.P50)
+	}
+
+	if len(report.ByType) != 2 || report.ByType[0].Name != "bug" || report.ByType[1].Name != "story" {
+		t.Errorf("unexpected type groups: %+v", report.ByType)
+	}
+	if len(report.ByAssignee) != 2 || report.ByAssignee[1].Name != unassignedAssignee {
+		t.Errorf("unexpected assignee groups: %+v", report.ByAssignee)
+	}
+
+	// most recently completed first, open task last
+	if report.Tasks[0].TaskID != "TIKI-1" || report.Tasks[2].TaskID != "TIKI-3" {
+		t.Errorf("unexpected task order: %s, %s, %s", report.Tasks[0].TaskID, report.Tasks[1].TaskID, report.Tasks[2].TaskID)
+	}
+	if got := report.Tasks[2].TimeInStatus[task.StatusInProgress]; got != 20*day {
+		t.Errorf("open task should accumulate time until now, got %v", got)
+	}
+
+	// in progress: 3 days of 9 days total lead time
+	if report.FlowEfficiency < 0.33 || report.FlowEfficiency > 0.34 {
+		t.Errorf("unexpected flow efficiency %v", report.FlowEfficiency)
+	}
+
+	md := report.Markdown()
+	for _, want := range []string{"# Flow metrics", "## By type", "| story | 1 |", "TIKI-1"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{5, 1, 4, 2, 3}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 3},
+		{85, 5},
+		{95, 5},
+		{0, 1},
+	}
+	for _, tt := range tests {
+		if got := Percentile(durations, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 for empty input, got %v", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "<1m"},
+		{25 * time.Minute, "25m"},
+		{3*time.Hour + 5*time.Minute, "3h 5m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// maxReportTasks caps the completed task table in the markdown report.
+const maxReportTasks = 20
+
+// reportStatuses lists statuses in workflow order for the time-in-status table.
+var reportStatuses = []task.Status{
+	task.StatusBacklog,
+	task.StatusReady,
+	task.StatusInProgress,
+	task.StatusReview,
+}
+
+// Markdown renders the report as a markdown document suitable for doki views and the CLI.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# Flow metrics\n\n")
+	fmt.Fprintf(&sb, "Generated %s from git history. ", r.GeneratedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&sb, "%d tasks tracked, %d completed.\n\n", len(r.Tasks), r.Overall.Completed)
+
+	if r.Overall.Completed == 0 {
+		sb.WriteString("No completed tasks yet - lead and cycle times appear once tasks reach done.\n\n")
+	} else {
+		fmt.Fprintf(&sb, "Flow efficiency: **%.0f%%** (time in progress over lead time of completed tasks)\n\n", r.FlowEfficiency*100)
+
+		sb.WriteString("## Lead and cycle time\n\n")
+		writeGroupTable(&sb, "Scope", []GroupStats{r.Overall})
+
+		sb.WriteString("## By type\n\n")
+		writeGroupTable(&sb, "Type", r.ByType)
+
+		sb.WriteString("## By assignee\n\n")
+		writeGroupTable(&sb, "Assignee", r.ByAssignee)
+	}
+
+	sb.WriteString("## Average time in status\n\n")
+	sb.WriteString("| Status | Average |\n|---|---|\n")
+	for _, status := range reportStatuses {
+		d, ok := r.TimeInStatus[status]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&sb, "| %s | %s |\n", task.StatusLabel(status), FormatDuration(d))
+	}
+	sb.WriteString("\n")
+
+	if r.Overall.Completed > 0 {
+		sb.WriteString("## Recently completed\n\n")
+		sb.WriteString("| Task | Title | Lead | Cycle |\n|---|---|---|---|\n")
+		count := 0
+		for _, f := range r.Tasks {
+			if !f.IsDone() || count >= maxReportTasks {
+				continue
+			}
+			cycle := "-"
+			if f.HasCycleTime() {
+				cycle = FormatDuration(f.CycleTime)
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.TaskID, escapeCell(f.Title), FormatDuration(f.LeadTime), cycle)
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writeGroupTable renders percentile rows for a set of groups.
+func writeGroupTable(sb *strings.Builder, heading string, groups []GroupStats) {
+	fmt.Fprintf(sb, "| %s | Done | Lead p50 | Lead p85 | Lead p95 | Cycle p50 | Cycle p85 | Cycle p95 |\n", heading)
+	sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, g := range groups {
+		fmt.Fprintf(sb, "| %s | %d | %s | %s | %s | %s | %s | %s |\n",
+			escapeCell(g.Name), g.Completed,
+			FormatDuration(g.Lead.P50), FormatDuration(g.Lead.P85), FormatDuration(g.Lead.P95),
+			formatCycle(g, g.Cycle.P50), formatCycle(g, g.Cycle.P85), formatCycle(g, g.Cycle.P95))
+	}
+	sb.WriteString("\n")
+}
+
+func formatCycle(g GroupStats, d time.Duration) string {
+	if g.CycleCount == 0 {
+		return "-"
+	}
+	return FormatDuration(d)
+}
+
+// FormatDuration renders a duration with the two most significant units, e.g. "3d 4h" or "25m".
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// escapeCell keeps table cells on one line and free of column separators.
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// VelocityAverageSprints is the number of sprints in the velocity rolling average.
+const VelocityAverageSprints = 3
+
+// SprintVelocity is the work completed in one sprint.
+type SprintVelocity struct {
+	Sprint  config.Sprint
+	Points  int
+	Tasks   int
+	Average float64 // rolling average of Points over the last VelocityAverageSprints sprints
+}
+
+// Velocity sums the points of tasks completed in each sprint that has started by now.
+// A done task counts toward the sprint whose tag it carried when it was completed;
+// untagged tasks count toward the sprint in which they were completed. Tasks that
+// were reopened and are no longer done do not count.
+func Velocity(transitions map[string][]store.StatusChange, sprints []config.Sprint, now time.Time) []SprintVelocity {
+	var result []SprintVelocity
+	for _, sprint := range sprints {
+		if sprint.Start.After(now) {
+			continue
+		}
+		result = append(result, SprintVelocity{Sprint: sprint})
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	for _, changes := range transitions {
+		completion, ok := lastCompletion(changes)
+		if !ok {
+			continue
+		}
+		index := sprintFor(result, completion)
+		if index < 0 {
+			continue
+		}
+		result[index].Points += completion.Points
+		result[index].Tasks++
+	}
+
+	for i := range result {
+		first := i - VelocityAverageSprints + 1
+		if first < 0 {
+			first = 0
+		}
+		sum := 0
+		for _, v := range result[first : i+1] {
+			sum += v.Points
+		}
+		result[i].Average = float64(sum) / float64(i-first+1)
+	}
+
+	return result
+}
+
+// lastCompletion returns the change that finally moved the task to done.
+func lastCompletion(changes []store.StatusChange) (store.StatusChange, bool) {
+	if len(changes) == 0 || changes[len(changes)-1].To != task.StatusDone {
+		return store.StatusChange{}, false
+	}
+	return changes[len(changes)-1], true
+}
+
+// sprintFor picks the sprint a completion belongs to: by tag first, then by date.
+func sprintFor(velocities []SprintVelocity, completion store.StatusChange) int {
+	for i, v := range velocities {
+		if v.Sprint.HasTag(completion.Tags) {
+			return i
+		}
+	}
+	for i, v := range velocities {
+		if v.Sprint.Contains(completion.At) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+func completed(id string, at time.Time, points int, tags ...string) store.StatusChange {
+	c := change(id, task.StatusReview, task.StatusDone, at)
+	c.Points = points
+	c.Tags = tags
+	return c
+}
+
+func TestVelocity(t *testing.T) {
+	base := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	sprints := []config.Sprint{
+		{Name: "S1", Tag: "s1", Start: base, End: base.Add(14 * day)},
+		{Name: "S2", Tag: "s2", Start: base.Add(14 * day), End: base.Add(28 * day)},
+		{Name: "S3", Tag: "s3", Start: base.Add(28 * day), End: base.Add(42 * day)},
+		{Name: "Future", Tag: "s4", Start: base.Add(42 * day), End: base.Add(56 * day)},
+	}
+	now := base.Add(30 * day)
+
+	transitions := map[string][]store.StatusChange{
+		// completed during S1 by date
+		"TIKI-1": {completed("TIKI-1", base.Add(3*day), 5)},
+		// completed after S1 ended but tagged for it
+		"TIKI-2": {completed("TIKI-2", base.Add(15*day), 3, "S1")},
+		// completed in S2
+		"TIKI-3": {completed("TIKI-3", base.Add(20*day), 8)},
+		// done then reopened: does not count
+		"TIKI-4": {
+			completed("TIKI-4", base.Add(16*day), 2),
+			change("TIKI-4", task.StatusDone, task.StatusInProgress, base.Add(18*day)),
+		},
+		// done before any sprint
+		"TIKI-5": {completed("TIKI-5", base.Add(-5*day), 13)},
+	}
+
+	got := Velocity(transitions, sprints, now)
+	if len(got) != 3 {
+		t.Fatalf("len(Velocity()) = %d, want 3 started sprints", len(got))
+	}
+
+	want := []struct {
+		points  int
+		tasks   int
+		average float64
+	}{
+		{points: 8, tasks: 2, average: 8},
+		{points: 8, tasks: 1, average: 8},
+		{points: 0, tasks: 0, average: 16.0 / 3},
+	}
+	for i, w := range want {
+		if got[i].Points != w.points || got[i].Tasks != w.tasks {
+			t.Errorf("sprint %s = %d points / %d tasks, want %d / %d",
+				got[i].Sprint.Name, got[i].Points, got[i].Tasks, w.points, w.tasks)
+		}
+		if got[i].Average != w.average {
+			t.Errorf("sprint %s average = %v, want %v", got[i].Sprint.Name, got[i].Average, w.average)
+		}
+	}
+}
+
+func TestVelocityNoSprints(t *testing.T) {
+	if got := Velocity(nil, nil, time.Now()); got != nil {
+		t.Errorf("Velocity() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"sync"
+)
+
+// ChartWindows lists the selectable time windows (in days) for chart views.
+var ChartWindows = []int{7, 14, 30, 90, 180, 365}
+
+// DefaultChartWindow is the window used when a chart plugin does not configure one.
+const DefaultChartWindow = 30
+
+// ChartConfig holds the selected time window of a chart plugin view.
+// Thread-safe model that notifies listeners when the window changes.
+type ChartConfig struct {
+	mu         sync.RWMutex
+	pluginName string
+	window     int
+
+	listeners    map[int]func()
+	nextListener int
+}
+
+// NewChartConfig creates a chart config with the given window in days (0 = default)
+func NewChartConfig(name string, window int) *ChartConfig {
+	if window <= 0 {
+		window = DefaultChartWindow
+	}
+	return &ChartConfig{
+		pluginName:   name,
+		window:       window,
+		listeners:    make(map[int]func()),
+		nextListener: 1,
+	}
+}
+
+// GetPluginName returns the plugin name
+func (cc *ChartConfig) GetPluginName() string {
+	return cc.pluginName
+}
+
+// GetWindow returns the selected window in days
+func (cc *ChartConfig) GetWindow() int {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.window
+}
+
+// CycleWindow moves to the next longer (delta > 0) or shorter (delta < 0) preset window.
+// A configured window that is not a preset snaps to the nearest preset in that direction.
+// Returns true if the window changed.
+func (cc *ChartConfig) CycleWindow(delta int) bool {
+	cc.mu.Lock()
+	next := cc.window
+	if delta > 0 {
+		for _, w := range ChartWindows {
+			if w > cc.window {
+				next = w
+				break
+			}
+		}
+	} else if delta < 0 {
+		for i := len(ChartWindows) - 1; i >= 0; i-- {
+			if ChartWindows[i] < cc.window {
+				next = ChartWindows[i]
+				break
+			}
+		}
+	}
+	changed := next != cc.window
+	cc.window = next
+	cc.mu.Unlock()
+
+	if changed {
+		cc.notifyListeners()
+	}
+	return changed
+}
+
+// AddListener registers a callback for window changes and returns its ID
+func (cc *ChartConfig) AddListener(listener func()) int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	id := cc.nextListener
+	cc.nextListener++
+	cc.listeners[id] = listener
+	return id
+}
+
+// RemoveListener removes a previously registered listener by ID
+func (cc *ChartConfig) RemoveListener(id int) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.listeners, id)
+}
+
+// notifyListeners calls all registered listeners
+func (cc *ChartConfig) notifyListeners() {
+	cc.mu.RLock()
+	listeners := make([]func(), 0, len(cc.listeners))
+	for _, listener := range cc.listeners {
+		listeners = append(listeners, listener)
+	}
+	cc.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
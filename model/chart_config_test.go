@@ -0,0 +1,44 @@
+package model
+
+import "testing"
+
+func TestChartConfigDefaultsWindow(t *testing.T) {
+	cc := NewChartConfig("Flow", 0)
+	if cc.GetWindow() != DefaultChartWindow {
+		t.Errorf("GetWindow() = %d, want %d", cc.GetWindow(), DefaultChartWindow)
+	}
+}
+
+func TestChartConfigCycleWindow(t *testing.T) {
+	cc := NewChartConfig("Flow", 14)
+
+	notified := 0
+	cc.AddListener(func() { notified++ })
+
+	if !cc.CycleWindow(1) || cc.GetWindow() != 30 {
+		t.Fatalf("expected longer window 30, got %d", cc.GetWindow())
+	}
+	if !cc.CycleWindow(-1) || !cc.CycleWindow(-1) || cc.GetWindow() != 7 {
+		t.Fatalf("expected shortest window 7, got %d", cc.GetWindow())
+	}
+	if cc.CycleWindow(-1) {
+		t.Error("CycleWindow below the shortest preset should report no change")
+	}
+	if notified != 3 {
+		t.Errorf("expected 3 notifications, got %d", notified)
+	}
+}
+
+func TestChartConfigCycleWindowSnapsCustomValue(t *testing.T) {
+	cc := NewChartConfig("Flow", 45)
+	cc.CycleWindow(1)
+	if cc.GetWindow() != 90 {
+		t.Errorf("expected custom window to snap up to 90, got %d", cc.GetWindow())
+	}
+
+	cc = NewChartConfig("Flow", 45)
+	cc.CycleWindow(-1)
+	if cc.GetWindow() != 30 {
+		t.Errorf("expected custom window to snap down to 30, got %d", cc.GetWindow())
+	}
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"sync"
+
+	"github.com/boolean-maybe/tiki/doki"
+)
+
+// DokiSearch holds the documentation search state of a doki plugin view: full-text
+// search results or the backlinks of the page on display, and the page itself.
+// Thread-safe model that notifies listeners when results change.
+type DokiSearch struct {
+	mu         sync.RWMutex
+	pluginName string
+	page       string // file path of the page on display
+	revision   int    // bumped when the page is edited outside the view
+	query      string // search query, or the page path for backlinks
+	backlinks  bool
+	hits       []doki.Hit // nil = no active search
+	err        error
+
+	listeners    map[int]func()
+	nextListener int
+}
+
+// NewDokiSearch creates an empty doki search state
+func NewDokiSearch(name string) *DokiSearch {
+	return &DokiSearch{
+		pluginName:   name,
+		listeners:    make(map[int]func()),
+		nextListener: 1,
+	}
+}
+
+// GetPluginName returns the plugin name
+func (ds *DokiSearch) GetPluginName() string {
+	return ds.pluginName
+}
+
+// SetPage records the file path of the page the view displays
+func (ds *DokiSearch) SetPage(path string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.page = path
+}
+
+// GetPage returns the file path of the page the view displays
+func (ds *DokiSearch) GetPage() string {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.page
+}
+
+// PageEdited records that the page on display changed on disk, so views reload it
+func (ds *DokiSearch) PageEdited() {
+	ds.mu.Lock()
+	ds.revision++
+	ds.mu.Unlock()
+	ds.notifyListeners()
+}
+
+// GetPageRevision returns the number of edits made to pages from this view
+func (ds *DokiSearch) GetPageRevision() int {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.revision
+}
+
+// SetResults stores the hits for a query (an error replaces the hits)
+func (ds *DokiSearch) SetResults(query string, hits []doki.Hit, err error) {
+	ds.setHits(query, false, hits, err)
+}
+
+// SetBacklinks stores the pages linking to page
+func (ds *DokiSearch) SetBacklinks(page string, hits []doki.Hit, err error) {
+	ds.setHits(page, true, hits, err)
+}
+
+func (ds *DokiSearch) setHits(query string, backlinks bool, hits []doki.Hit, err error) {
+	ds.mu.Lock()
+	ds.query = query
+	ds.backlinks = backlinks
+	ds.hits = hits
+	if ds.hits == nil {
+		ds.hits = []doki.Hit{}
+	}
+	ds.err = err
+	ds.mu.Unlock()
+	ds.notifyListeners()
+}
+
+// Clear ends the active search
+func (ds *DokiSearch) Clear() {
+	ds.mu.Lock()
+	wasActive := ds.hits != nil
+	ds.query = ""
+	ds.backlinks = false
+	ds.hits = nil
+	ds.err = nil
+	ds.mu.Unlock()
+	if wasActive {
+		ds.notifyListeners()
+	}
+}
+
+// IsActive returns true if a search has results (possibly none) to show
+func (ds *DokiSearch) IsActive() bool {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.hits != nil
+}
+
+// GetQuery returns the current query (empty while showing backlinks)
+func (ds *DokiSearch) GetQuery() string {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	if ds.backlinks {
+		return ""
+	}
+	return ds.query
+}
+
+// IsBacklinks returns true if the results are backlinks rather than search hits
+func (ds *DokiSearch) IsBacklinks() bool {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.backlinks
+}
+
+// GetResults returns the hits and the error of the last search
+func (ds *DokiSearch) GetResults() ([]doki.Hit, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.hits, ds.err
+}
+
+// AddListener registers a callback for result changes and returns its ID
+func (ds *DokiSearch) AddListener(listener func()) int {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	id := ds.nextListener
+	ds.nextListener++
+	ds.listeners[id] = listener
+	return id
+}
+
+// RemoveListener removes a previously registered listener by ID
+func (ds *DokiSearch) RemoveListener(id int) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	delete(ds.listeners, id)
+}
+
+// notifyListeners calls all registered listeners
+func (ds *DokiSearch) notifyListeners() {
+	ds.mu.RLock()
+	listeners := make([]func(), 0, len(ds.listeners))
+	for _, listener := range ds.listeners {
+		listeners = append(listeners, listener)
+	}
+	ds.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
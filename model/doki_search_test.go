@@ -0,0 +1,73 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDokiSearchLifecycle(t *testing.T) {
+	ds := NewDokiSearch("Docs")
+	if ds.IsActive() {
+		t.Fatal("new search should be inactive")
+	}
+
+	notified := 0
+	ds.AddListener(func() { notified++ })
+
+	// a search without hits is still active so the view can say "no results"
+	ds.SetResults("missing", nil, nil)
+	if !ds.IsActive() || ds.GetQuery() != "missing" {
+		t.Fatalf("expected active search for 'missing', got active=%v query=%q", ds.IsActive(), ds.GetQuery())
+	}
+
+	ds.SetResults("broken", nil, errors.New("boom"))
+	if _, err := ds.GetResults(); err == nil {
+		t.Error("expected error to be kept")
+	}
+
+	ds.Clear()
+	ds.Clear() // no-op when inactive
+	if ds.IsActive() || ds.GetQuery() != "" {
+		t.Error("Clear should reset the search")
+	}
+	if notified != 3 {
+		t.Errorf("expected 3 notifications, got %d", notified)
+	}
+}
+
+func TestDokiSearchBacklinks(t *testing.T) {
+	ds := NewDokiSearch("Docs")
+	ds.SetPage("/docs/config.md")
+	ds.SetBacklinks(ds.GetPage(), nil, nil)
+
+	if !ds.IsActive() || !ds.IsBacklinks() {
+		t.Fatal("expected active backlinks results")
+	}
+	if ds.GetQuery() != "" {
+		t.Errorf("backlinks should not restore a search query, got %q", ds.GetQuery())
+	}
+
+	ds.SetResults("sprints", nil, nil)
+	if ds.IsBacklinks() || ds.GetQuery() != "sprints" {
+		t.Error("a search should replace backlinks")
+	}
+}
+
+func TestDokiSearchPageEdited(t *testing.T) {
+	ds := NewDokiSearch("Docs")
+	notified := 0
+	ds.AddListener(func() { notified++ })
+
+	ds.PageEdited()
+	ds.PageEdited()
+
+	if ds.GetPageRevision() != 2 {
+		t.Errorf("expected revision 2, got %d", ds.GetPageRevision())
+	}
+	if notified != 2 {
+		t.Errorf("expected 2 notifications, got %d", notified)
+	}
+	if ds.IsActive() {
+		t.Error("an edit should not start a search")
+	}
+}
@@ -8,6 +8,8 @@ const (
 	paramTaskID    = "taskID"
 	paramDraftTask = "draftTask"
 	paramFocus     = "focus"
+	paramPage      = "page"
+	paramAnchor    = "anchor"
 )
 
 // TaskDetailParams are params for TaskDetailViewID.
@@ -89,3 +91,38 @@ func DecodeTaskEditParams(params map[string]interface{}) TaskEditParams {
 	}
 	return p
 }
+
+// DokiParams are params for doki plugin views opened at a page other than their own.
+type DokiParams struct {
+	Page   string // markdown file path
+	Anchor string // heading slug to scroll to, if any
+}
+
+// EncodeDokiParams converts typed params into a navigation params map.
+func EncodeDokiParams(p DokiParams) map[string]interface{} {
+	if p.Page == "" {
+		return nil
+	}
+	m := map[string]interface{}{
+		paramPage: p.Page,
+	}
+	if p.Anchor != "" {
+		m[paramAnchor] = p.Anchor
+	}
+	return m
+}
+
+// DecodeDokiParams converts a navigation params map into typed params.
+func DecodeDokiParams(params map[string]interface{}) DokiParams {
+	var p DokiParams
+	if params == nil {
+		return p
+	}
+	if page, ok := params[paramPage].(string); ok {
+		p.Page = page
+	}
+	if anchor, ok := params[paramAnchor].(string); ok {
+		p.Anchor = anchor
+	}
+	return p
+}
@@ -388,3 +388,21 @@ func TestViewParams_ParamKeyConstants(t *testing.T) {
 		t.Error("TaskEditParams should use 'focus' key for Focus")
 	}
 }
+
+func TestDokiParams_EncodeDecodeRoundTrip(t *testing.T) {
+	tests := []DokiParams{
+		{Page: "/repo/.doc/doki/index.md"},
+		{Page: "/repo/.doc/doki/doc/config.md", Anchor: "sprints"},
+	}
+
+	for _, params := range tests {
+		decoded := DecodeDokiParams(EncodeDokiParams(params))
+		if decoded != params {
+			t.Errorf("round-trip failed: got %+v, want %+v", decoded, params)
+		}
+	}
+
+	if EncodeDokiParams(DokiParams{}) != nil {
+		t.Error("empty page should encode to nil params")
+	}
+}
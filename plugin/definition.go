@@ -26,7 +26,7 @@ type BasePlugin struct {
 	Background  tcell.Color   // caption background color
 	FilePath    string        // source file path (for error messages)
 	ConfigIndex int           // index in workflow.yaml views array (-1 if not from a config file)
-	Type        string        // plugin type: "tiki" or "doki"
+	Type        string        // plugin type: "tiki", "doki" or "chart"
 	Default     bool          // true if this view should open on startup
 }
 
@@ -66,9 +66,17 @@ type TikiPlugin struct {
 // DokiPlugin is a documentation-based plugin
 type DokiPlugin struct {
 	BasePlugin
-	Fetcher string // "file" or "internal"
+	Fetcher string // "file", "git", "dir", "internal" or "metrics"
 	Text    string // content text (for internal)
-	URL     string // resource URL (for file)
+	URL     string // resource URL (for file and git), directory (for dir)
+	Ref     string // branch, tag or commit to read docs from (for git)
+}
+
+// ChartPlugin is an analytics plugin that renders task history as a chart
+type ChartPlugin struct {
+	BasePlugin
+	Chart  string // chart kind: "cfd", "burndown" or "velocity"
+	Window int    // initial time window in days (0 = chart default); cfd only
 }
 
 // PluginActionConfig represents a shortcut action in YAML or config definitions.
@@ -87,16 +95,18 @@ type PluginAction struct {
 
 // PluginLaneConfig represents a lane in YAML or config definitions.
 type PluginLaneConfig struct {
-	Name    string `yaml:"name" mapstructure:"name"`
-	Columns int    `yaml:"columns" mapstructure:"columns"`
-	Filter  string `yaml:"filter" mapstructure:"filter"`
-	Action  string `yaml:"action" mapstructure:"action"`
+	Name     string `yaml:"name" mapstructure:"name"`
+	Columns  int    `yaml:"columns" mapstructure:"columns"`
+	Filter   string `yaml:"filter" mapstructure:"filter"`
+	Action   string `yaml:"action" mapstructure:"action"`
+	Template string `yaml:"template" mapstructure:"template"`
 }
 
 // TikiLane represents a parsed lane definition.
 type TikiLane struct {
-	Name    string
-	Columns int
-	Filter  filter.FilterExpr
-	Action  LaneAction
+	Name     string
+	Columns  int
+	Filter   filter.FilterExpr
+	Action   LaneAction
+	Template string // named task template for tikis created in this lane
 }
@@ -0,0 +1,28 @@
+package plugin
+
+import (
+	"time"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// LaneTasks returns the tasks shown in a lane: those matching the lane filter,
+// ordered by the plugin's sort rules (input order when there are none).
+func (p *TikiPlugin) LaneTasks(lane int, tasks []*task.Task, now time.Time, currentUser string) []*task.Task {
+	if lane < 0 || lane >= len(p.Lanes) {
+		return nil
+	}
+
+	laneFilter := p.Lanes[lane].Filter
+	var filtered []*task.Task
+	for _, t := range tasks {
+		if laneFilter == nil || laneFilter.Evaluate(t, now, currentUser) {
+			filtered = append(filtered, t)
+		}
+	}
+
+	if len(p.Sort) > 0 {
+		SortTasks(filtered, p.Sort)
+	}
+	return filtered
+}
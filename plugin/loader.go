@@ -58,6 +58,8 @@ func loadPluginsFromFile(path string) ([]Plugin, []string) {
 			tp.ConfigIndex = i
 		} else if dp, ok := p.(*DokiPlugin); ok {
 			dp.ConfigIndex = i
+		} else if cp, ok := p.(*ChartPlugin); ok {
+			cp.ConfigIndex = i
 		}
 
 		plugins = append(plugins, p)
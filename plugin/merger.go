@@ -13,10 +13,13 @@ type pluginFileConfig struct {
 	Filter     string               `yaml:"filter"`
 	Sort       string               `yaml:"sort"`
 	View       string               `yaml:"view"` // "compact" or "expanded" (default: compact)
-	Type       string               `yaml:"type"` // "tiki" or "doki" (default: tiki)
+	Type       string               `yaml:"type"` // "tiki", "doki" or "chart" (default: tiki)
 	Fetcher    string               `yaml:"fetcher"`
 	Text       string               `yaml:"text"`
 	URL        string               `yaml:"url"`
+	Ref        string               `yaml:"ref"` // git branch, tag or commit (git fetcher)
+	Chart      string               `yaml:"chart"`
+	Window     int                  `yaml:"window"` // chart time window in days
 	Lanes      []PluginLaneConfig   `yaml:"lanes"`
 	Actions    []PluginActionConfig `yaml:"actions"`
 	Default    bool                 `yaml:"default"`
@@ -2,12 +2,14 @@ package plugin
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 	"gopkg.in/yaml.v3"
 
+	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/plugin/filter"
 )
 
@@ -63,15 +65,31 @@ func parsePluginConfig(cfg pluginFileConfig, source string) (Plugin, error) {
 		if len(cfg.Actions) > 0 {
 			return nil, fmt.Errorf("doki plugin cannot have 'actions'")
 		}
+		if cfg.Chart != "" {
+			return nil, fmt.Errorf("doki plugin cannot have 'chart'")
+		}
 
-		if cfg.Fetcher != "file" && cfg.Fetcher != "internal" {
-			return nil, fmt.Errorf("doki plugin fetcher must be 'file' or 'internal', got '%s'", cfg.Fetcher)
+		switch cfg.Fetcher {
+		case "file", "git", "dir":
+			if cfg.URL == "" {
+				return nil, fmt.Errorf("doki plugin with %s fetcher requires 'url'", cfg.Fetcher)
+			}
+		case "internal":
+			if cfg.Text == "" {
+				return nil, fmt.Errorf("doki plugin with internal fetcher requires 'text'")
+			}
+		case "metrics":
+		default:
+			return nil, fmt.Errorf("doki plugin fetcher must be 'file', 'git', 'dir', 'internal' or 'metrics', got '%s'", cfg.Fetcher)
 		}
-		if cfg.Fetcher == "file" && cfg.URL == "" {
-			return nil, fmt.Errorf("doki plugin with file fetcher requires 'url'")
+		if cfg.Fetcher == "git" && cfg.Ref == "" {
+			return nil, fmt.Errorf("doki plugin with git fetcher requires 'ref'")
 		}
-		if cfg.Fetcher == "internal" && cfg.Text == "" {
-			return nil, fmt.Errorf("doki plugin with internal fetcher requires 'text'")
+		if strings.HasPrefix(cfg.Ref, "-") {
+			return nil, fmt.Errorf("doki plugin 'ref' can't start with '-', got '%s'", cfg.Ref)
+		}
+		if cfg.Fetcher != "git" && cfg.Ref != "" {
+			return nil, fmt.Errorf("doki plugin can only have 'ref' with git fetcher")
 		}
 
 		return &DokiPlugin{
@@ -79,6 +97,7 @@ func parsePluginConfig(cfg pluginFileConfig, source string) (Plugin, error) {
 			Fetcher:    cfg.Fetcher,
 			Text:       cfg.Text,
 			URL:        cfg.URL,
+			Ref:        cfg.Ref,
 		}, nil
 
 	case "tiki":
@@ -92,6 +111,12 @@ func parsePluginConfig(cfg pluginFileConfig, source string) (Plugin, error) {
 		if cfg.URL != "" {
 			return nil, fmt.Errorf("tiki plugin cannot have 'url'")
 		}
+		if cfg.Ref != "" {
+			return nil, fmt.Errorf("tiki plugin cannot have 'ref'")
+		}
+		if cfg.Chart != "" {
+			return nil, fmt.Errorf("tiki plugin cannot have 'chart'")
+		}
 		if cfg.Filter != "" {
 			return nil, fmt.Errorf("tiki plugin cannot have 'filter'")
 		}
@@ -122,11 +147,17 @@ func parsePluginConfig(cfg pluginFileConfig, source string) (Plugin, error) {
 			if err != nil {
 				return nil, fmt.Errorf("parsing action for lane %q: %w", lane.Name, err)
 			}
+			if lane.Template != "" {
+				if _, err := config.FindTaskTemplate(lane.Template); err != nil {
+					return nil, fmt.Errorf("lane %q: %w", lane.Name, err)
+				}
+			}
 			lanes = append(lanes, TikiLane{
-				Name:    lane.Name,
-				Columns: columns,
-				Filter:  filterExpr,
-				Action:  action,
+				Name:     lane.Name,
+				Columns:  columns,
+				Filter:   filterExpr,
+				Action:   action,
+				Template: lane.Template,
 			})
 		}
 
@@ -150,6 +181,40 @@ func parsePluginConfig(cfg pluginFileConfig, source string) (Plugin, error) {
 			Actions:    actions,
 		}, nil
 
+	case "chart":
+		// Strict validation for Chart
+		if cfg.Fetcher != "" || cfg.Text != "" || cfg.URL != "" || cfg.Ref != "" {
+			return nil, fmt.Errorf("chart plugin cannot have 'fetcher', 'text', 'url' or 'ref'")
+		}
+		if cfg.Filter != "" || cfg.Sort != "" || cfg.View != "" {
+			return nil, fmt.Errorf("chart plugin cannot have 'filter', 'sort' or 'view'")
+		}
+		if len(cfg.Lanes) > 0 {
+			return nil, fmt.Errorf("chart plugin cannot have 'lanes'")
+		}
+		if len(cfg.Actions) > 0 {
+			return nil, fmt.Errorf("chart plugin cannot have 'actions'")
+		}
+		switch cfg.Chart {
+		case "cfd":
+			if cfg.Window < 0 {
+				return nil, fmt.Errorf("chart plugin has invalid window %d", cfg.Window)
+			}
+		case "burndown", "velocity":
+			// these charts span the sprints from config rather than a window
+			if cfg.Window != 0 {
+				return nil, fmt.Errorf("%s chart cannot have 'window'", cfg.Chart)
+			}
+		default:
+			return nil, fmt.Errorf("chart plugin chart must be 'cfd', 'burndown' or 'velocity', got '%s'", cfg.Chart)
+		}
+
+		return &ChartPlugin{
+			BasePlugin: base,
+			Chart:      cfg.Chart,
+			Window:     cfg.Window,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown plugin type: %s", pluginType)
 	}
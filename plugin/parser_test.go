@@ -1,8 +1,12 @@
 package plugin
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/boolean-maybe/tiki/config"
 )
 
 func TestDokiValidation(t *testing.T) {
@@ -17,7 +21,7 @@ func TestDokiValidation(t *testing.T) {
 				Name: "Invalid Doki",
 				Type: "doki",
 			},
-			wantError: "doki plugin fetcher must be 'file' or 'internal'",
+			wantError: "doki plugin fetcher must be 'file', 'git', 'dir', 'internal' or 'metrics'",
 		},
 		{
 			name: "Invalid Fetcher",
@@ -26,7 +30,7 @@ func TestDokiValidation(t *testing.T) {
 				Type:    "doki",
 				Fetcher: "http",
 			},
-			wantError: "doki plugin fetcher must be 'file' or 'internal'",
+			wantError: "doki plugin fetcher must be 'file', 'git', 'dir', 'internal' or 'metrics'",
 		},
 		{
 			name: "File Fetcher Missing URL",
@@ -46,6 +50,47 @@ func TestDokiValidation(t *testing.T) {
 			},
 			wantError: "doki plugin with internal fetcher requires 'text'",
 		},
+		{
+			name: "Git Fetcher Missing Ref",
+			cfg: pluginFileConfig{
+				Name:    "Git No Ref",
+				Type:    "doki",
+				Fetcher: "git",
+				URL:     "index.md",
+			},
+			wantError: "doki plugin with git fetcher requires 'ref'",
+		},
+		{
+			name: "Git Fetcher Option Ref",
+			cfg: pluginFileConfig{
+				Name:    "Git Option Ref",
+				Type:    "doki",
+				Fetcher: "git",
+				URL:     "index.md",
+				Ref:     "--output=/tmp/x",
+			},
+			wantError: "doki plugin 'ref' can't start with '-', got '--output=/tmp/x'",
+		},
+		{
+			name: "Dir Fetcher Missing URL",
+			cfg: pluginFileConfig{
+				Name:    "Dir No URL",
+				Type:    "doki",
+				Fetcher: "dir",
+			},
+			wantError: "doki plugin with dir fetcher requires 'url'",
+		},
+		{
+			name: "Ref Without Git Fetcher",
+			cfg: pluginFileConfig{
+				Name:    "File With Ref",
+				Type:    "doki",
+				Fetcher: "file",
+				URL:     "index.md",
+				Ref:     "main",
+			},
+			wantError: "doki plugin can only have 'ref' with git fetcher",
+		},
 		{
 			name: "Doki with Tiki fields",
 			cfg: pluginFileConfig{
@@ -77,6 +122,15 @@ func TestDokiValidation(t *testing.T) {
 			},
 			wantError: "",
 		},
+		{
+			name: "Valid Metrics Fetcher",
+			cfg: pluginFileConfig{
+				Name:    "Valid Metrics",
+				Type:    "doki",
+				Fetcher: "metrics",
+			},
+			wantError: "",
+		},
 	}
 
 	for _, tc := range tests {
@@ -97,6 +151,77 @@ func TestDokiValidation(t *testing.T) {
 	}
 }
 
+func TestChartValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       pluginFileConfig
+		wantError string
+	}{
+		{
+			name:      "Missing Chart",
+			cfg:       pluginFileConfig{Name: "No Chart", Type: "chart"},
+			wantError: "chart plugin chart must be 'cfd', 'burndown' or 'velocity'",
+		},
+		{
+			name:      "Chart with Lanes",
+			cfg:       pluginFileConfig{Name: "Lanes", Type: "chart", Chart: "cfd", Lanes: []PluginLaneConfig{{Name: "x"}}},
+			wantError: "chart plugin cannot have 'lanes'",
+		},
+		{
+			name:      "Negative Window",
+			cfg:       pluginFileConfig{Name: "Window", Type: "chart", Chart: "cfd", Window: -3},
+			wantError: "chart plugin has invalid window",
+		},
+		{
+			name:      "Doki with Chart",
+			cfg:       pluginFileConfig{Name: "Doki", Type: "doki", Fetcher: "internal", Text: "x", Chart: "cfd"},
+			wantError: "doki plugin cannot have 'chart'",
+		},
+		{
+			name:      "Burndown with Window",
+			cfg:       pluginFileConfig{Name: "Sprint", Type: "chart", Chart: "burndown", Window: 14},
+			wantError: "burndown chart cannot have 'window'",
+		},
+		{
+			name:      "Valid CFD",
+			cfg:       pluginFileConfig{Name: "Flow", Type: "chart", Chart: "cfd", Window: 90},
+			wantError: "",
+		},
+		{
+			name:      "Valid Burndown",
+			cfg:       pluginFileConfig{Name: "Sprint", Type: "chart", Chart: "burndown"},
+			wantError: "",
+		},
+		{
+			name:      "Valid Velocity",
+			cfg:       pluginFileConfig{Name: "Velocity", Type: "chart", Chart: "velocity"},
+			wantError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := parsePluginConfig(tc.cfg, "test")
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("Expected error containing '%s', got '%v'", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got '%v'", err)
+			}
+			chart, ok := p.(*ChartPlugin)
+			if !ok {
+				t.Fatalf("Expected *ChartPlugin, got %T", p)
+			}
+			if chart.Chart != tc.cfg.Chart || chart.Window != tc.cfg.Window {
+				t.Errorf("unexpected chart plugin: %+v", chart)
+			}
+		})
+	}
+}
+
 func TestTikiValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -281,12 +406,14 @@ lanes:
   - name: Todo
     columns: 4
     filter: status = 'ready'
+    template: bug
 sort: Priority
 view: expanded
 foreground: "#ff0000"
 background: "#0000ff"
 `)
 
+	withTemplates(t, "bug")
 	plugin, err := parsePluginYAML(validYAML, "test.yaml")
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -312,6 +439,46 @@ background: "#0000ff"
 	if tikiPlugin.Lanes[0].Columns != 4 {
 		t.Errorf("Expected lane columns 4, got %d", tikiPlugin.Lanes[0].Columns)
 	}
+
+	if tikiPlugin.Lanes[0].Template != "bug" {
+		t.Errorf("Expected lane template 'bug', got %q", tikiPlugin.Lanes[0].Template)
+	}
+}
+
+// withTemplates runs a test in a temporary project with the named task templates
+func withTemplates(t *testing.T, names ...string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	templates := filepath.Join(tmpDir, ".doc", "templates")
+	if err := os.MkdirAll(templates, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(templates, name+".md"), []byte("---\ntype: story\n---\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	t.Cleanup(config.ResetPathManager)
+}
+
+func TestParsePluginYAML_UnknownLaneTemplate(t *testing.T) {
+	withTemplates(t, "bug")
+	_, err := parsePluginYAML([]byte(`
+name: Test Plugin
+type: tiki
+lanes:
+  - name: Todo
+    filter: status = 'ready'
+    template: bgu
+`), "test.yaml")
+	if err == nil || !strings.Contains(err.Error(), `lane "Todo": unknown template "bgu" (available: bug)`) {
+		t.Errorf("expected an unknown template error, got %v", err)
+	}
 }
 
 func TestParsePluginActions_Valid(t *testing.T) {
@@ -514,3 +681,28 @@ foreground: "#00ff00"
 		t.Errorf("Expected URL, got %q", dokiPlugin.URL)
 	}
 }
+
+func TestParsePluginYAML_GitDoki(t *testing.T) {
+	validYAML := []byte(`
+name: Release Docs
+key: R
+type: doki
+fetcher: git
+url: index.md
+ref: release-1.2
+`)
+
+	plugin, err := parsePluginYAML(validYAML, "test.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	dokiPlugin, ok := plugin.(*DokiPlugin)
+	if !ok {
+		t.Fatalf("Expected DokiPlugin, got %T", plugin)
+	}
+
+	if dokiPlugin.Fetcher != "git" || dokiPlugin.URL != "index.md" || dokiPlugin.Ref != "release-1.2" {
+		t.Errorf("unexpected git doki plugin %+v", dokiPlugin)
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+)
+
+// store change listeners don't say what changed: the hub keeps a snapshot of the tasks
+// and diffs it on every notification, so that clients get one event per task
+
+// event is a change to one task, sent as an SSE message named after its type
+type event struct {
+	Type string    `json:"type"` // created, updated or deleted
+	ID   string    `json:"id"`
+	Task *taskJSON `json:"task,omitempty"` // the task after the change; none when deleted
+}
+
+// clientBuffer is how many events a client can fall behind before it is disconnected;
+// it reconnects and reads the tasks again
+const clientBuffer = 256
+
+// heartbeat keeps idle streams open through proxies
+const heartbeat = 30 * time.Second
+
+// hub turns store notifications into events for the open streams
+type hub struct {
+	store      store.Store
+	listenerID int
+
+	mu       sync.Mutex
+	snapshot map[string]taskJSON
+	clients  map[*client]struct{}
+	closed   bool
+}
+
+type client struct {
+	events chan event
+	once   sync.Once
+}
+
+func (c *client) stop() {
+	c.once.Do(func() { close(c.events) })
+}
+
+func newHub(s store.Store) *hub {
+	h := &hub{store: s, clients: make(map[*client]struct{})}
+	h.snapshot = h.take()
+	h.listenerID = s.AddListener(h.changed)
+	return h
+}
+
+func (h *hub) take() map[string]taskJSON {
+	tasks := h.store.GetAllTasks()
+	snapshot := make(map[string]taskJSON, len(tasks))
+	for _, t := range tasks {
+		snapshot[t.ID] = toJSON(t)
+	}
+	return snapshot
+}
+
+// changed runs on every store notification. The snapshot is taken under the lock, so
+// notifications that race each other are diffed in the order they read the store.
+func (h *hub) changed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	current := h.take()
+	events := diff(h.snapshot, current)
+	h.snapshot = current
+	for c := range h.clients {
+		for _, e := range events {
+			select {
+			case c.events <- e:
+			default:
+				delete(h.clients, c)
+				c.stop()
+			}
+		}
+	}
+}
+
+// diff lists the changes from one snapshot to the next, by task ID
+func diff(before, after map[string]taskJSON) []event {
+	var events []event
+	for id, t := range after {
+		old, existed := before[id]
+		switch {
+		case !existed:
+			events = append(events, event{Type: "created", ID: id, Task: &t})
+		case !reflect.DeepEqual(old, t):
+			events = append(events, event{Type: "updated", ID: id, Task: &t})
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			events = append(events, event{Type: "deleted", ID: id})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events
+}
+
+func (h *hub) subscribe() *client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	c := &client{events: make(chan event, clientBuffer)}
+	h.clients[c] = struct{}{}
+	return c
+}
+
+func (h *hub) unsubscribe(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+	c.stop()
+}
+
+func (h *hub) close() {
+	h.store.RemoveListener(h.listenerID)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for c := range h.clients {
+		delete(h.clients, c)
+		c.stop()
+	}
+}
+
+// serve streams events to one client until it disconnects
+func (h *hub) serve(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	c := h.subscribe()
+	if c == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer h.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-c.events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
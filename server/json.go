@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// taskJSON is a tiki as the API returns it
+type taskJSON struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Type        task.Type         `json:"type"`
+	Status      task.Status       `json:"status"`
+	Tags        []string          `json:"tags"`
+	Assignee    string            `json:"assignee,omitempty"`
+	Priority    int               `json:"priority"`
+	Points      int               `json:"points"`
+	ExternalID  string            `json:"external_id,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Comments    []commentJSON     `json:"comments,omitempty"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at,omitzero"`
+	UpdatedAt   time.Time         `json:"updated_at,omitzero"`
+}
+
+type commentJSON struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+func toJSON(t *task.Task) taskJSON {
+	out := taskJSON{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Type:        t.Type,
+		Status:      t.Status,
+		Tags:        slices.Clone(t.Tags),
+		Assignee:    t.Assignee,
+		Priority:    t.Priority,
+		Points:      t.Points,
+		ExternalID:  t.ExternalID,
+		Fields:      maps.Clone(t.Fields),
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	for _, c := range t.Comments {
+		out.Comments = append(out.Comments, commentJSON(c))
+	}
+	return out
+}
+
+// taskInput holds the fields a request sets; fields left out keep their value
+type taskInput struct {
+	Title       *string            `json:"title"`
+	Description *string            `json:"description"`
+	Type        *string            `json:"type"`
+	Status      *string            `json:"status"`
+	Tags        *[]string          `json:"tags"`
+	Assignee    *string            `json:"assignee"`
+	Priority    *jsonPriority      `json:"priority"`
+	Points      *int               `json:"points"`
+	Fields      *map[string]string `json:"fields"`
+	// Prompts answers the {{prompt "Field"}} placeholders of the template a tiki is created from
+	Prompts map[string]string `json:"prompts"`
+}
+
+// apply sets the fields given in the request on t
+func (in taskInput) apply(t *task.Task) error {
+	if in.Title != nil {
+		t.Title = strings.TrimSpace(*in.Title)
+	}
+	if in.Description != nil {
+		t.Description = *in.Description
+	}
+	if in.Type != nil {
+		taskType, ok := task.ParseType(*in.Type)
+		if !ok {
+			return fmt.Errorf("unknown type %q", *in.Type)
+		}
+		t.Type = taskType
+	}
+	if in.Status != nil {
+		status, ok := task.ParseStatus(*in.Status)
+		if !ok || *in.Status == "" {
+			return fmt.Errorf("unknown status %q", *in.Status)
+		}
+		t.Status = status
+	}
+	if in.Tags != nil {
+		t.Tags = *in.Tags
+	}
+	if in.Assignee != nil {
+		t.Assignee = *in.Assignee
+	}
+	if in.Priority != nil {
+		t.Priority = int(*in.Priority)
+	}
+	if in.Points != nil {
+		t.Points = *in.Points
+	}
+	if in.Fields != nil {
+		t.Fields = *in.Fields
+		if len(t.Fields) == 0 {
+			t.Fields = nil
+		}
+	}
+	return nil
+}
+
+// jsonPriority reads a priority given as a number (1 highest to 5) or a word ("high")
+type jsonPriority int
+
+func (p *jsonPriority) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*p = jsonPriority(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.New("priority: expected a number or a word such as \"high\"")
+	}
+	n = task.NormalizePriority(s)
+	if n < 0 {
+		return fmt.Errorf("priority: unknown priority %q", s)
+	}
+	*p = jsonPriority(n)
+	return nil
+}
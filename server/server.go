@@ -0,0 +1,310 @@
+// Package server exposes a task store over a local HTTP/JSON API, so that editor
+// extensions and dashboards share the store the TUI uses instead of parsing tiki files
+// themselves. Store changes stream to clients as Server-Sent Events.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin/filter"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// Server serves the API:
+//
+//	GET    /api/tasks                 list, ?filter= a lane filter expression, ?q= a title search
+//	POST   /api/tasks                 create, ?template= a named template
+//	GET    /api/tasks/{id}            get
+//	PATCH  /api/tasks/{id}            update the fields given
+//	DELETE /api/tasks/{id}            delete
+//	POST   /api/tasks/{id}/comments   comment
+//	POST   /api/reload                re-read the tikis from disk
+//	GET    /api/events                change notifications as Server-Sent Events
+//
+// Only requests to a loopback host or to addr are answered, and a browser Origin must
+// match the host, so that web pages can neither rebind a domain to the API nor post to
+// it. POST and PATCH must be application/json, which a page can't send without asking.
+type Server struct {
+	store  store.Store
+	addr   string
+	mux    *http.ServeMux
+	events *hub
+	now    func() time.Time
+}
+
+// New returns a server for s listening on addr. Close it to stop watching s for changes.
+func New(s store.Store, addr string) *Server {
+	srv := &Server{store: s, addr: addr, mux: http.NewServeMux(), now: time.Now}
+	srv.events = newHub(s)
+
+	srv.mux.HandleFunc("GET /api/tasks", srv.listTasks)
+	srv.mux.HandleFunc("POST /api/tasks", srv.createTask)
+	srv.mux.HandleFunc("GET /api/tasks/{id}", srv.getTask)
+	srv.mux.HandleFunc("PATCH /api/tasks/{id}", srv.updateTask)
+	srv.mux.HandleFunc("DELETE /api/tasks/{id}", srv.deleteTask)
+	srv.mux.HandleFunc("POST /api/tasks/{id}/comments", srv.addComment)
+	srv.mux.HandleFunc("POST /api/reload", srv.reload)
+	srv.mux.HandleFunc("GET /api/events", srv.events.serve)
+	return srv
+}
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !srv.allowedHost(r.Host) {
+		writeError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("origin %q not allowed", origin))
+			return
+		}
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, errors.New("content type must be application/json"))
+			return
+		}
+	}
+	srv.mux.ServeHTTP(w, r)
+}
+
+// allowedHost reports whether a request's Host names a loopback address or the
+// address the server listens on
+func (srv *Server) allowedHost(host string) bool {
+	if strings.EqualFold(host, srv.addr) {
+		return true
+	}
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	if strings.EqualFold(name, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(name)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Close stops change notifications and ends the open event streams
+func (srv *Server) Close() {
+	srv.events.close()
+}
+
+func (srv *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	tasks := srv.store.GetAllTasks()
+	if q := r.URL.Query().Get("q"); q != "" {
+		tasks = tasks[:0:0]
+		for _, result := range srv.store.Search(q, nil) {
+			tasks = append(tasks, result.Task)
+		}
+	}
+	if expr := r.URL.Query().Get("filter"); expr != "" {
+		f, err := filter.ParseFilter(expr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("filter: %w", err))
+			return
+		}
+		if f != nil {
+			now := srv.now()
+			currentUser, _, _ := srv.store.GetCurrentUser()
+			matched := tasks[:0:0]
+			for _, t := range tasks {
+				if f.Evaluate(t, now, currentUser) {
+					matched = append(matched, t)
+				}
+			}
+			tasks = matched
+		}
+	}
+
+	sorted := make([]*task.Task, len(tasks))
+	copy(sorted, tasks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	out := make([]taskJSON, 0, len(sorted))
+	for _, t := range sorted {
+		out = append(out, toJSON(t))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (srv *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	t, ok := srv.lookup(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toJSON(t))
+}
+
+func (srv *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var in taskInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if in.Title == nil || strings.TrimSpace(*in.Title) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+
+	t, err := srv.store.NewTaskFromTemplate(r.URL.Query().Get("template"), in.Prompts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := in.apply(t); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if errs := t.Validate(); errs.HasErrors() {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("validation failed: %s", errs.Error()))
+		return
+	}
+	if err := srv.store.CreateTask(t); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/tasks/"+t.ID)
+	writeJSON(w, http.StatusCreated, toJSON(t))
+}
+
+func (srv *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	current, ok := srv.lookup(w, r)
+	if !ok {
+		return
+	}
+	var in taskInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if in.Prompts != nil {
+		writeError(w, http.StatusBadRequest, errors.New("prompts only apply when creating a tiki"))
+		return
+	}
+
+	updated := current.Clone()
+	if err := in.apply(updated); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if errs := updated.Validate(); errs.HasErrors() {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("validation failed: %s", errs.Error()))
+		return
+	}
+	if err := srv.store.UpdateTask(updated); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toJSON(updated))
+}
+
+func (srv *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	t, ok := srv.lookup(w, r)
+	if !ok {
+		return
+	}
+	srv.store.DeleteTask(t.ID)
+	if srv.store.GetTask(t.ID) != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("%s could not be deleted", t.ID))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (srv *Server) addComment(w http.ResponseWriter, r *http.Request) {
+	t, ok := srv.lookup(w, r)
+	if !ok {
+		return
+	}
+	var in struct {
+		Author string `json:"author"`
+		Text   string `json:"text"`
+	}
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if strings.TrimSpace(in.Text) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("text is required"))
+		return
+	}
+	if in.Author == "" {
+		in.Author, _, _ = srv.store.GetCurrentUser()
+	}
+
+	comment := task.Comment{ID: commentID(), Author: in.Author, Text: in.Text, CreatedAt: srv.now()}
+	if !srv.store.AddComment(t.ID, comment) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no tiki %s", t.ID))
+		return
+	}
+	writeJSON(w, http.StatusCreated, commentJSON(comment))
+}
+
+func (srv *Server) reload(w http.ResponseWriter, r *http.Request) {
+	if err := srv.store.Reload(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookup finds the task named by the request path, answering 404 when there is none
+func (srv *Server) lookup(w http.ResponseWriter, r *http.Request) (*task.Task, bool) {
+	id := r.PathValue("id")
+	t := srv.store.GetTask(id)
+	if t == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no tiki %s", strings.ToUpper(id)))
+		return nil, false
+	}
+	return t, true
+}
+
+// readJSON decodes a request body, answering 400 for malformed JSON and unknown fields
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("failed to write response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeStoreError answers a failed save: 409 when the tiki changed on disk since it
+// was loaded, 500 otherwise
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrConflict) {
+		writeError(w, http.StatusConflict, fmt.Errorf("%w: reload and retry", err))
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func commentID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store/tikistore"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// newTestServer serves a TikiStore in a temporary project
+func newTestServer(t *testing.T) (*httptest.Server, *tikistore.TikiStore) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	t.Cleanup(config.ResetPathManager)
+
+	s, err := tikistore.NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+	api := New(s, "")
+	ts := httptest.NewServer(api)
+	t.Cleanup(func() {
+		api.Close()
+		ts.Close()
+	})
+	return ts, s
+}
+
+func do(t *testing.T, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method == http.MethodPost || method == http.MethodPatch {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestTaskAPI(t *testing.T) {
+	ts, s := newTestServer(t)
+	api := ts.URL + "/api/tasks"
+
+	status, body := do(t, http.MethodPost, api, `{"title": "Fix login", "type": "bug", "priority": "high", "tags": ["auth"]}`)
+	if status != http.StatusCreated {
+		t.Fatalf("create: %d %s", status, body)
+	}
+	var created taskJSON
+	if err := json.Unmarshal([]byte(body), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Type != task.TypeBug || created.Priority != 1 {
+		t.Errorf("created = %+v", created)
+	}
+	if s.GetTask(created.ID) == nil {
+		t.Fatal("created tiki not in the store")
+	}
+	if status, body := do(t, http.MethodPost, api, `{"title": "Docs", "status": "ready"}`); status != http.StatusCreated {
+		t.Fatalf("create: %d %s", status, body)
+	}
+
+	status, body = do(t, http.MethodGet, api+"?filter="+strings.ReplaceAll("type = 'bug'", " ", "%20"), "")
+	var listed []taskJSON
+	if err := json.Unmarshal([]byte(body), &listed); err != nil || status != http.StatusOK {
+		t.Fatalf("list: %d %s", status, body)
+	}
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Errorf("filtered list = %+v", listed)
+	}
+
+	status, body = do(t, http.MethodPatch, api+"/"+strings.ToLower(created.ID), `{"status": "in progress", "assignee": "alice"}`)
+	if status != http.StatusOK {
+		t.Fatalf("update: %d %s", status, body)
+	}
+	got := s.GetTask(created.ID)
+	if got.Status != task.StatusInProgress || got.Assignee != "alice" || got.Title != "Fix login" {
+		t.Errorf("updated tiki = %+v", got)
+	}
+
+	status, body = do(t, http.MethodPost, api+"/"+created.ID+"/comments", `{"author": "bob", "text": "on it"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("comment: %d %s", status, body)
+	}
+	if status, body = do(t, http.MethodGet, api+"/"+created.ID, ""); status != http.StatusOK || !strings.Contains(body, `"text":"on it"`) {
+		t.Errorf("get: %d %s", status, body)
+	}
+
+	if status, _ := do(t, http.MethodDelete, api+"/"+created.ID, ""); status != http.StatusNoContent {
+		t.Errorf("delete: %d", status)
+	}
+	if s.GetTask(created.ID) != nil {
+		t.Error("deleted tiki still in the store")
+	}
+	if status, _ := do(t, http.MethodGet, api+"/"+created.ID, ""); status != http.StatusNotFound {
+		t.Errorf("get deleted: %d, want 404", status)
+	}
+}
+
+func TestTaskAPIErrors(t *testing.T) {
+	ts, _ := newTestServer(t)
+	api := ts.URL + "/api/tasks"
+
+	status, body := do(t, http.MethodPost, api, `{"title": "Existing"}`)
+	var created taskJSON
+	if err := json.Unmarshal([]byte(body), &created); err != nil || status != http.StatusCreated {
+		t.Fatalf("create: %d %s", status, body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+	}{
+		{"no title", http.MethodPost, api, `{"type": "bug"}`},
+		{"unknown type", http.MethodPost, api, `{"title": "x", "type": "chore"}`},
+		{"unknown field", http.MethodPost, api, `{"title": "x", "colour": "red"}`},
+		{"invalid priority", http.MethodPatch, api + "/" + created.ID, `{"priority": 9}`},
+		{"unknown status", http.MethodPatch, api + "/" + created.ID, `{"status": "blocked"}`},
+		{"malformed JSON", http.MethodPatch, api + "/" + created.ID, `{"status": `},
+		{"empty comment", http.MethodPost, api + "/" + created.ID + "/comments", `{"text": " "}`},
+		{"bad filter", http.MethodGet, api + "?filter=status%20%3D%3D%3D", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, tt.method, tt.url, tt.body)
+			if status != http.StatusBadRequest || !strings.Contains(body, `"error"`) {
+				t.Errorf("got %d %s, want 400 with an error", status, body)
+			}
+		})
+	}
+
+	if status, _ := do(t, http.MethodPatch, api+"/TIKI-NOPE00", `{"title": "x"}`); status != http.StatusNotFound {
+		t.Errorf("update missing: %d, want 404", status)
+	}
+}
+
+func TestCrossSiteRequests(t *testing.T) {
+	ts, s := newTestServer(t)
+	api := ts.URL + "/api/tasks"
+
+	tests := []struct {
+		name        string
+		method      string
+		host        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{"form post", http.MethodPost, "", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"post without content type", http.MethodPost, "", "", "", http.StatusUnsupportedMediaType},
+		{"rebound host", http.MethodGet, "evil.example:7474", "", "", http.StatusForbidden},
+		{"foreign origin", http.MethodPost, "", "http://evil.example", "application/json", http.StatusForbidden},
+		{"foreign origin read", http.MethodGet, "", "http://evil.example", "", http.StatusForbidden},
+		{"same origin", http.MethodPost, "", ts.URL, "application/json; charset=utf-8", http.StatusCreated},
+		{"localhost", http.MethodGet, "localhost:7474", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, api, strings.NewReader(`{"title": "Planted"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("got %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	if got := len(s.GetAllTasks()); got != 1 {
+		t.Errorf("%d tikis created, want only the same-origin one", got)
+	}
+}
+
+func TestAllowedHost(t *testing.T) {
+	srv := &Server{addr: "tiki.internal:7474"}
+	for host, want := range map[string]bool{
+		"127.0.0.1:7474":     true,
+		"[::1]:7474":         true,
+		"localhost":          true,
+		"LOCALHOST:7474":     true,
+		"tiki.internal:7474": true,
+		"tiki.internal:80":   false,
+		"192.168.1.5:7474":   false,
+		"evil.example":       false,
+	} {
+		if got := srv.allowedHost(host); got != want {
+			t.Errorf("allowedHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	ts, s := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	next := func() string {
+		t.Helper()
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("stream closed")
+				}
+				if strings.HasPrefix(line, "event: ") {
+					return strings.TrimPrefix(line, "event: ") + " " + strings.TrimPrefix(<-lines, "data: ")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no event")
+			}
+		}
+	}
+
+	// changes made to the store directly, as the TUI would, are streamed too
+	tk := &task.Task{Title: "Streamed", Type: task.TypeStory, Status: task.StatusBacklog, Priority: 3}
+	if err := s.CreateTask(tk); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); !strings.HasPrefix(got, "created ") || !strings.Contains(got, `"title":"Streamed"`) {
+		t.Errorf("event = %s", got)
+	}
+
+	updated := s.GetTask(tk.ID).Clone()
+	updated.Status = task.StatusDone
+	if err := s.UpdateTask(updated); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); !strings.HasPrefix(got, "updated ") || !strings.Contains(got, `"status":"done"`) {
+		t.Errorf("event = %s", got)
+	}
+
+	s.DeleteTask(tk.ID)
+	if got := next(); got != `deleted {"type":"deleted","id":"`+tk.ID+`"}` {
+		t.Errorf("event = %s", got)
+	}
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBackend is the backend used when config.yaml doesn't name one
+const DefaultBackend = "tiki"
+
+// BackendOptions tell a backend where the project's tasks live
+type BackendOptions struct {
+	// Dirs are the task directories. The first is the project's task directory, where
+	// new tasks are created; the others are read along with it.
+	Dirs []string
+}
+
+// BackendFactory opens a store for a backend
+type BackendFactory func(opts BackendOptions) (Store, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]BackendFactory)
+)
+
+// RegisterBackend makes a storage backend available under name, the value store.backend
+// selects it with. Registering the same name twice panics.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if factory == nil {
+		panic("store: RegisterBackend factory is nil")
+	}
+	if _, dup := backends[name]; dup {
+		panic("store: RegisterBackend called twice for backend " + name)
+	}
+	backends[name] = factory
+}
+
+// Backends returns the names of the registered backends, sorted
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenBackend opens a store with the named backend; an empty name is DefaultBackend
+func OpenBackend(name string, opts BackendOptions) (Store, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultBackend
+	}
+	if len(opts.Dirs) == 0 {
+		return nil, fmt.Errorf("store backend %q: no task directory", name)
+	}
+
+	backendsMu.RLock()
+	factory, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown store backend %q (available: %s)", name, strings.Join(Backends(), ", "))
+	}
+
+	s, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("open %s store: %w", name, err)
+	}
+	return s, nil
+}
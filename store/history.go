@@ -13,15 +13,36 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-const burndownDays = 14
-const burndownHalfDays = burndownDays * 2 // 12-hour intervals (AM/PM)
+// DefaultBurndownDays is the rolling burndown window used when no sprint is configured.
+const DefaultBurndownDays = 14
+
+// burndownBuckets caps the number of burndown points so the chart fits the header;
+// the default window yields 12-hour intervals (AM/PM).
+const burndownBuckets = DefaultBurndownDays * 2
+
+// BurndownOptions configures the window, scope and unit of a burndown.
+type BurndownOptions struct {
+	// Start and End bound a sprint (End is exclusive). A zero Start selects the
+	// rolling window of the last DefaultBurndownDays days.
+	Start time.Time
+	End   time.Time
+	// Scope selects the tasks that count toward remaining work; nil counts all tasks.
+	Scope func(*task.Task) bool
+	// Points measures remaining work in story points instead of task count.
+	Points bool
+}
 
+// StatusChange records a status transition of a task. In a full history the first
+// change of each task has an empty From and marks the task's creation.
+// Points and Tags are the task's values in the commit that made the change.
 type StatusChange struct {
 	TaskID string
 	From   task.Status
 	To     task.Status
 	At     time.Time
 	Commit string
+	Points int
+	Tags   []string
 }
 
 type BurndownPoint struct {
@@ -29,19 +50,43 @@ type BurndownPoint struct {
 	Remaining int
 }
 
+// VersionSource supplies the versions of task files a history is built from: git for
+// markdown tasks, the revisions table for the sqlite store. Both return a map of file
+// paths to versions, and include the last version before since when includePrior is set.
+type VersionSource interface {
+	// AllFileVersionsSince returns the versions that changed the status
+	AllFileVersionsSince(dirPattern string, since time.Time, includePrior bool) (map[string][]git.FileVersion, error)
+	// AllFileChangesSince returns every version
+	AllFileChangesSince(dirPattern string, since time.Time, includePrior bool) (map[string][]git.FileVersion, error)
+}
+
 type TaskHistory struct {
-	gitOps       git.GitOps
-	taskDir      string
-	now          func() time.Time
-	windowStart  time.Time
-	transitions  map[string][]StatusChange
-	baseActive   int
-	activeDeltas []statusDelta
+	versions        VersionSource
+	taskDir         string
+	now             func() time.Time
+	options         BurndownOptions
+	windowStart     time.Time
+	windowEnd       time.Time
+	bucket          time.Duration
+	transitions     map[string][]StatusChange
+	baseRemaining   int
+	remainingDeltas []statusDelta
+	full            bool
+}
+
+type versionStatus struct {
+	when   time.Time
+	status task.Status
+	hash   string
+	weight int
+	points int
+	tags   []string
 }
 
 type statusEvent struct {
 	when   time.Time
 	status task.Status
+	weight int
 }
 
 type statusDelta struct {
@@ -49,30 +94,56 @@ type statusDelta struct {
 	delta int
 }
 
-func NewTaskHistory(taskDir string, gitOps git.GitOps) *TaskHistory {
+func NewTaskHistory(taskDir string, versions VersionSource) *TaskHistory {
 	return &TaskHistory{
-		gitOps:  gitOps,
-		taskDir: taskDir,
-		now:     time.Now,
+		versions: versions,
+		taskDir:  taskDir,
+		now:      time.Now,
 	}
 }
 
+// NewFullTaskHistory creates a task history that loads every commit instead of only the
+// burndown window, so transitions cover each task's whole lifetime starting at creation.
+func NewFullTaskHistory(taskDir string, versions VersionSource) *TaskHistory {
+	h := NewTaskHistory(taskDir, versions)
+	h.full = true
+	return h
+}
+
+// SetBurndownOptions replaces the burndown configuration; call before Build.
+func (h *TaskHistory) SetBurndownOptions(options BurndownOptions) {
+	h.options = options
+}
+
 func (h *TaskHistory) Build() error {
-	if h.gitOps == nil {
+	if h.versions == nil {
 		return fmt.Errorf("git operations are required")
 	}
 	if h.taskDir == "" {
 		return fmt.Errorf("task directory is required")
 	}
 
-	h.windowStart = dayStartUTC(h.now().UTC().AddDate(0, 0, -(burndownDays - 1)))
+	h.windowStart, h.windowEnd = h.burndownWindow()
+	h.bucket = burndownBucket(h.windowEnd.Sub(h.windowStart))
 	h.transitions = make(map[string][]StatusChange)
-	h.activeDeltas = nil
-	h.baseActive = 0
+	h.remainingDeltas = nil
+	h.baseRemaining = 0
+
+	loadFrom := h.windowStart
+	includePrior := true
+	if h.full {
+		loadFrom = time.Unix(0, 0)
+		includePrior = false
+	}
 
-	// Use batched git operations to get all file versions at once
+	// Use batched git operations to get all file versions at once. Status changes are
+	// enough unless the weight of a task reads its other fields.
 	dirPattern := filepath.Join(h.taskDir, "*.md")
-	allVersions, err := h.gitOps.AllFileVersionsSince(dirPattern, h.windowStart, true)
+	loadVersions := h.versions.AllFileVersionsSince
+	if h.options.Points || h.options.Scope != nil {
+		loadVersions = h.versions.AllFileChangesSince
+	}
+	allVersions, err := loadVersions(dirPattern, loadFrom, includePrior)
 	if err != nil {
 		return fmt.Errorf("getting file versions: %w", err)
 	}
@@ -85,46 +156,52 @@ func (h *TaskHistory) Build() error {
 
 		taskID := deriveTaskID(filepath.Base(filePath))
 
-		// Parse status from each version
-		type versionStatus struct {
-			when   time.Time
-			status task.Status
-			hash   string
-		}
+		// versions are fetched concurrently, so restore commit order first
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].When.Before(versions[j].When)
+		})
 
+		// Parse each version and weigh it by the burndown scope and unit
 		var statuses []versionStatus
 		for _, version := range versions {
-			status, err := parseStatusFromContent(version.Content)
+			snapshot, err := parseVersionTask(version.Content)
 			if err != nil {
-				return fmt.Errorf("parsing status for %s at %s: %w", filePath, version.Hash, err)
+				return fmt.Errorf("parsing %s at %s: %w", filePath, version.Hash, err)
 			}
+			snapshot.ID = taskID
 			statuses = append(statuses, versionStatus{
 				when:   version.When,
-				status: status,
+				status: snapshot.Status,
 				hash:   version.Hash,
+				weight: h.remainingWeight(snapshot),
+				points: snapshot.Points,
+				tags:   snapshot.Tags,
 			})
 		}
 
 		// Build events from statuses (same logic as before)
-		var baselineStatus task.Status
+		var baseline versionStatus
 		baselineSet := false
 		for _, s := range statuses {
 			if s.when.Before(h.windowStart) {
-				baselineStatus = s.status
+				baseline = s
 				baselineSet = true
 			}
 		}
 
 		var events []statusEvent
 		var lastStatus task.Status
+		lastWeight := 0
 		hasStatus := false
 
 		if baselineSet {
 			events = append(events, statusEvent{
 				when:   h.windowStart,
-				status: baselineStatus,
+				status: baseline.status,
+				weight: baseline.weight,
 			})
-			lastStatus = baselineStatus
+			lastStatus = baseline.status
+			lastWeight = baseline.weight
 			hasStatus = true
 		}
 
@@ -134,53 +211,82 @@ func (h *TaskHistory) Build() error {
 			}
 
 			if !hasStatus {
-				events = append(events, statusEvent{when: s.when, status: s.status})
+				events = append(events, statusEvent{when: s.when, status: s.status, weight: s.weight})
 				lastStatus = s.status
+				lastWeight = s.weight
 				hasStatus = true
 				continue
 			}
 
 			if s.status == lastStatus {
+				// scope or points may change without a status transition
+				if s.weight != lastWeight {
+					events = append(events, statusEvent{when: s.when, status: s.status, weight: s.weight})
+					lastWeight = s.weight
+				}
 				continue
 			}
 
-			h.transitions[taskID] = append(h.transitions[taskID], StatusChange{
-				TaskID: taskID,
-				From:   lastStatus,
-				To:     s.status,
-				At:     s.when,
-				Commit: s.hash,
-			})
-			events = append(events, statusEvent{when: s.when, status: s.status})
+			if !h.full {
+				h.transitions[taskID] = append(h.transitions[taskID], StatusChange{
+					TaskID: taskID,
+					From:   lastStatus,
+					To:     s.status,
+					At:     s.when,
+					Commit: s.hash,
+					Points: s.points,
+					Tags:   s.tags,
+				})
+			}
+			events = append(events, statusEvent{when: s.when, status: s.status, weight: s.weight})
 			lastStatus = s.status
+			lastWeight = s.weight
 		}
 
 		if len(events) > 0 {
 			h.recordEvents(events)
 		}
+
+		if h.full {
+			h.transitions[taskID] = lifetimeTransitions(taskID, statuses)
+		}
 	}
 
-	sort.SliceStable(h.activeDeltas, func(i, j int) bool {
-		return h.activeDeltas[i].when.Before(h.activeDeltas[j].when)
+	sort.SliceStable(h.remainingDeltas, func(i, j int) bool {
+		return h.remainingDeltas[i].when.Before(h.remainingDeltas[j].when)
 	})
 
 	return nil
 }
 
+// Transitions returns a copy of the recorded status changes keyed by task ID, oldest first.
+func (h *TaskHistory) Transitions() map[string][]StatusChange {
+	result := make(map[string][]StatusChange, len(h.transitions))
+	for taskID, changes := range h.transitions {
+		result[taskID] = append([]StatusChange(nil), changes...)
+	}
+	return result
+}
+
+// Burndown returns the remaining work at the start of each interval of the window.
+// Intervals that have not started yet are omitted, so an ongoing sprint ends at now.
 func (h *TaskHistory) Burndown() []BurndownPoint {
 	if h.windowStart.IsZero() {
 		return nil
 	}
 
-	points := make([]BurndownPoint, 0, burndownHalfDays)
-	current := h.baseActive
+	now := h.now()
+	points := make([]BurndownPoint, 0, burndownBuckets)
+	current := h.baseRemaining
 	eventIndex := 0
 
-	periodStart := h.windowStart
-	for i := 0; i < burndownHalfDays; i++ {
-		periodEnd := periodStart.Add(12 * time.Hour)
-		for eventIndex < len(h.activeDeltas) && !h.activeDeltas[eventIndex].when.After(periodEnd) {
-			current += h.activeDeltas[eventIndex].delta
+	for periodStart := h.windowStart; periodStart.Before(h.windowEnd); periodStart = periodStart.Add(h.bucket) {
+		if periodStart.After(now) {
+			break
+		}
+		periodEnd := periodStart.Add(h.bucket)
+		for eventIndex < len(h.remainingDeltas) && !h.remainingDeltas[eventIndex].when.After(periodEnd) {
+			current += h.remainingDeltas[eventIndex].delta
 			eventIndex++
 		}
 
@@ -188,12 +294,47 @@ func (h *TaskHistory) Burndown() []BurndownPoint {
 			Date:      periodStart,
 			Remaining: current,
 		})
-		periodStart = periodEnd
 	}
 
 	return points
 }
 
+// burndownWindow resolves the configured sprint or the rolling default window.
+func (h *TaskHistory) burndownWindow() (time.Time, time.Time) {
+	if !h.options.Start.IsZero() && h.options.End.After(h.options.Start) {
+		return h.options.Start, h.options.End
+	}
+	start := dayStartUTC(h.now().UTC().AddDate(0, 0, -(DefaultBurndownDays - 1)))
+	return start, start.AddDate(0, 0, DefaultBurndownDays)
+}
+
+// burndownBucket picks the smallest multiple of 12 hours that fits the window
+// into burndownBuckets intervals.
+func burndownBucket(span time.Duration) time.Duration {
+	halfDay := 12 * time.Hour
+	halfDays := (span + halfDay - 1) / halfDay
+	perBucket := (halfDays + burndownBuckets - 1) / burndownBuckets
+	if perBucket < 1 {
+		perBucket = 1
+	}
+	return time.Duration(perBucket) * halfDay
+}
+
+// remainingWeight is how much a task version contributes to remaining work:
+// nothing unless it is active and in scope, otherwise its points or one task.
+func (h *TaskHistory) remainingWeight(t *task.Task) int {
+	if !isActiveStatus(t.Status) {
+		return 0
+	}
+	if h.options.Scope != nil && !h.options.Scope(t) {
+		return 0
+	}
+	if h.options.Points {
+		return t.Points
+	}
+	return 1
+}
+
 func (h *TaskHistory) recordEvents(events []statusEvent) {
 	if len(events) == 0 {
 		return
@@ -203,56 +344,99 @@ func (h *TaskHistory) recordEvents(events []statusEvent) {
 		return events[i].when.Before(events[j].when)
 	})
 
-	lastStatus := events[0].status
-	if events[0].when.Equal(h.windowStart) && isActiveStatus(lastStatus) {
-		h.baseActive++
-	} else if isActiveStatus(lastStatus) {
-		h.activeDeltas = append(h.activeDeltas, statusDelta{when: events[0].when, delta: 1})
+	lastWeight := events[0].weight
+	if events[0].when.Equal(h.windowStart) {
+		h.baseRemaining += lastWeight
+	} else if lastWeight != 0 {
+		h.remainingDeltas = append(h.remainingDeltas, statusDelta{when: events[0].when, delta: lastWeight})
 	}
 
 	for i := 1; i < len(events); i++ {
-		prevActive := isActiveStatus(lastStatus)
-		nextActive := isActiveStatus(events[i].status)
-		if prevActive == nextActive {
-			lastStatus = events[i].status
+		delta := events[i].weight - lastWeight
+		lastWeight = events[i].weight
+		if delta == 0 {
 			continue
 		}
-
-		delta := -1
-		if nextActive {
-			delta = 1
-		}
-		h.activeDeltas = append(h.activeDeltas, statusDelta{
+		h.remainingDeltas = append(h.remainingDeltas, statusDelta{
 			when:  events[i].when,
 			delta: delta,
 		})
-		lastStatus = events[i].status
 	}
 }
 
-func parseStatusFromContent(content string) (task.Status, error) {
+// lifetimeTransitions converts ordered version statuses into status changes,
+// starting with a creation entry for the first version.
+func lifetimeTransitions(taskID string, statuses []versionStatus) []StatusChange {
+	var changes []StatusChange
+	for i, s := range statuses {
+		if i > 0 && s.status == statuses[i-1].status {
+			continue
+		}
+		var from task.Status
+		if i > 0 {
+			from = statuses[i-1].status
+		}
+		changes = append(changes, StatusChange{
+			TaskID: taskID,
+			From:   from,
+			To:     s.status,
+			At:     s.when,
+			Commit: s.hash,
+			Points: s.points,
+			Tags:   s.tags,
+		})
+	}
+	return changes
+}
+
+// parseVersionTask reads the fields a burndown scope can filter on from one file version.
+// Parsing is lenient so that old revisions with since-changed formats still count.
+func parseVersionTask(content string) (*task.Task, error) {
 	frontmatter, _, err := ParseFrontmatter(content)
 	if err != nil {
-		return task.StatusBacklog, err
+		return nil, err
 	}
 
+	t := &task.Task{Status: task.StatusBacklog}
 	if frontmatter == "" {
-		return task.StatusBacklog, nil
+		return t, nil
 	}
 
 	var fm map[string]interface{}
 	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
-		return task.StatusBacklog, err
+		return nil, err
 	}
 
-	statusVal := task.StatusBacklog
-	if rawStatus, ok := fm["status"]; ok {
-		if s, ok := rawStatus.(string); ok && s != "" {
-			statusVal = task.MapStatus(s)
+	if s, ok := fm["status"].(string); ok && s != "" {
+		t.Status = task.MapStatus(s)
+	}
+	if s, ok := fm["title"].(string); ok {
+		t.Title = s
+	}
+	if s, ok := fm["type"].(string); ok {
+		t.Type = task.NormalizeType(s)
+	}
+	if s, ok := fm["assignee"].(string); ok {
+		t.Assignee = s
+	}
+	if n, ok := fm["points"].(int); ok {
+		t.Points = n
+	}
+	switch v := fm["priority"].(type) {
+	case int:
+		t.Priority = v
+	case string:
+		t.Priority = task.NormalizePriority(v)
+	}
+	if items, ok := fm["tags"].([]interface{}); ok {
+		for _, item := range items {
+			if tag := strings.TrimSpace(fmt.Sprint(item)); tag != "" {
+				t.Tags = append(t.Tags, tag)
+			}
 		}
 	}
 
-	return statusVal, nil
+	return t, nil
 }
 
 func isActiveStatus(status task.Status) bool {
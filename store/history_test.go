@@ -0,0 +1,299 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store/internal/git"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// fakeGitOps serves canned file versions for history tests.
+type fakeGitOps struct {
+	versions map[string][]git.FileVersion
+}
+
+func (f *fakeGitOps) Add(paths ...string) error    { return nil }
+func (f *fakeGitOps) Remove(paths ...string) error { return nil }
+func (f *fakeGitOps) CurrentUser() (string, string, error) {
+	return "tester", "tester@example.com", nil
+}
+func (f *fakeGitOps) Author(string) (*git.AuthorInfo, error) { return nil, nil }
+func (f *fakeGitOps) AllAuthors(string) (map[string]*git.AuthorInfo, error) {
+	return nil, nil
+}
+func (f *fakeGitOps) LastCommitTime(string) (time.Time, error) { return time.Time{}, nil }
+func (f *fakeGitOps) AllLastCommitTimes(string) (map[string]time.Time, error) {
+	return nil, nil
+}
+func (f *fakeGitOps) CurrentBranch() (string, error) { return "main", nil }
+func (f *fakeGitOps) FileVersionsSince(filePath string, _ time.Time, _ bool) ([]git.FileVersion, error) {
+	return f.versions[filePath], nil
+}
+
+// AllFileVersionsSince keeps the versions that change the status line, as git log -G^status: does
+func (f *fakeGitOps) AllFileVersionsSince(string, time.Time, bool) (map[string][]git.FileVersion, error) {
+	result := make(map[string][]git.FileVersion, len(f.versions))
+	for file, versions := range f.versions {
+		sorted := append([]git.FileVersion(nil), versions...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].When.Before(sorted[j].When) })
+		last := ""
+		for i, v := range sorted {
+			status := statusLine(v.Content)
+			if i == 0 || status != last {
+				result[file] = append(result[file], v)
+			}
+			last = status
+		}
+	}
+	return result, nil
+}
+func (f *fakeGitOps) AllFileChangesSince(string, time.Time, bool) (map[string][]git.FileVersion, error) {
+	return f.versions, nil
+}
+func (f *fakeGitOps) AllUsers() ([]string, error) { return nil, nil }
+func (f *fakeGitOps) FileAtRef(string, string) (string, error) {
+	return "", nil
+}
+
+func statusLine(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "status:") {
+			return line
+		}
+	}
+	return ""
+}
+
+func statusVersion(hash string, when time.Time, status string) git.FileVersion {
+	return git.FileVersion{
+		Hash:    hash,
+		When:    when,
+		Content: "---\ntitle: Task\nstatus: " + status + "\n---\n",
+	}
+}
+
+func TestFullTaskHistoryTransitions(t *testing.T) {
+	base := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	ops := &fakeGitOps{versions: map[string][]git.FileVersion{
+		// deliberately out of order: versions arrive from concurrent workers
+		"tasks/tiki-abc123.md": {
+			statusVersion("c3", base.Add(72*time.Hour), "done"),
+			statusVersion("c1", base, "backlog"),
+			statusVersion("c2", base.Add(24*time.Hour), "in_progress"),
+			statusVersion("c2b", base.Add(25*time.Hour), "in_progress"),
+		},
+	}}
+
+	history := NewFullTaskHistory("tasks", ops)
+	if err := history.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	changes := history.Transitions()["TIKI-ABC123"]
+	expected := []struct {
+		from task.Status
+		to   task.Status
+		at   time.Time
+	}{
+		{"", task.StatusBacklog, base},
+		{task.StatusBacklog, task.StatusInProgress, base.Add(24 * time.Hour)},
+		{task.StatusInProgress, task.StatusDone, base.Add(72 * time.Hour)},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d transitions, got %+v", len(expected), changes)
+	}
+	for i, want := range expected {
+		got := changes[i]
+		if got.From != want.from || got.To != want.to || !got.At.Equal(want.at) {
+			t.Errorf("transition %d: want %v→%v at %v, got %v→%v at %v",
+				i, want.from, want.to, want.at, got.From, got.To, got.At)
+		}
+	}
+}
+
+func pointsVersion(hash string, when time.Time, status string, points int, taskType string) git.FileVersion {
+	return git.FileVersion{
+		Hash: hash,
+		When: when,
+		Content: fmt.Sprintf("---\ntitle: Task\ntype: %s\nstatus: %s\npoints: %d\n---\n",
+			taskType, status, points),
+	}
+}
+
+func TestSprintBurndownPointsAndScope(t *testing.T) {
+	start := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+	ops := &fakeGitOps{versions: map[string][]git.FileVersion{
+		// active before the sprint, finished on day 2
+		"tasks/tiki-aaa111.md": {
+			pointsVersion("a1", start.Add(-48*time.Hour), "ready", 5, "story"),
+			pointsVersion("a2", start.Add(50*time.Hour), "done", 5, "story"),
+		},
+		// pulled in on day 1, re-estimated on day 4
+		"tasks/tiki-bbb222.md": {
+			pointsVersion("b1", start.Add(26*time.Hour), "in_progress", 3, "story"),
+			pointsVersion("b2", start.Add(98*time.Hour), "in_progress", 8, "story"),
+		},
+		// out of scope
+		"tasks/tiki-ccc333.md": {
+			pointsVersion("c1", start.Add(-48*time.Hour), "ready", 13, "bug"),
+		},
+	}}
+
+	history := NewTaskHistory("tasks", ops)
+	history.now = func() time.Time { return end.Add(24 * time.Hour) }
+	history.SetBurndownOptions(BurndownOptions{
+		Start:  start,
+		End:    end,
+		Scope:  func(t *task.Task) bool { return t.Type == task.TypeStory },
+		Points: true,
+	})
+	if err := history.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	points := history.Burndown()
+	if len(points) != 20 {
+		t.Fatalf("len(Burndown()) = %d, want 20 half-day points", len(points))
+	}
+	want := map[int]int{0: 5, 2: 8, 4: 3, 8: 8, 19: 8}
+	for i, remaining := range want {
+		if points[i].Remaining != remaining {
+			t.Errorf("points[%d].Remaining = %d, want %d", i, points[i].Remaining, remaining)
+		}
+	}
+}
+
+func TestBurndownStopsAtNowAndWidensBuckets(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ops := &fakeGitOps{versions: map[string][]git.FileVersion{
+		"tasks/tiki-aaa111.md": {statusVersion("a1", start.Add(-time.Hour), "ready")},
+	}}
+
+	history := NewTaskHistory("tasks", ops)
+	history.now = func() time.Time { return start.AddDate(0, 0, 14) }
+	history.SetBurndownOptions(BurndownOptions{Start: start, End: start.AddDate(0, 0, 28)})
+	if err := history.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	points := history.Burndown()
+	if len(points) != 15 {
+		t.Fatalf("len(Burndown()) = %d, want 15 daily points up to now", len(points))
+	}
+	if got := points[1].Date.Sub(points[0].Date); got != 24*time.Hour {
+		t.Errorf("bucket = %v, want 24h for a 28-day sprint", got)
+	}
+	if points[0].Remaining != 1 {
+		t.Errorf("points[0].Remaining = %d, want 1", points[0].Remaining)
+	}
+}
+
+func TestBurndownBucket(t *testing.T) {
+	tests := []struct {
+		days int
+		want time.Duration
+	}{
+		{days: 7, want: 12 * time.Hour},
+		{days: 14, want: 12 * time.Hour},
+		{days: 15, want: 24 * time.Hour},
+		{days: 28, want: 24 * time.Hour},
+		{days: 30, want: 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := burndownBucket(time.Duration(tt.days) * 24 * time.Hour); got != tt.want {
+			t.Errorf("burndownBucket(%d days) = %v, want %v", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestFullTaskHistoryCarriesPointsAndTags(t *testing.T) {
+	base := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	ops := &fakeGitOps{versions: map[string][]git.FileVersion{
+		"tasks/tiki-abc123.md": {
+			{Hash: "c1", When: base, Content: "---\ntitle: Task\nstatus: ready\npoints: 3\n---\n"},
+			{Hash: "c2", When: base.Add(48 * time.Hour), Content: "---\ntitle: Task\nstatus: done\npoints: 5\ntags: [sprint-1]\n---\n"},
+		},
+	}}
+
+	history := NewFullTaskHistory("tasks", ops)
+	if err := history.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	changes := history.Transitions()["TIKI-ABC123"]
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(changes))
+	}
+	done := changes[1]
+	if done.Points != 5 || len(done.Tags) != 1 || done.Tags[0] != "sprint-1" {
+		t.Errorf("done change = %d points, tags %v; want 5 points, [sprint-1]", done.Points, done.Tags)
+	}
+}
+
+// gitCommitFile writes a file in a git repository and commits it at the given time
+func gitCommitFile(t *testing.T, repo, name, content string, at time.Time) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	date := at.Format(time.RFC3339)
+	for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", "update " + name}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date,
+			"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com",
+			"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestSprintBurndownGitFieldChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	start := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+	// re-estimated on day 1, moved out of scope on day 3: neither commit touches the status
+	gitCommitFile(t, repo, "tiki-aaa111.md", "---\ntitle: Task\ntype: story\nstatus: ready\npoints: 3\n---\n", start.Add(-48*time.Hour))
+	gitCommitFile(t, repo, "tiki-aaa111.md", "---\ntitle: Task\ntype: story\nstatus: ready\npoints: 8\n---\n", start.Add(26*time.Hour))
+	gitCommitFile(t, repo, "tiki-aaa111.md", "---\ntitle: Task\ntype: bug\nstatus: ready\npoints: 8\n---\n", start.Add(74*time.Hour))
+
+	ops, err := git.NewGitOps(repo)
+	if err != nil {
+		t.Fatalf("NewGitOps: %v", err)
+	}
+	history := NewTaskHistory(repo, ops)
+	history.now = func() time.Time { return end.Add(24 * time.Hour) }
+	history.SetBurndownOptions(BurndownOptions{
+		Start:  start,
+		End:    end,
+		Scope:  func(t *task.Task) bool { return t.Type == task.TypeStory },
+		Points: true,
+	})
+	if err := history.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	points := history.Burndown()
+	want := map[int]int{0: 3, 1: 3, 2: 8, 5: 8, 6: 0, 19: 0}
+	for i, remaining := range want {
+		if points[i].Remaining != remaining {
+			t.Errorf("points[%d].Remaining = %d, want %d", i, points[i].Remaining, remaining)
+		}
+	}
+}
@@ -24,7 +24,9 @@ type GitOps interface {
 	CurrentBranch() (string, error)
 	FileVersionsSince(filePath string, since time.Time, includePrior bool) ([]FileVersion, error)
 	AllFileVersionsSince(dirPattern string, since time.Time, includePrior bool) (map[string][]FileVersion, error)
+	AllFileChangesSince(dirPattern string, since time.Time, includePrior bool) (map[string][]FileVersion, error)
 	AllUsers() ([]string, error)
+	FileAtRef(ref string, filePath string) (string, error)
 }
 
 // NewGitOps creates a new GitOps instance using the shell-out implementation by default
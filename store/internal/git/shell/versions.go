@@ -3,6 +3,7 @@ package shell
 import (
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -96,17 +97,52 @@ func (u *Util) buildFileVersion(logLine string, relPath string) (FileVersion, er
 	}, nil
 }
 
-// AllFileVersionsSince returns file versions for all files matching dirPattern since the given time.
+// FileAtRef returns the content of a file as of a branch, tag or commit.
+// A ref starting with "-" is rejected rather than passed to git as an option.
+func (u *Util) FileAtRef(ref string, filePath string) (string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid git ref %q", ref)
+	}
+	relPath, err := u.toRelative(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	showTarget := fmt.Sprintf("%s:%s", ref, filepath.ToSlash(relPath))
+	//nolint:gosec // G204: git command with validated ref and controlled file path
+	cmd := exec.Command("git", "show", "--end-of-options", showTarget)
+	cmd.Dir = u.repoPath
+	content, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", relPath, ref, err)
+	}
+	return string(content), nil
+}
+
+// AllFileVersionsSince returns file versions for all files matching dirPattern since the given time,
+// loading only the commits that changed the status field.
 // Returns a map of relative file paths to their version history.
 // If includePrior is true, includes the most recent commit before the time window for each file.
 func (u *Util) AllFileVersionsSince(dirPattern string, since time.Time, includePrior bool) (map[string][]FileVersion, error) {
+	return u.allFileVersionsSince(dirPattern, since, includePrior, []string{"-G^status:"})
+}
+
+// AllFileChangesSince is AllFileVersionsSince loading every commit that changed a file,
+// for histories that read fields other than status, such as points or tags
+func (u *Util) AllFileChangesSince(dirPattern string, since time.Time, includePrior bool) (map[string][]FileVersion, error) {
+	return u.allFileVersionsSince(dirPattern, since, includePrior, nil)
+}
+
+// allFileVersionsSince loads the versions of the commits git log selects with filter
+func (u *Util) allFileVersionsSince(dirPattern string, since time.Time, includePrior bool, filter []string) (map[string][]FileVersion, error) {
 	sinceStr := since.Format(time.RFC3339)
 	result := make(map[string][]FileVersion)
 
-	// Step 1: Get all commits that changed the status field in files matching the pattern
+	// Step 1: Get all commits that changed files matching the pattern, as filtered
+	logArgs := append([]string{"log", "--all", "--full-history"}, filter...)
 	//nolint:gosec // G204: git command with controlled directory pattern and timestamp
-	cmd := exec.Command("git", "log", "--all", "--full-history", "-G^status:",
-		"--format=%H|%an|%ae|%aI", "--name-only", "--since", sinceStr, "--", dirPattern)
+	cmd := exec.Command("git", append(logArgs,
+		"--format=%H|%an|%ae|%aI", "--name-only", "--since", sinceStr, "--", dirPattern)...)
 	cmd.Dir = u.repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -179,11 +215,11 @@ func (u *Util) AllFileVersionsSince(dirPattern string, since time.Time, includeP
 	}
 
 	// Step 4: If includePrior, get the most recent commit before the time window for each file
-	// Build a map of file -> most recent prior commit (only status changes)
+	// Build a map of file -> most recent prior commit (as filtered)
 	if includePrior {
 		//nolint:gosec // G204: git command with controlled directory pattern and timestamp
-		cmd := exec.Command("git", "log", "--all", "--full-history", "-G^status:",
-			"--format=%H|%an|%ae|%aI", "--name-only", "--before", sinceStr, "--", dirPattern)
+		cmd := exec.Command("git", append(logArgs,
+			"--format=%H|%an|%ae|%aI", "--name-only", "--before", sinceStr, "--", dirPattern)...)
 		cmd.Dir = u.repoPath
 		if output, err := cmd.Output(); err == nil {
 			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
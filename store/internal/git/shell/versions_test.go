@@ -0,0 +1,49 @@
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileAtRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	file := filepath.Join(repo, "index.md")
+	if err := os.WriteFile(file, []byte("# Released\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "index.md"},
+		{"-c", "user.name=tester", "-c", "user.email=tester@example.com", "commit", "-q", "-m", "release"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	u, err := NewUtil(repo)
+	if err != nil {
+		t.Fatalf("NewUtil: %v", err)
+	}
+	content, err := u.FileAtRef("v1", file)
+	if err != nil || content != "# Released\n" {
+		t.Fatalf("FileAtRef(v1) = %q, %v", content, err)
+	}
+
+	// a ref is never read as an option, which could make git write files
+	output := filepath.Join(t.TempDir(), "written")
+	if _, err := u.FileAtRef("--output="+output, file); err == nil {
+		t.Error("expected an error for a ref starting with -")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("git wrote %s", output)
+	}
+}
@@ -198,6 +198,16 @@ func (s *InMemoryStore) GetBurndown() []BurndownPoint {
 	return nil
 }
 
+// GetStatusTransitions returns nil for MemoryStore (no history tracking)
+func (s *InMemoryStore) GetStatusTransitions() (map[string][]StatusChange, error) {
+	return nil, nil
+}
+
+// GetTaskTimeline returns nil for MemoryStore (no history tracking)
+func (s *InMemoryStore) GetTaskTimeline(taskID string) ([]TaskRevision, error) {
+	return nil, nil
+}
+
 // GetAllUsers returns a placeholder user list for MemoryStore
 func (s *InMemoryStore) GetAllUsers() ([]string, error) {
 	return []string{"memory-user"}, nil
@@ -226,5 +236,15 @@ func (s *InMemoryStore) NewTaskTemplate() (*task.Task, error) {
 	return task, nil
 }
 
+// NewTaskFromTemplate returns the same defaults as NewTaskTemplate whatever the name.
+func (s *InMemoryStore) NewTaskFromTemplate(name string, values map[string]string) (*task.Task, error) {
+	return s.NewTaskTemplate()
+}
+
+// TemplatePrompts returns no fields: MemoryStore doesn't load templates from files.
+func (s *InMemoryStore) TemplatePrompts(name string) ([]string, error) {
+	return nil, nil
+}
+
 // ensure InMemoryStore implements Store
 var _ Store = (*InMemoryStore)(nil)
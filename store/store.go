@@ -1,9 +1,15 @@
 package store
 
 import (
+	"errors"
+
 	"github.com/boolean-maybe/tiki/task"
 )
 
+// ErrConflict indicates a task was modified elsewhere since it was loaded. Every backend
+// returns it, so callers can ask for a reload without knowing which one is in use.
+var ErrConflict = errors.New("task was modified externally")
+
 // Store is the interface for task storage engines.
 // Implementations must be thread-safe and notify listeners on changes.
 type Store interface {
@@ -55,12 +61,26 @@ type Store interface {
 	// GetBurndown returns the burndown chart data
 	GetBurndown() []BurndownPoint
 
+	// GetStatusTransitions returns every status change of every task over its whole history
+	GetStatusTransitions() (map[string][]StatusChange, error)
+
+	// GetTaskTimeline returns the committed revisions of a task, oldest first
+	GetTaskTimeline(taskID string) ([]TaskRevision, error)
+
 	// GetAllUsers returns list of all git users for assignee selection
 	GetAllUsers() ([]string, error)
 
 	// NewTaskTemplate returns a new task populated with template defaults from new.md.
 	// The task will have an auto-generated ID, git author, and all fields from the template.
 	NewTaskTemplate() (*task.Task, error)
+
+	// NewTaskFromTemplate is NewTaskTemplate with the named template from the templates
+	// directories; an empty name uses new.md. Placeholders such as {{.User}} are filled in
+	// and values answers the template's prompts. Fails when there is no such template.
+	NewTaskFromTemplate(name string, values map[string]string) (*task.Task, error)
+
+	// TemplatePrompts returns the fields the named template asks for on creation
+	TemplatePrompts(name string) ([]string, error)
 }
 
 // ChangeListener is called when the store's data changes
@@ -0,0 +1,46 @@
+package tikistore
+
+import (
+	"path/filepath"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+)
+
+// the backends selected by store.backend in config.yaml
+func init() {
+	store.RegisterBackend("tiki", func(opts store.BackendOptions) (store.Store, error) {
+		s, err := NewTikiStore(opts.Dirs[0])
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+	store.RegisterBackend("multidir", func(opts store.BackendOptions) (store.Store, error) {
+		s, err := NewMultiDirStore(opts.Dirs)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+	// one database next to the task directory, .doc/tiki.db
+	store.RegisterBackend("sqlite", func(opts store.BackendOptions) (store.Store, error) {
+		s, err := NewSQLiteStore(filepath.Join(filepath.Dir(opts.Dirs[0]), SQLiteDatabaseName))
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+	// the repositories of the user's workspace; the project's own directories don't matter
+	store.RegisterBackend("workspace", func(store.BackendOptions) (store.Store, error) {
+		repos, err := config.GetWorkspaceRepos()
+		if err != nil {
+			return nil, err
+		}
+		s, err := NewWorkspaceStore(repos)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+}
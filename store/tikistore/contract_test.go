@@ -0,0 +1,251 @@
+package tikistore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+// backendContract opens a backend over an empty project. changeElsewhere saves a new
+// title for a task the way another tiki process would, behind the store's back.
+type backendContract struct {
+	open            func(t *testing.T) store.Store
+	changeElsewhere func(t *testing.T, id, title string)
+}
+
+func backendContracts() map[string]backendContract {
+	// file backends see another process's save as a newer file
+	files := func(backend string) backendContract {
+		var taskDir string
+		return backendContract{
+			open: func(t *testing.T) store.Store {
+				taskDir, _ = newMultiDirProject(t, nil, nil)
+				s, err := store.OpenBackend(backend, store.BackendOptions{Dirs: []string{taskDir}})
+				if err != nil {
+					t.Fatalf("OpenBackend: %v", err)
+				}
+				return s
+			},
+			changeElsewhere: func(t *testing.T, id, title string) {
+				path := filepath.Join(taskDir, strings.ToLower(id)+".md")
+				if err := os.WriteFile(path, []byte(taskFile(title, "ready")), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				later := time.Now().Add(time.Minute)
+				if err := os.Chtimes(path, later, later); err != nil {
+					t.Fatal(err)
+				}
+			},
+		}
+	}
+
+	var dbPath string
+	return map[string]backendContract{
+		"tiki":     files("tiki"),
+		"multidir": files("multidir"),
+		"sqlite": {
+			open: func(t *testing.T) store.Store {
+				taskDir, _ := newMultiDirProject(t, nil, nil)
+				s, err := store.OpenBackend("sqlite", store.BackendOptions{Dirs: []string{taskDir}})
+				if err != nil {
+					t.Fatalf("OpenBackend: %v", err)
+				}
+				dbPath = s.(*SQLiteStore).Path()
+				t.Cleanup(func() { _ = s.(*SQLiteStore).Close() })
+				return s
+			},
+			changeElsewhere: func(t *testing.T, id, title string) {
+				other, err := NewSQLiteStore(dbPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer func() { _ = other.Close() }()
+				changed := other.GetTask(id).Clone()
+				changed.Title = title
+				if err := other.UpdateTask(changed); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+}
+
+// TestBackendContract runs every backend through the Store behavior the UI, the API and
+// the MCP server rely on: change notifications and conflict detection
+func TestBackendContract(t *testing.T) {
+	for name, backend := range backendContracts() {
+		t.Run(name, func(t *testing.T) {
+			s := backend.open(t)
+			notified := 0
+			listenerID := s.AddListener(func() { notified++ })
+			expectNotified := func(action string, want int) {
+				t.Helper()
+				if notified != want {
+					t.Errorf("after %s: %d notifications, want %d", action, notified, want)
+				}
+			}
+
+			created := &taskpkg.Task{Title: "Contract", Type: taskpkg.TypeStory, Status: taskpkg.StatusReady, Priority: 3}
+			if err := s.CreateTask(created); err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			expectNotified("create", 1)
+			id := created.ID
+			if !strings.HasPrefix(id, "TIKI-") || s.GetTask(strings.ToLower(id)) == nil {
+				t.Fatalf("created task %q not found", id)
+			}
+
+			updated := s.GetTask(id).Clone()
+			updated.Status = taskpkg.StatusInProgress
+			if err := s.UpdateTask(updated); err != nil {
+				t.Fatalf("UpdateTask: %v", err)
+			}
+			expectNotified("update", 2)
+			if got := s.GetTask(id).Status; got != taskpkg.StatusInProgress {
+				t.Errorf("status = %q after update", got)
+			}
+
+			if !s.AddComment(id, taskpkg.Comment{ID: "c1", Author: "bob", Text: "on it", CreatedAt: time.Now()}) {
+				t.Fatal("AddComment failed")
+			}
+			expectNotified("comment", 3)
+			if comments := s.GetTask(id).Comments; len(comments) != 1 || comments[0].Text != "on it" {
+				t.Errorf("comments = %+v", comments)
+			}
+			if s.AddComment("TIKI-NOPE00", taskpkg.Comment{Text: "lost"}) {
+				t.Error("AddComment to a missing task succeeded")
+			}
+
+			// a save based on a version another process replaced is refused
+			stale := s.GetTask(id).Clone()
+			stale.Title = "Stale edit"
+			backend.changeElsewhere(t, id, "Changed elsewhere")
+			if err := s.UpdateTask(stale); !errors.Is(err, store.ErrConflict) {
+				t.Fatalf("UpdateTask after a change elsewhere = %v, want ErrConflict", err)
+			}
+			expectNotified("conflict", 3)
+			if got := s.GetTask(id).Title; got != "Contract" {
+				t.Errorf("title = %q after a refused update", got)
+			}
+
+			if err := s.Reload(); err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+			expectNotified("reload", 4)
+			if got := s.GetTask(id).Title; got != "Changed elsewhere" {
+				t.Errorf("title = %q after reload, want the change made elsewhere", got)
+			}
+			fresh := s.GetTask(id).Clone()
+			fresh.Title = "Fresh edit"
+			if err := s.UpdateTask(fresh); err != nil {
+				t.Fatalf("UpdateTask after reload: %v", err)
+			}
+			expectNotified("update after reload", 5)
+			if results := s.Search("fresh", nil); len(results) != 1 || results[0].Task.ID != id {
+				t.Errorf("Search = %+v", results)
+			}
+
+			s.DeleteTask(id)
+			expectNotified("delete", 6)
+			if s.GetTask(id) != nil || len(s.GetAllTasks()) != 0 {
+				t.Error("deleted task still in the store")
+			}
+
+			s.RemoveListener(listenerID)
+			if err := s.CreateTask(&taskpkg.Task{Title: "Unheard", Type: taskpkg.TypeStory, Status: taskpkg.StatusReady, Priority: 3}); err != nil {
+				t.Fatal(err)
+			}
+			expectNotified("create after RemoveListener", 6)
+		})
+	}
+}
+
+func TestSQLiteStoreRevisions(t *testing.T) {
+	taskDir, _ := newMultiDirProject(t, nil, nil)
+	dbPath := filepath.Join(filepath.Dir(taskDir), SQLiteDatabaseName)
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	if s.GetBurndown() != nil {
+		t.Error("burndown before SetBurndownOptions should be nil")
+	}
+
+	tk := &taskpkg.Task{Title: "Tracked", Type: taskpkg.TypeBug, Status: taskpkg.StatusReady, Priority: 2, Points: 3}
+	if err := s.CreateTask(tk); err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range []func(*taskpkg.Task){
+		func(t *taskpkg.Task) { t.Points = 5 },
+		func(t *taskpkg.Task) { t.Status = taskpkg.StatusInProgress },
+	} {
+		next := s.GetTask(tk.ID).Clone()
+		change(next)
+		if err := s.UpdateTask(next); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !s.AddComment(tk.ID, taskpkg.Comment{ID: "c1", Author: "bob", Text: "kept", CreatedAt: time.Now()}) {
+		t.Fatal("AddComment failed")
+	}
+
+	timeline, err := s.GetTaskTimeline(tk.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeline) != 3 {
+		t.Fatalf("timeline has %d revisions, want 3", len(timeline))
+	}
+	if changes := timeline[1].Changes; len(changes) != 1 || changes[0].Field != "points" {
+		t.Errorf("second revision changes = %+v, want points only", changes)
+	}
+
+	transitions, err := s.GetStatusTransitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := transitions[tk.ID]
+	if len(got) != 2 || got[0].To != taskpkg.StatusReady || got[1].From != taskpkg.StatusReady || got[1].To != taskpkg.StatusInProgress {
+		t.Errorf("transitions = %+v, want created as ready then in progress", got)
+	}
+
+	s.SetBurndownOptions(store.BurndownOptions{Points: true})
+	burndown := s.GetBurndown()
+	if len(burndown) == 0 || burndown[len(burndown)-1].Remaining != 5 {
+		t.Errorf("burndown = %+v, want 5 points remaining today", burndown)
+	}
+
+	// everything survives reopening the database, comments included
+	reopened, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = reopened.Close() })
+	loaded := reopened.GetTask(tk.ID)
+	if loaded == nil || loaded.Points != 5 || loaded.Status != taskpkg.StatusInProgress || loaded.Title != "Tracked" {
+		t.Fatalf("reopened task = %+v", loaded)
+	}
+	if len(loaded.Comments) != 1 || loaded.Comments[0].Text != "kept" {
+		t.Errorf("reopened comments = %+v", loaded.Comments)
+	}
+
+	// a task edited as a tiki file is saved back
+	source, err := reopened.TaskSource(tk.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.SetTaskSource(tk.ID, strings.Replace(source, "Tracked", "Edited as a file", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.GetTask(tk.ID); got.Title != "Edited as a file" || len(got.Comments) != 1 {
+		t.Errorf("task after SetTaskSource = %+v", got)
+	}
+}
@@ -0,0 +1,344 @@
+package tikistore
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/store/internal/git"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+// maxIDAttempts bounds the retries for a new task ID that no directory uses yet
+const maxIDAttempts = 100
+
+// MultiDirStore aggregates the tasks of several directories, such as .doc/tiki and
+// .doc/archive, into one store. New tasks are created in the first directory; a task is
+// updated and deleted in the directory it was loaded from. When two directories hold the
+// same ID the earlier one wins.
+type MultiDirStore struct {
+	stores []*TikiStore
+
+	mu             sync.RWMutex
+	listeners      map[int]store.ChangeListener
+	nextListenerID int
+}
+
+// NewMultiDirStore creates a store over dirs; the first is where new tasks go
+func NewMultiDirStore(dirs []string) (*MultiDirStore, error) {
+	if len(dirs) == 0 {
+		return nil, errors.New("no task directories")
+	}
+	m := &MultiDirStore{
+		listeners:      make(map[int]store.ChangeListener),
+		nextListenerID: 1,
+	}
+	seen := make(map[string]string)
+	for _, dir := range dirs {
+		s, err := NewTikiStore(dir)
+		if err != nil {
+			return nil, fmt.Errorf("task directory %s: %w", dir, err)
+		}
+		for _, t := range s.GetAllTasks() {
+			if first, dup := seen[t.ID]; dup {
+				slog.Warn("task is in more than one directory, using the first", "task_id", t.ID, "used", first, "ignored", dir)
+				continue
+			}
+			seen[t.ID] = dir
+		}
+		// children notify after releasing their locks, so forwarding is safe
+		s.AddListener(m.notifyListeners)
+		m.stores = append(m.stores, s)
+	}
+	return m, nil
+}
+
+// Primary returns the store of the first directory, which holds new tasks and the git
+// state (user, branch) shared by all of them
+func (m *MultiDirStore) Primary() *TikiStore {
+	return m.stores[0]
+}
+
+// Stores returns the store of each directory, the first directory's first
+func (m *MultiDirStore) Stores() []*TikiStore {
+	return append([]*TikiStore(nil), m.stores...)
+}
+
+// AddListener registers a callback for change notifications in any directory.
+// returns a listener ID that can be used to remove the listener.
+func (m *MultiDirStore) AddListener(listener store.ChangeListener) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextListenerID
+	m.nextListenerID++
+	m.listeners[id] = listener
+	return id
+}
+
+// RemoveListener removes a previously registered listener by ID
+func (m *MultiDirStore) RemoveListener(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.listeners, id)
+}
+
+func (m *MultiDirStore) notifyListeners() {
+	m.mu.RLock()
+	listeners := make([]store.ChangeListener, 0, len(m.listeners))
+	for _, l := range m.listeners {
+		listeners = append(listeners, l)
+	}
+	m.mu.RUnlock()
+
+	for _, l := range listeners {
+		l()
+	}
+}
+
+// owner returns the store a task was loaded from, nil when no directory has it
+func (m *MultiDirStore) owner(id string) *TikiStore {
+	for _, s := range m.stores {
+		if s.GetTask(id) != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// idTaken reports whether any directory has a task or a file for the ID
+func (m *MultiDirStore) idTaken(id string) bool {
+	for _, s := range m.stores {
+		if s.GetTask(id) != nil {
+			return true
+		}
+		if _, err := os.Stat(s.taskFilePath(id)); !os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTask adds a new task to the first directory. A task whose ID another directory
+// already holds is rejected rather than shadowed.
+func (m *MultiDirStore) CreateTask(task *taskpkg.Task) error {
+	if task.ID == "" {
+		for attempt := 0; ; attempt++ {
+			if attempt == maxIDAttempts {
+				return errors.New("no free task ID found")
+			}
+			task.ID = fmt.Sprintf("TIKI-%s", config.GenerateRandomID())
+			if !m.idTaken(normalizeTaskID(task.ID)) {
+				break
+			}
+			slog.Debug("ID collision detected, regenerating", "id", task.ID)
+		}
+	}
+	if s := m.owner(task.ID); s != nil && s != m.Primary() {
+		return fmt.Errorf("task %s already exists in %s", normalizeTaskID(task.ID), s.dir)
+	}
+	return m.Primary().CreateTask(task)
+}
+
+// GetTask retrieves a task by ID
+func (m *MultiDirStore) GetTask(id string) *taskpkg.Task {
+	if s := m.owner(id); s != nil {
+		return s.GetTask(id)
+	}
+	return nil
+}
+
+// UpdateTask saves a task in the directory it was loaded from.
+// Returns ErrConflict when its file changed on disk since.
+func (m *MultiDirStore) UpdateTask(task *taskpkg.Task) error {
+	s := m.owner(task.ID)
+	if s == nil {
+		return fmt.Errorf("task not found: %s", normalizeTaskID(task.ID))
+	}
+	return s.UpdateTask(task)
+}
+
+// DeleteTask removes a task and its file from the directory it was loaded from
+func (m *MultiDirStore) DeleteTask(id string) {
+	if s := m.owner(id); s != nil {
+		s.DeleteTask(id)
+	}
+}
+
+// GetAllTasks returns the tasks of all directories, sorted by priority then title
+func (m *MultiDirStore) GetAllTasks() []*taskpkg.Task {
+	var tasks []*taskpkg.Task
+	seen := make(map[string]bool)
+	for _, s := range m.stores {
+		for _, t := range s.GetAllTasks() {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+// Search searches the tasks of all directories, see TikiStore.Search
+func (m *MultiDirStore) Search(query string, filterFunc func(*taskpkg.Task) bool) []taskpkg.SearchResult {
+	var results []taskpkg.SearchResult
+	seen := make(map[string]bool)
+	for _, s := range m.stores {
+		for _, r := range s.Search(query, filterFunc) {
+			if !seen[r.Task.ID] {
+				seen[r.Task.ID] = true
+				results = append(results, r)
+			}
+		}
+	}
+
+	tasks := make([]*taskpkg.Task, len(results))
+	scores := make(map[string]float64, len(results))
+	for i, r := range results {
+		tasks[i] = r.Task
+		scores[r.Task.ID] = r.Score
+	}
+	sortTasks(tasks)
+	for i, t := range tasks {
+		results[i] = taskpkg.SearchResult{Task: t, Score: scores[t.ID]}
+	}
+	return results
+}
+
+// AddComment adds a comment to a task
+func (m *MultiDirStore) AddComment(taskID string, comment taskpkg.Comment) bool {
+	s := m.owner(taskID)
+	return s != nil && s.AddComment(taskID, comment)
+}
+
+// Reload reloads the tasks of every directory
+func (m *MultiDirStore) Reload() error {
+	var errs []error
+	for _, s := range m.stores {
+		if err := s.Reload(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.dir, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadTask reloads a single task from the directory it is in
+func (m *MultiDirStore) ReloadTask(taskID string) error {
+	if s := m.owner(taskID); s != nil {
+		return s.ReloadTask(taskID)
+	}
+	// not loaded yet: it may have been added to any directory
+	for _, s := range m.stores {
+		if _, err := os.Stat(s.taskFilePath(normalizeTaskID(taskID))); err == nil {
+			return s.ReloadTask(taskID)
+		}
+	}
+	return fmt.Errorf("task not found: %s", normalizeTaskID(taskID))
+}
+
+// TaskFilePath returns the markdown file of a task in the directory it was loaded from,
+// or in the first directory for a new one
+func (m *MultiDirStore) TaskFilePath(id string) string {
+	if s := m.owner(id); s != nil {
+		return s.TaskFilePath(id)
+	}
+	return m.Primary().TaskFilePath(id)
+}
+
+// GetCurrentUser returns the current git user name and email
+func (m *MultiDirStore) GetCurrentUser() (name string, email string, err error) {
+	return m.Primary().GetCurrentUser()
+}
+
+// GetStats returns statistics for the header (user, branch)
+func (m *MultiDirStore) GetStats() []store.Stat {
+	return m.Primary().GetStats()
+}
+
+// GetBurndown returns the remaining work of all directories together
+func (m *MultiDirStore) GetBurndown() []store.BurndownPoint {
+	series := make([][]store.BurndownPoint, 0, len(m.stores))
+	for _, s := range m.stores {
+		series = append(series, s.GetBurndown())
+	}
+	return sumBurndowns(series)
+}
+
+// GetStatusTransitions returns the status history of the tasks of every directory
+func (m *MultiDirStore) GetStatusTransitions() (map[string][]store.StatusChange, error) {
+	transitions := make(map[string][]store.StatusChange)
+	for _, s := range m.stores {
+		dirTransitions, err := s.GetStatusTransitions()
+		if err != nil {
+			return nil, err
+		}
+		for id, changes := range dirTransitions {
+			if _, dup := transitions[id]; !dup {
+				transitions[id] = changes
+			}
+		}
+	}
+	return transitions, nil
+}
+
+// GetTaskTimeline returns the committed revisions of a task file, oldest first
+func (m *MultiDirStore) GetTaskTimeline(taskID string) ([]store.TaskRevision, error) {
+	if s := m.owner(taskID); s != nil {
+		return s.GetTaskTimeline(taskID)
+	}
+	return m.Primary().GetTaskTimeline(taskID)
+}
+
+// GetAllUsers returns list of all git users for assignee selection
+func (m *MultiDirStore) GetAllUsers() ([]string, error) {
+	return m.Primary().GetAllUsers()
+}
+
+// GetGitOps returns the git operations instance (needed for history construction)
+func (m *MultiDirStore) GetGitOps() git.GitOps {
+	return m.Primary().GetGitOps()
+}
+
+// FileAtRef returns the content of a repository file as of a branch, tag or commit
+func (m *MultiDirStore) FileAtRef(ref string, filePath string) (string, error) {
+	return m.Primary().FileAtRef(ref, filePath)
+}
+
+// StageFile adds a file outside the task directories, such as a doki page, to the git index
+func (m *MultiDirStore) StageFile(path string) error {
+	return m.Primary().StageFile(path)
+}
+
+// NewTaskTemplate returns a new task populated with template defaults
+func (m *MultiDirStore) NewTaskTemplate() (*taskpkg.Task, error) {
+	return m.NewTaskFromTemplate("", nil)
+}
+
+// TemplatePrompts returns the fields the named template asks for on creation
+func (m *MultiDirStore) TemplatePrompts(name string) ([]string, error) {
+	return m.Primary().TemplatePrompts(name)
+}
+
+// NewTaskFromTemplate is TikiStore.NewTaskFromTemplate with an ID no directory uses
+func (m *MultiDirStore) NewTaskFromTemplate(name string, values map[string]string) (*taskpkg.Task, error) {
+	for range maxIDAttempts {
+		task, err := m.Primary().NewTaskFromTemplate(name, values)
+		if err != nil {
+			return nil, err
+		}
+		// the template may have used the ID, so a taken one is regenerated, not replaced
+		if !m.idTaken(task.ID) {
+			return task, nil
+		}
+		slog.Debug("ID collision detected during template creation, regenerating", "id", task.ID)
+	}
+	return nil, errors.New("no free task ID found")
+}
+
+// ensure MultiDirStore implements Store
+var _ store.Store = (*MultiDirStore)(nil)
@@ -0,0 +1,144 @@
+package tikistore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+// newMultiDirProject returns a task directory and an archive directory in a temporary
+// project, each holding the given task files
+func newMultiDirProject(t *testing.T, active, archived map[string]string) (string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	t.Cleanup(config.ResetPathManager)
+
+	taskDir := filepath.Join(tmpDir, ".doc", "tiki")
+	archiveDir := filepath.Join(tmpDir, ".doc", "archive")
+	for dir, files := range map[string]map[string]string{taskDir: active, archiveDir: archived} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return taskDir, archiveDir
+}
+
+func taskFile(title, status string) string {
+	return "---\ntitle: " + title + "\ntype: story\nstatus: " + status + "\npriority: 3\n---\n"
+}
+
+func TestMultiDirStore(t *testing.T) {
+	taskDir, archiveDir := newMultiDirProject(t,
+		map[string]string{
+			"tiki-aaa111.md": taskFile("Active", "ready"),
+			"tiki-dup000.md": taskFile("Active copy", "ready"),
+		},
+		map[string]string{
+			"tiki-bbb222.md": taskFile("Archived", "done"),
+			"tiki-dup000.md": taskFile("Archived copy", "done"),
+		})
+
+	opened, err := store.OpenBackend("multidir", store.BackendOptions{Dirs: []string{taskDir, archiveDir}})
+	if err != nil {
+		t.Fatalf("OpenBackend: %v", err)
+	}
+	s := opened.(*MultiDirStore)
+
+	if got := len(s.GetAllTasks()); got != 3 {
+		t.Fatalf("GetAllTasks() returned %d tasks, want 3", got)
+	}
+	if dup := s.GetTask("TIKI-DUP000"); dup == nil || dup.Title != "Active copy" {
+		t.Errorf("duplicate ID should come from the first directory, got %+v", dup)
+	}
+	if results := s.Search("archived", nil); len(results) != 2 {
+		t.Errorf("Search(archived) returned %d results, want 2", len(results))
+	}
+
+	notified := 0
+	s.AddListener(func() { notified++ })
+
+	// an archived task is saved where it was loaded from
+	archived := s.GetTask("tiki-bbb222").Clone()
+	archived.Title = "Archived, renamed"
+	if err := s.UpdateTask(archived); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(archiveDir, "tiki-bbb222.md"))
+	if err != nil || !strings.Contains(string(content), "Archived, renamed") {
+		t.Errorf("archived task not saved in the archive: %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(taskDir, "tiki-bbb222.md")); !os.IsNotExist(err) {
+		t.Errorf("archived task should not be written to the task directory")
+	}
+	if got := s.TaskFilePath("TIKI-BBB222"); got != filepath.Join(archiveDir, "tiki-bbb222.md") {
+		t.Errorf("TaskFilePath = %s", got)
+	}
+
+	// new tasks go to the first directory
+	created := &taskpkg.Task{Title: "New", Type: taskpkg.TypeStory, Status: taskpkg.StatusBacklog, Priority: 3}
+	if err := s.CreateTask(created); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(taskDir, strings.ToLower(created.ID)+".md")); err != nil {
+		t.Errorf("new task not in the task directory: %v", err)
+	}
+	if err := s.CreateTask(&taskpkg.Task{ID: "TIKI-BBB222", Title: "Clash"}); err == nil {
+		t.Errorf("creating an ID the archive holds should fail")
+	}
+
+	if notified != 2 {
+		t.Errorf("listeners notified %d times, want 2", notified)
+	}
+
+	// a file changed on disk since it was loaded is a conflict, whichever directory it is in
+	stale := s.GetTask("TIKI-BBB222").Clone()
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(archiveDir, "tiki-bbb222.md"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateTask(stale); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("UpdateTask after an external change = %v, want ErrConflict", err)
+	}
+
+	s.DeleteTask("TIKI-BBB222")
+	if _, err := os.Stat(filepath.Join(archiveDir, "tiki-bbb222.md")); !os.IsNotExist(err) {
+		t.Errorf("DeleteTask should remove the archived file")
+	}
+	if s.GetTask("TIKI-BBB222") != nil {
+		t.Errorf("deleted task still in the store")
+	}
+}
+
+func TestOpenBackend(t *testing.T) {
+	taskDir, _ := newMultiDirProject(t, map[string]string{"tiki-aaa111.md": taskFile("Active", "ready")}, nil)
+
+	s, err := store.OpenBackend("", store.BackendOptions{Dirs: []string{taskDir}})
+	if err != nil {
+		t.Fatalf("OpenBackend: %v", err)
+	}
+	if _, ok := s.(*TikiStore); !ok {
+		t.Errorf("default backend = %T, want *TikiStore", s)
+	}
+
+	_, err = store.OpenBackend("jira", store.BackendOptions{Dirs: []string{taskDir}})
+	if err == nil || !strings.Contains(err.Error(), "multidir, sqlite, tiki") {
+		t.Errorf("unknown backend error should list the available ones, got %v", err)
+	}
+}
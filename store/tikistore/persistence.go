@@ -85,60 +85,16 @@ func (s *TikiStore) loadTaskFile(path string, authorMap map[string]*git.AuthorIn
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
-	frontmatter, body, err := store.ParseFrontmatter(string(content))
-	if err != nil {
-		return nil, fmt.Errorf("parsing frontmatter: %w", err)
-	}
-
-	var fm taskFrontmatter
-	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
-		return nil, fmt.Errorf("parsing yaml: %w", err)
-	}
-
 	// Derive ID from filename: "tiki-abc123.md" -> "TIKI-ABC123"
 	// IGNORE fm.ID even if present - filename is authoritative
 	filename := filepath.Base(path)
 	taskID := strings.ToUpper(strings.TrimSuffix(filename, ".md"))
 
-	// Log warning if frontmatter has ID that differs from filename
-	// Parse frontmatter as generic map to check for ID field
-	var fmMap map[string]interface{}
-	if err := yaml.Unmarshal([]byte(frontmatter), &fmMap); err == nil {
-		if rawID, ok := fmMap["id"]; ok {
-			if idStr, ok := rawID.(string); ok && idStr != "" && idStr != taskID {
-				slog.Warn("ignoring frontmatter ID mismatch, using filename",
-					"file", path,
-					"frontmatter_id", idStr,
-					"filename_id", taskID)
-			}
-		}
-	}
-
-	task := &taskpkg.Task{
-		ID:          taskID,
-		Title:       fm.Title,
-		Description: strings.TrimSpace(body),
-		Type:        taskpkg.NormalizeType(fm.Type),
-		Status:      taskpkg.MapStatus(fm.Status),
-		Tags:        fm.Tags.ToStringSlice(),
-		Assignee:    fm.Assignee,
-		Priority:    int(fm.Priority),
-		Points:      fm.Points,
-		LoadedMtime: info.ModTime(),
-	}
-
-	// Validate and default Priority field (1-5 range)
-	if task.Priority < taskpkg.MinPriority || task.Priority > taskpkg.MaxPriority {
-		slog.Debug("invalid priority value, using default", "task_id", task.ID, "file", path, "invalid_value", task.Priority, "default", taskpkg.DefaultPriority)
-		task.Priority = taskpkg.DefaultPriority
-	}
-
-	// Validate and default Points field
-	maxPoints := config.GetMaxPoints()
-	if task.Points < 1 || task.Points > maxPoints {
-		task.Points = maxPoints / 2
-		slog.Debug("invalid points value, using default", "task_id", task.ID, "file", path, "invalid_value", fm.Points, "default", task.Points)
+	task, err := parseTask(taskID, string(content), path)
+	if err != nil {
+		return nil, err
 	}
+	task.LoadedMtime = info.ModTime()
 
 	// Compute UpdatedAt as max(file_mtime, last_git_commit_time)
 	task.UpdatedAt = info.ModTime() // Start with file mtime
@@ -272,7 +228,7 @@ func (s *TikiStore) saveTask(task *taskpkg.Task) error {
 		if info, err := os.Stat(path); err == nil {
 			if !info.ModTime().Equal(task.LoadedMtime) {
 				slog.Warn("task modified externally, conflict detected", "task_id", task.ID, "path", path, "loaded_mtime", task.LoadedMtime, "file_mtime", info.ModTime())
-				return ErrConflict
+				return store.ErrConflict
 			}
 		} else if !os.IsNotExist(err) {
 			slog.Error("failed to stat file for optimistic locking", "task_id", task.ID, "path", path, "error", err)
@@ -280,37 +236,13 @@ func (s *TikiStore) saveTask(task *taskpkg.Task) error {
 		}
 	}
 
-	fm := taskFrontmatter{
-		Title:    task.Title,
-		Type:     string(task.Type),
-		Status:   taskpkg.StatusToString(task.Status),
-		Tags:     task.Tags,
-		Assignee: task.Assignee,
-		Priority: taskpkg.PriorityValue(task.Priority),
-		Points:   task.Points,
-	}
-
-	// sort tags for consistent output
-	if len(fm.Tags) > 0 {
-		sort.Strings(fm.Tags)
-	}
-
-	yamlBytes, err := yaml.Marshal(fm)
+	content, err := marshalTask(task)
 	if err != nil {
 		slog.Error("failed to marshal frontmatter for task", "task_id", task.ID, "error", err)
-		return fmt.Errorf("marshaling frontmatter: %w", err)
-	}
-
-	var content strings.Builder
-	content.WriteString("---\n")
-	content.Write(yamlBytes)
-	content.WriteString("---\n")
-	if task.Description != "" {
-		content.WriteString(task.Description)
-		content.WriteString("\n")
+		return err
 	}
 
-	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		slog.Error("failed to write task file", "task_id", task.ID, "path", path, "error", err)
 		return fmt.Errorf("writing file: %w", err)
 	}
@@ -343,9 +275,106 @@ func (s *TikiStore) saveTask(task *taskpkg.Task) error {
 	return nil
 }
 
+// TaskFilePath returns the markdown file of a task: TIKI-ABC123 -> <dir>/tiki-abc123.md
+func (s *TikiStore) TaskFilePath(id string) string {
+	return s.taskFilePath(normalizeTaskID(id))
+}
+
 // taskFilePath returns the file path for a task ID
 func (s *TikiStore) taskFilePath(id string) string {
 	// convert ID to lowercase filename: TIKI-ABC123 -> tiki-abc123.md
 	filename := strings.ToLower(id) + ".md"
 	return filepath.Join(s.dir, filename)
 }
+
+// marshalTask returns the markdown of a task: YAML frontmatter, then the description
+func marshalTask(task *taskpkg.Task) (string, error) {
+	fm := taskFrontmatter{
+		Title:    task.Title,
+		Type:     string(task.Type),
+		Status:   taskpkg.StatusToString(task.Status),
+		Tags:     task.Tags,
+		Assignee: task.Assignee,
+		Priority: taskpkg.PriorityValue(task.Priority),
+		Points:   task.Points,
+
+		ExternalID: task.ExternalID,
+		Fields:     task.Fields,
+	}
+
+	// sort tags for consistent output
+	if len(fm.Tags) > 0 {
+		sort.Strings(fm.Tags)
+	}
+
+	yamlBytes, err := yaml.Marshal(fm)
+	if err != nil {
+		return "", fmt.Errorf("marshaling frontmatter: %w", err)
+	}
+
+	var content strings.Builder
+	content.WriteString("---\n")
+	content.Write(yamlBytes)
+	content.WriteString("---\n")
+	if task.Description != "" {
+		content.WriteString(task.Description)
+		content.WriteString("\n")
+	}
+	return content.String(), nil
+}
+
+// parseTask reads the markdown of task id, as marshalTask writes it. source names where
+// the markdown came from in logs. Out of range priorities and points get the defaults.
+func parseTask(id, content, source string) (*taskpkg.Task, error) {
+	frontmatter, body, err := store.ParseFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing frontmatter: %w", err)
+	}
+
+	var fm taskFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return nil, fmt.Errorf("parsing yaml: %w", err)
+	}
+
+	// Log warning if frontmatter has ID that differs from the authoritative one
+	// Parse frontmatter as generic map to check for ID field
+	var fmMap map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fmMap); err == nil {
+		if rawID, ok := fmMap["id"]; ok {
+			if idStr, ok := rawID.(string); ok && idStr != "" && idStr != id {
+				slog.Warn("ignoring frontmatter ID mismatch, using filename",
+					"file", source,
+					"frontmatter_id", idStr,
+					"filename_id", id)
+			}
+		}
+	}
+
+	task := &taskpkg.Task{
+		ID:          id,
+		Title:       fm.Title,
+		Description: strings.TrimSpace(body),
+		Type:        taskpkg.NormalizeType(fm.Type),
+		Status:      taskpkg.MapStatus(fm.Status),
+		Tags:        fm.Tags.ToStringSlice(),
+		Assignee:    fm.Assignee,
+		Priority:    int(fm.Priority),
+		Points:      fm.Points,
+		ExternalID:  fm.ExternalID,
+		Fields:      fm.Fields,
+	}
+
+	// Validate and default Priority field (1-5 range)
+	if task.Priority < taskpkg.MinPriority || task.Priority > taskpkg.MaxPriority {
+		slog.Debug("invalid priority value, using default", "task_id", task.ID, "file", source, "invalid_value", task.Priority, "default", taskpkg.DefaultPriority)
+		task.Priority = taskpkg.DefaultPriority
+	}
+
+	// Validate and default Points field
+	maxPoints := config.GetMaxPoints()
+	if task.Points < 1 || task.Points > maxPoints {
+		task.Points = maxPoints / 2
+		slog.Debug("invalid points value, using default", "task_id", task.ID, "file", source, "invalid_value", fm.Points, "default", task.Points)
+	}
+	return task, nil
+}
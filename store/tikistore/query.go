@@ -11,9 +11,13 @@ import (
 func (s *TikiStore) GetAllTasks() []*taskpkg.Task {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	return allTasks(s.tasks)
+}
 
-	tasks := make([]*taskpkg.Task, 0, len(s.tasks))
-	for _, t := range s.tasks {
+// allTasks returns the tasks of a store's map, sorted by priority then title
+func allTasks(byID map[string]*taskpkg.Task) []*taskpkg.Task {
+	tasks := make([]*taskpkg.Task, 0, len(byID))
+	for _, t := range byID {
 		tasks = append(tasks, t)
 	}
 	sortTasks(tasks)
@@ -37,7 +41,11 @@ func matchesQuery(task *taskpkg.Task, queryLower string) bool {
 func (s *TikiStore) Search(query string, filterFunc func(*taskpkg.Task) bool) []taskpkg.SearchResult {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	return searchTasks(s.tasks, query, filterFunc)
+}
 
+// searchTasks implements Search over the tasks of a store's map
+func searchTasks(byID map[string]*taskpkg.Task, query string, filterFunc func(*taskpkg.Task) bool) []taskpkg.SearchResult {
 	query = strings.TrimSpace(query)
 	queryLower := strings.ToLower(query)
 
@@ -45,14 +53,14 @@ func (s *TikiStore) Search(query string, filterFunc func(*taskpkg.Task) bool) []
 	var candidateTasks []*taskpkg.Task
 	if filterFunc != nil {
 		// Apply custom filter function
-		for _, t := range s.tasks {
+		for _, t := range byID {
 			if filterFunc(t) {
 				candidateTasks = append(candidateTasks, t)
 			}
 		}
 	} else {
 		// No filter = all tasks
-		for _, t := range s.tasks {
+		for _, t := range byID {
 			candidateTasks = append(candidateTasks, t)
 		}
 	}
@@ -0,0 +1,757 @@
+package tikistore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/store/internal/git"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+
+	_ "modernc.org/sqlite" // pure Go driver, registered as "sqlite"
+)
+
+// sqliteSchema keeps each task as the markdown a tiki file would hold, so both stores
+// read and write tasks the same way. Every save adds a revision, which stands in for git
+// history: the timeline, status transitions and burndown are built from revisions.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	id         TEXT PRIMARY KEY,
+	content    TEXT NOT NULL,
+	created_by TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS comments (
+	task_id    TEXT NOT NULL,
+	id         TEXT NOT NULL,
+	author     TEXT NOT NULL,
+	text       TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS comments_task ON comments (task_id);
+CREATE TABLE IF NOT EXISTS revisions (
+	seq     INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id TEXT NOT NULL,
+	author  TEXT NOT NULL,
+	email   TEXT NOT NULL,
+	at      INTEGER NOT NULL,
+	content TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS revisions_task ON revisions (task_id, seq);
+`
+
+// SQLiteDatabaseName is the database of the sqlite backend, next to the task directory
+const SQLiteDatabaseName = "tiki.db"
+
+// SQLiteStore keeps tasks in a SQLite database. Unlike tiki files, comments are saved
+// too. Every saved version of a task is kept as a revision, so the timeline, status
+// transitions and burndown don't need git. A save is rejected with ErrConflict when
+// another process saved the task since it was loaded: Task.LoadedMtime holds the time
+// of the loaded version.
+type SQLiteStore struct {
+	mu             sync.RWMutex
+	path           string
+	db             *sql.DB
+	tasks          map[string]*taskpkg.Task
+	listeners      map[int]store.ChangeListener
+	nextListenerID int
+	gitUtil        git.GitOps             // current user and branch; nil outside a repository
+	burndown       *store.BurndownOptions // nil until SetBurndownOptions
+}
+
+// NewSQLiteStore opens, or creates, the task database at path
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	slog.Debug("creating new SQLiteStore", "path", path)
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	// one connection serializes the store's transactions; other processes wait on the busy timeout
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create schema in %s: %w", path, err)
+	}
+
+	s := &SQLiteStore{
+		path:           path,
+		db:             db,
+		listeners:      make(map[int]store.ChangeListener),
+		nextListenerID: 1,
+	}
+
+	// Initialize git utility (best effort - don't fail if git is not available)
+	if gitUtil, err := git.NewGitOps(""); err == nil {
+		s.gitUtil = gitUtil
+	} else {
+		slog.Debug("git utility not initialized", "error", err)
+	}
+
+	tasks, err := s.load()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("loading tasks: %w", err)
+	}
+	s.tasks = tasks
+
+	slog.Info("sqliteStore initialized", "path", path, "num_tasks", len(s.tasks))
+	return s, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the database file
+func (s *SQLiteStore) Path() string {
+	return s.path
+}
+
+// AddListener registers a callback for change notifications.
+// returns a listener ID that can be used to remove the listener.
+func (s *SQLiteStore) AddListener(listener store.ChangeListener) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextListenerID
+	s.nextListenerID++
+	s.listeners[id] = listener
+	return id
+}
+
+// RemoveListener removes a previously registered listener by ID
+func (s *SQLiteStore) RemoveListener(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, id)
+}
+
+func (s *SQLiteStore) notifyListeners() {
+	s.mu.RLock()
+	listeners := make([]store.ChangeListener, 0, len(s.listeners))
+	for _, l := range s.listeners {
+		listeners = append(listeners, l)
+	}
+	s.mu.RUnlock()
+
+	for _, l := range listeners {
+		l()
+	}
+}
+
+// load reads every task and its comments from the database
+func (s *SQLiteStore) load() (map[string]*taskpkg.Task, error) {
+	rows, err := s.db.Query(`SELECT id, content, created_by, created_at, updated_at FROM tasks`)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make(map[string]*taskpkg.Task)
+	for rows.Next() {
+		task, err := s.scanTask(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		tasks[task.ID] = task
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.db.Query(`SELECT task_id, id, author, text, created_at FROM comments ORDER BY created_at, rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = comments.Close() }()
+	for comments.Next() {
+		var taskID string
+		var comment taskpkg.Comment
+		var createdAt int64
+		if err := comments.Scan(&taskID, &comment.ID, &comment.Author, &comment.Text, &createdAt); err != nil {
+			return nil, err
+		}
+		comment.CreatedAt = time.Unix(0, createdAt)
+		if task := tasks[taskID]; task != nil {
+			task.Comments = append(task.Comments, comment)
+		}
+	}
+	return tasks, comments.Err()
+}
+
+// scanTask reads a row of the tasks table
+func (s *SQLiteStore) scanTask(row interface{ Scan(...any) error }) (*taskpkg.Task, error) {
+	var id, content, createdBy string
+	var createdAt, updatedAt int64
+	if err := row.Scan(&id, &content, &createdBy, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	task, err := parseTask(id, content, s.path)
+	if err != nil {
+		return nil, fmt.Errorf("task %s: %w", id, err)
+	}
+	task.CreatedBy = createdBy
+	task.CreatedAt = time.Unix(0, createdAt)
+	task.UpdatedAt = time.Unix(0, updatedAt)
+	task.LoadedMtime = task.UpdatedAt
+	return task, nil
+}
+
+// idTaken reports whether a task has the ID, loaded or saved by another process since
+func (s *SQLiteStore) idTaken(id string) bool {
+	if _, ok := s.tasks[id]; ok {
+		return true
+	}
+	var n int
+	err := s.db.QueryRow(`SELECT count(*) FROM tasks WHERE id = ?`, id).Scan(&n)
+	return err != nil || n > 0
+}
+
+// newTaskID returns a random task ID no task has
+func (s *SQLiteStore) newTaskID() (string, error) {
+	for range maxIDAttempts {
+		id := normalizeTaskID(fmt.Sprintf("TIKI-%s", config.GenerateRandomID()))
+		if !s.idTaken(id) {
+			return id, nil
+		}
+		slog.Debug("ID collision detected, regenerating", "id", id)
+	}
+	return "", errors.New("no free task ID found")
+}
+
+// revisionAuthor is the git user saving a revision, best effort
+func (s *SQLiteStore) revisionAuthor() (name, email string) {
+	if s.gitUtil == nil {
+		return "", ""
+	}
+	name, email, _ = s.gitUtil.CurrentUser()
+	return name, email
+}
+
+// addRevision records a saved version of a task
+func (s *SQLiteStore) addRevision(tx *sql.Tx, id, content string, at time.Time) error {
+	name, email := s.revisionAuthor()
+	_, err := tx.Exec(`INSERT INTO revisions (task_id, author, email, at, content) VALUES (?, ?, ?, ?, ?)`,
+		id, name, email, at.UnixNano(), content)
+	return err
+}
+
+// CreateTask adds a new task to the database
+func (s *SQLiteStore) CreateTask(task *taskpkg.Task) error {
+	s.mu.Lock()
+
+	if task.ID == "" {
+		id, err := s.newTaskID()
+		if err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		task.ID = id
+	}
+	task.ID = normalizeTaskID(task.ID)
+	if s.idTaken(task.ID) {
+		s.mu.Unlock()
+		return fmt.Errorf("task %s already exists", task.ID)
+	}
+
+	content, err := marshalTask(task)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	now := time.Now()
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now
+	}
+	if task.CreatedBy == "" {
+		task.CreatedBy = gitAuthor(s.gitUtil)
+	}
+
+	err = s.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO tasks (id, content, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+			task.ID, content, task.CreatedBy, task.CreatedAt.UnixNano(), now.UnixNano())
+		if err != nil {
+			return err
+		}
+		for _, comment := range task.Comments {
+			if err := insertComment(tx, task.ID, comment); err != nil {
+				return err
+			}
+		}
+		return s.addRevision(tx, task.ID, content, now)
+	})
+	if err != nil {
+		s.mu.Unlock()
+		slog.Error("failed to save new task after creation", "task_id", task.ID, "error", err)
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	task.UpdatedAt = now
+	task.LoadedMtime = now
+	s.tasks[task.ID] = task
+	s.mu.Unlock()
+
+	slog.Info("task created", "task_id", task.ID, "status", task.Status)
+	s.notifyListeners()
+	return nil
+}
+
+// GetTask retrieves a task by ID
+func (s *SQLiteStore) GetTask(id string) *taskpkg.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tasks[normalizeTaskID(id)]
+}
+
+// UpdateTask saves a task. Returns ErrConflict when another process saved it since the
+// version in task.LoadedMtime.
+func (s *SQLiteStore) UpdateTask(task *taskpkg.Task) error {
+	s.mu.Lock()
+
+	task.ID = normalizeTaskID(task.ID)
+	if _, exists := s.tasks[task.ID]; !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("task not found: %s", task.ID)
+	}
+
+	content, err := marshalTask(task)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	// versions are told apart by their time, which must move forward
+	now := time.Now()
+	if !now.After(task.LoadedMtime) {
+		now = task.LoadedMtime.Add(time.Nanosecond)
+	}
+
+	err = s.inTx(func(tx *sql.Tx) error {
+		var result sql.Result
+		var err error
+		if task.LoadedMtime.IsZero() {
+			result, err = tx.Exec(`UPDATE tasks SET content = ?, updated_at = ? WHERE id = ?`,
+				content, now.UnixNano(), task.ID)
+		} else {
+			result, err = tx.Exec(`UPDATE tasks SET content = ?, updated_at = ? WHERE id = ? AND updated_at = ?`,
+				content, now.UnixNano(), task.ID, task.LoadedMtime.UnixNano())
+		}
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			slog.Warn("task modified externally, conflict detected", "task_id", task.ID, "path", s.path, "loaded_mtime", task.LoadedMtime)
+			return store.ErrConflict
+		}
+		return s.addRevision(tx, task.ID, content, now)
+	})
+	if err != nil {
+		s.mu.Unlock()
+		slog.Error("failed to save updated task", "task_id", task.ID, "error", err)
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	task.UpdatedAt = now
+	task.LoadedMtime = now
+	s.tasks[task.ID] = task
+	s.mu.Unlock()
+
+	slog.Info("task updated", "task_id", task.ID, "status", task.Status)
+	s.notifyListeners()
+	return nil
+}
+
+// DeleteTask removes a task with its comments and revisions
+func (s *SQLiteStore) DeleteTask(id string) {
+	s.mu.Lock()
+
+	normalizedID := normalizeTaskID(id)
+	if _, exists := s.tasks[normalizedID]; !exists {
+		s.mu.Unlock()
+		return
+	}
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		for _, table := range []string{"tasks WHERE id", "comments WHERE task_id", "revisions WHERE task_id"} {
+			//nolint:gosec // G202: table names are constants
+			if _, err := tx.Exec("DELETE FROM "+table+" = ?", normalizedID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.mu.Unlock()
+		slog.Error("task deletion failed, task preserved", "task_id", normalizedID, "error", err)
+		return
+	}
+	delete(s.tasks, normalizedID)
+	s.mu.Unlock()
+
+	slog.Info("task deleted", "task_id", normalizedID)
+	s.notifyListeners()
+}
+
+// GetAllTasks returns all tasks, sorted by priority then title
+func (s *SQLiteStore) GetAllTasks() []*taskpkg.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return allTasks(s.tasks)
+}
+
+// Search searches tasks, see TikiStore.Search
+func (s *SQLiteStore) Search(query string, filterFunc func(*taskpkg.Task) bool) []taskpkg.SearchResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return searchTasks(s.tasks, query, filterFunc)
+}
+
+// AddComment adds a comment to a task and saves it
+func (s *SQLiteStore) AddComment(taskID string, comment taskpkg.Comment) bool {
+	s.mu.Lock()
+
+	taskID = normalizeTaskID(taskID)
+	task, exists := s.tasks[taskID]
+	if !exists {
+		s.mu.Unlock()
+		return false
+	}
+	if err := s.inTx(func(tx *sql.Tx) error { return insertComment(tx, taskID, comment) }); err != nil {
+		s.mu.Unlock()
+		slog.Error("failed to save comment", "task_id", taskID, "error", err)
+		return false
+	}
+	task.Comments = append(task.Comments, comment)
+	s.mu.Unlock()
+
+	s.notifyListeners()
+	return true
+}
+
+func insertComment(tx *sql.Tx, taskID string, comment taskpkg.Comment) error {
+	_, err := tx.Exec(`INSERT INTO comments (task_id, id, author, text, created_at) VALUES (?, ?, ?, ?, ?)`,
+		taskID, comment.ID, comment.Author, comment.Text, comment.CreatedAt.UnixNano())
+	return err
+}
+
+// Reload reloads all tasks from the database
+func (s *SQLiteStore) Reload() error {
+	tasks, err := s.load()
+	if err != nil {
+		slog.Error("error reloading tasks from database", "error", err)
+		return err
+	}
+	s.mu.Lock()
+	s.tasks = tasks
+	s.mu.Unlock()
+
+	s.notifyListeners()
+	return nil
+}
+
+// ReloadTask reloads a single task from the database by ID
+func (s *SQLiteStore) ReloadTask(taskID string) error {
+	normalizedID := normalizeTaskID(taskID)
+	row := s.db.QueryRow(`SELECT id, content, created_by, created_at, updated_at FROM tasks WHERE id = ?`, normalizedID)
+	task, err := s.scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("task not found: %s", normalizedID)
+	}
+	if err != nil {
+		return fmt.Errorf("loading task %s: %w", normalizedID, err)
+	}
+
+	s.mu.Lock()
+	if old := s.tasks[normalizedID]; old != nil {
+		task.Comments = old.Comments
+	}
+	s.tasks[normalizedID] = task
+	s.mu.Unlock()
+
+	s.notifyListeners()
+	return nil
+}
+
+// TaskSource returns a task as the markdown of a tiki file, for editing it as a file
+func (s *SQLiteStore) TaskSource(id string) (string, error) {
+	task := s.GetTask(id)
+	if task == nil {
+		return "", fmt.Errorf("task not found: %s", normalizeTaskID(id))
+	}
+	return marshalTask(task.Clone())
+}
+
+// SetTaskSource saves a task edited as the markdown TaskSource returned. The edit is
+// rejected with ErrConflict when the task was saved in between.
+func (s *SQLiteStore) SetTaskSource(id, content string) error {
+	current := s.GetTask(id)
+	if current == nil {
+		return fmt.Errorf("task not found: %s", normalizeTaskID(id))
+	}
+	task, err := parseTask(current.ID, content, s.path)
+	if err != nil {
+		return err
+	}
+	task.ID = current.ID
+	task.CreatedBy = current.CreatedBy
+	task.CreatedAt = current.CreatedAt
+	task.Comments = current.Comments
+	task.LoadedMtime = current.LoadedMtime
+	return s.UpdateTask(task)
+}
+
+// inTx runs fn in a transaction, committed when fn succeeds
+func (s *SQLiteStore) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetCurrentUser returns the current git user name and email
+func (s *SQLiteStore) GetCurrentUser() (name string, email string, err error) {
+	if s.gitUtil == nil {
+		return "n/a", "", fmt.Errorf("git utility not available")
+	}
+	return s.gitUtil.CurrentUser()
+}
+
+// GetStats returns statistics for the header (user, branch)
+func (s *SQLiteStore) GetStats() []store.Stat {
+	user, branch := "n/a", "n/a"
+	if s.gitUtil != nil {
+		if name, _, err := s.gitUtil.CurrentUser(); err == nil && name != "" {
+			user = name
+		}
+		if b, err := s.gitUtil.CurrentBranch(); err == nil {
+			branch = b
+		}
+	}
+	return []store.Stat{
+		{Name: "User", Value: user, Order: 3},
+		{Name: "Branch", Value: branch, Order: 4},
+	}
+}
+
+// GetAllUsers returns the git users and everyone who saved a revision, for assignee selection
+func (s *SQLiteStore) GetAllUsers() ([]string, error) {
+	var users []string
+	seen := make(map[string]bool)
+	if s.gitUtil != nil {
+		if gitUsers, err := s.gitUtil.AllUsers(); err == nil {
+			for _, u := range gitUsers {
+				seen[u] = true
+			}
+			users = append(users, gitUsers...)
+		}
+	}
+
+	rows, err := s.db.Query(`SELECT DISTINCT author FROM revisions WHERE author != '' ORDER BY author`)
+	if err != nil {
+		return nil, fmt.Errorf("loading revision authors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var author string
+		if err := rows.Scan(&author); err != nil {
+			return nil, err
+		}
+		if !seen[author] {
+			seen[author] = true
+			users = append(users, author)
+		}
+	}
+	return users, rows.Err()
+}
+
+// SetBurndownOptions configures the burndown GetBurndown builds from the revisions
+func (s *SQLiteStore) SetBurndownOptions(options store.BurndownOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.burndown = &options
+}
+
+// GetBurndown returns the burndown chart data, nil until SetBurndownOptions
+func (s *SQLiteStore) GetBurndown() []store.BurndownPoint {
+	s.mu.RLock()
+	options := s.burndown
+	s.mu.RUnlock()
+	if options == nil {
+		return nil
+	}
+
+	history := store.NewTaskHistory(s.path, revisionSource{db: s.db})
+	history.SetBurndownOptions(*options)
+	if err := history.Build(); err != nil {
+		slog.Warn("failed to build task history", "path", s.path, "error", err)
+		return nil
+	}
+	return history.Burndown()
+}
+
+// GetStatusTransitions returns every status change of every task, from the revisions
+func (s *SQLiteStore) GetStatusTransitions() (map[string][]store.StatusChange, error) {
+	history := store.NewFullTaskHistory(s.path, revisionSource{db: s.db})
+	if err := history.Build(); err != nil {
+		return nil, fmt.Errorf("building task history: %w", err)
+	}
+	return history.Transitions(), nil
+}
+
+// GetTaskTimeline returns the saved revisions of a task, oldest first
+func (s *SQLiteStore) GetTaskTimeline(taskID string) ([]store.TaskRevision, error) {
+	versions, err := revisionSource{db: s.db}.taskVersions(normalizeTaskID(taskID))
+	if err != nil {
+		return nil, fmt.Errorf("loading versions for %s: %w", taskID, err)
+	}
+	return store.BuildTaskTimeline(versions)
+}
+
+// NewTaskTemplate returns a new task populated with template defaults
+func (s *SQLiteStore) NewTaskTemplate() (*taskpkg.Task, error) {
+	return s.NewTaskFromTemplate("", nil)
+}
+
+// TemplatePrompts returns the fields the named template asks for on creation
+func (s *SQLiteStore) TemplatePrompts(name string) ([]string, error) {
+	source, err := loadTemplateSource(name)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := templatePrompts(templateLabel(name), source)
+	if err != nil && name == "" {
+		slog.Warn("failed to parse new.md placeholders, using it verbatim", "error", err)
+		return nil, nil
+	}
+	return fields, err
+}
+
+// NewTaskFromTemplate is TikiStore.NewTaskFromTemplate with an ID no task in the database has
+func (s *SQLiteStore) NewTaskFromTemplate(name string, values map[string]string) (*taskpkg.Task, error) {
+	source, err := loadTemplateSource(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	id, err := s.newTaskID()
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return newTaskFromTemplate(name, source, values, id, s.gitUtil)
+}
+
+// revisionSource serves the revisions table as the file versions a task history reads,
+// each task as if it were the file <id>.md
+type revisionSource struct {
+	db *sql.DB
+}
+
+// AllFileVersionsSince returns the revisions that changed a task's status, like
+// git log -G^status: does for tiki files
+func (r revisionSource) AllFileVersionsSince(_ string, since time.Time, includePrior bool) (map[string][]git.FileVersion, error) {
+	return r.versionsSince(since, includePrior, true)
+}
+
+// AllFileChangesSince returns every revision
+func (r revisionSource) AllFileChangesSince(_ string, since time.Time, includePrior bool) (map[string][]git.FileVersion, error) {
+	return r.versionsSince(since, includePrior, false)
+}
+
+func (r revisionSource) versionsSince(since time.Time, includePrior, statusOnly bool) (map[string][]git.FileVersion, error) {
+	all, err := r.query(`SELECT task_id, seq, author, email, at, content FROM revisions ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]git.FileVersion)
+	for file, versions := range all {
+		var kept []git.FileVersion
+		var prior *git.FileVersion
+		lastStatus := ""
+		for i, v := range versions {
+			status := revisionStatus(v.Content)
+			changed := i == 0 || status != lastStatus
+			lastStatus = status
+			if statusOnly && !changed {
+				continue
+			}
+			if v.When.Before(since) {
+				prior = &versions[i]
+				continue
+			}
+			kept = append(kept, v)
+		}
+		if includePrior && prior != nil {
+			kept = append([]git.FileVersion{*prior}, kept...)
+		}
+		if len(kept) > 0 {
+			result[file] = kept
+		}
+	}
+	return result, nil
+}
+
+// taskVersions returns every revision of a task, oldest first
+func (r revisionSource) taskVersions(id string) ([]git.FileVersion, error) {
+	all, err := r.query(`SELECT task_id, seq, author, email, at, content FROM revisions WHERE task_id = ? ORDER BY seq`, id)
+	if err != nil {
+		return nil, err
+	}
+	return all[revisionFile(id)], nil
+}
+
+func (r revisionSource) query(query string, args ...any) (map[string][]git.FileVersion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("loading revisions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string][]git.FileVersion)
+	for rows.Next() {
+		var id string
+		var seq, at int64
+		var v git.FileVersion
+		if err := rows.Scan(&id, &seq, &v.Author, &v.Email, &at, &v.Content); err != nil {
+			return nil, err
+		}
+		v.Hash = fmt.Sprintf("r%d", seq)
+		v.When = time.Unix(0, at)
+		result[revisionFile(id)] = append(result[revisionFile(id)], v)
+	}
+	return result, rows.Err()
+}
+
+// revisionFile names a task's revisions the way a history names a tiki file
+func revisionFile(id string) string {
+	return strings.ToLower(id) + ".md"
+}
+
+// revisionStatus returns the status line of a revision's frontmatter
+func revisionStatus(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "status:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "status:"))
+		}
+	}
+	return ""
+}
+
+// ensure SQLiteStore implements Store
+var _ store.Store = (*SQLiteStore)(nil)
+
+// ensure revisionSource serves task histories
+var _ store.VersionSource = revisionSource{}
@@ -3,20 +3,19 @@ package tikistore
 // TikiStore is a file-based Store implementation that persists tasks as markdown files.
 
 import (
-	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/boolean-maybe/tiki/store"
 	"github.com/boolean-maybe/tiki/store/internal/git"
 	taskpkg "github.com/boolean-maybe/tiki/task"
 )
 
-// ErrConflict indicates a task was modified externally since it was loaded
-var ErrConflict = errors.New("task was modified externally")
-
 func normalizeTaskID(id string) string {
 	return strings.ToUpper(strings.TrimSpace(id))
 }
@@ -43,11 +42,29 @@ type taskFrontmatter struct {
 	Assignee string                `yaml:"assignee,omitempty"`
 	Priority taskpkg.PriorityValue `yaml:"priority,omitempty"`
 	Points   int                   `yaml:"points,omitempty"`
+	// set on imported tasks
+	ExternalID string            `yaml:"external_id,omitempty"`
+	Fields     map[string]string `yaml:"fields,omitempty"`
 }
 
 // NewTikiStore creates a new TikiStore.
 // dir: directory containing task markdown files
 func NewTikiStore(dir string) (*TikiStore, error) {
+	return newTikiStore(dir, "")
+}
+
+// NewRepoTikiStore creates a TikiStore over the .doc/tiki directory of the repository at
+// root, running git there instead of in the working directory
+func NewRepoTikiStore(root string) (*TikiStore, error) {
+	dir := filepath.Join(root, ".doc", "tiki")
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a tiki project: no .doc/tiki directory", root)
+	}
+	return newTikiStore(dir, root)
+}
+
+// newTikiStore creates a TikiStore over dir with git run in repoPath (empty: the working directory)
+func newTikiStore(dir, repoPath string) (*TikiStore, error) {
 	slog.Debug("creating new TikiStore", "dir", dir)
 	s := &TikiStore{
 		dir:            dir,
@@ -57,7 +74,7 @@ func NewTikiStore(dir string) (*TikiStore, error) {
 	}
 
 	// Initialize git utility (best effort - don't fail if git is not available)
-	gitUtil, err := git.NewGitOps("")
+	gitUtil, err := git.NewGitOps(repoPath)
 	if err == nil {
 		s.gitUtil = gitUtil
 	} else {
@@ -76,6 +93,11 @@ func NewTikiStore(dir string) (*TikiStore, error) {
 	return s, nil
 }
 
+// Dir returns the directory the task files are in
+func (s *TikiStore) Dir() string {
+	return s.dir
+}
+
 // SetTaskHistory sets the task history instance (called after background build completes)
 func (s *TikiStore) SetTaskHistory(history *store.TaskHistory) {
 	s.mu.Lock()
@@ -143,6 +165,56 @@ func (s *TikiStore) GetBurndown() []store.BurndownPoint {
 	return s.taskHistory.Burndown()
 }
 
+// GetStatusTransitions builds the full status history of all tasks from git.
+// Unlike the burndown history this walks every commit, so callers should not invoke it on hot paths.
+func (s *TikiStore) GetStatusTransitions() (map[string][]store.StatusChange, error) {
+	// No lock needed - gitUtil and dir are immutable after initialization
+	if s.gitUtil == nil {
+		return nil, fmt.Errorf("git utility not available")
+	}
+
+	history := store.NewFullTaskHistory(s.dir, s.gitUtil)
+	if err := history.Build(); err != nil {
+		return nil, fmt.Errorf("building task history: %w", err)
+	}
+	return history.Transitions(), nil
+}
+
+// GetTaskTimeline returns the committed revisions of a task file, oldest first
+func (s *TikiStore) GetTaskTimeline(taskID string) ([]store.TaskRevision, error) {
+	// No lock needed - gitUtil is immutable after initialization
+	if s.gitUtil == nil {
+		return nil, fmt.Errorf("git utility not available")
+	}
+
+	versions, err := s.gitUtil.FileVersionsSince(s.taskFilePath(taskID), time.Unix(0, 0), false)
+	if err != nil {
+		return nil, fmt.Errorf("loading versions for %s: %w", taskID, err)
+	}
+
+	return store.BuildTaskTimeline(versions)
+}
+
+// FileAtRef returns the content of a repository file as of a branch, tag or commit
+func (s *TikiStore) FileAtRef(ref string, filePath string) (string, error) {
+	// No lock needed - gitUtil is immutable after initialization
+	if s.gitUtil == nil {
+		return "", fmt.Errorf("git utility not available")
+	}
+
+	return s.gitUtil.FileAtRef(ref, filePath)
+}
+
+// StageFile adds a file outside the task directory, such as a doki page, to the git index
+func (s *TikiStore) StageFile(path string) error {
+	// No lock needed - gitUtil is immutable after initialization
+	if s.gitUtil == nil {
+		return fmt.Errorf("git utility not available")
+	}
+
+	return s.gitUtil.Add(path)
+}
+
 // GetAllUsers returns list of all git users for assignee selection
 func (s *TikiStore) GetAllUsers() ([]string, error) {
 	// No lock needed - gitUtil is immutable after initialization
@@ -1,13 +1,17 @@
 package tikistore
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store/internal/git"
 	taskpkg "github.com/boolean-maybe/tiki/task"
 
 	"gopkg.in/yaml.v3"
@@ -24,8 +28,21 @@ type templateFrontmatter struct {
 	Points   int      `yaml:"points"`
 }
 
-// loadTemplateTask reads new.md from user config directory, or falls back to embedded template.
-func loadTemplateTask() *taskpkg.Task {
+// loadTemplateSource reads a named template from the templates directories, or new.md
+// when name is empty: from the user config directory, falling back to the embedded one.
+func loadTemplateSource(name string) ([]byte, error) {
+	if name != "" {
+		templatePath, err := config.FindTaskTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("read template %q: %w", name, err)
+		}
+		return data, nil
+	}
+
 	// Try to load from user config directory first
 	templatePath := config.GetTemplateFile()
 
@@ -33,48 +50,139 @@ func loadTemplateTask() *taskpkg.Task {
 	if err != nil {
 		if os.IsNotExist(err) {
 			slog.Debug("new.md not found in user config dir, using embedded template", "path", templatePath)
-			return loadEmbeddedTemplate()
+		} else {
+			slog.Warn("failed to read new.md template", "path", templatePath, "error", err)
 		}
-		slog.Warn("failed to read new.md template", "path", templatePath, "error", err)
-		return loadEmbeddedTemplate()
+		return []byte(config.GetDefaultNewTaskTemplate()), nil
 	}
+	return data, nil
+}
 
-	return parseTaskTemplate(data)
+// templateData holds the values of a template's placeholders
+type templateData struct {
+	ID     string
+	User   string
+	Email  string
+	Branch string
+	Date   string // creation date, YYYY-MM-DD
 }
 
-// loadEmbeddedTemplate loads the embedded config/new.md template
-func loadEmbeddedTemplate() *taskpkg.Task {
-	templateStr := config.GetDefaultNewTaskTemplate()
-	if templateStr == "" {
-		return nil
+// expandTemplate fills in the placeholders of a template: {{.ID}}, {{.User}}, {{.Email}},
+// {{.Branch}}, {{.Date}}, and {{prompt "Field"}} with the value given for Field.
+// Templates without placeholders come back unchanged.
+func expandTemplate(name string, source []byte, data templateData, values map[string]string) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"prompt": func(field string) string { return values[field] },
+	}).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("parse template %q: %w", name, err)
 	}
-	return parseTaskTemplate([]byte(templateStr))
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("expand template %q: %w", name, err)
+	}
+	return buf.Bytes(), nil
 }
 
-// parseTaskTemplate parses task template data from markdown with YAML frontmatter
-func parseTaskTemplate(data []byte) *taskpkg.Task {
-	content := strings.TrimSpace(string(data))
+// expandTaskTemplate returns the task a template describes with its placeholders filled
+// in, or nil when it has no frontmatter. Placeholders in the frontmatter expand to tokens
+// that are replaced once the YAML is parsed, so a value with quotes or newlines can't
+// break it.
+func expandTaskTemplate(name string, source []byte, data templateData, values map[string]string) (*taskpkg.Task, error) {
+	frontmatter, body, ok := splitTemplate(string(source))
+	if !ok {
+		return nil, nil
+	}
+
+	var replacements []string
+	token := func(value string) string {
+		t := fmt.Sprintf("tikiplaceholder%dx", len(replacements)/2)
+		replacements = append(replacements, t, value)
+		return t
+	}
+	tokenData := templateData{ID: token(data.ID), User: token(data.User), Email: token(data.Email),
+		Branch: token(data.Branch), Date: token(data.Date)}
+	tokenValues := make(map[string]string, len(values))
+	for field, value := range values {
+		tokenValues[field] = token(value)
+	}
+	expanded, err := expandTemplate(name, []byte(frontmatter), tokenData, tokenValues)
+	if err != nil {
+		return nil, err
+	}
+	var fm templateFrontmatter
+	if err := yaml.Unmarshal(expanded, &fm); err != nil {
+		return nil, fmt.Errorf("template %q: invalid frontmatter: %w", name, err)
+	}
+	r := strings.NewReplacer(replacements...)
+	fm.Title, fm.Type, fm.Status, fm.Assignee = r.Replace(fm.Title), r.Replace(fm.Type), r.Replace(fm.Status), r.Replace(fm.Assignee)
+	for i, tag := range fm.Tags {
+		fm.Tags[i] = r.Replace(tag)
+	}
+
+	description, err := expandTemplate(name, []byte(body), data, values)
+	if err != nil {
+		return nil, err
+	}
+	return fm.task(string(description)), nil
+}
+
+// templatePrompts returns the fields a template asks for with {{prompt "Field"}}, in order
+func templatePrompts(name string, source []byte) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"prompt": func(field string) string {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+			return ""
+		},
+	}).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("parse template %q: %w", name, err)
+	}
+	if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+		return nil, fmt.Errorf("expand template %q: %w", name, err)
+	}
+	return fields, nil
+}
+
+// splitTemplate splits a template into its YAML frontmatter and its body
+func splitTemplate(content string) (frontmatter, body string, ok bool) {
+	content = strings.TrimSpace(content)
 	if !strings.HasPrefix(content, "---") {
-		return nil
+		return "", "", false
 	}
 
 	rest := content[3:]
 	idx := strings.Index(rest, "\n---")
 	if idx == -1 {
-		return nil
+		return "", "", false
 	}
+	return strings.TrimSpace(rest[:idx]), strings.TrimSpace(strings.TrimPrefix(rest[idx+4:], "\n")), true
+}
 
-	frontmatter := strings.TrimSpace(rest[:idx])
-	body := strings.TrimSpace(strings.TrimPrefix(rest[idx+4:], "\n"))
+// parseTaskTemplate parses task template data from markdown with YAML frontmatter, as is
+func parseTaskTemplate(data []byte) *taskpkg.Task {
+	frontmatter, body, ok := splitTemplate(string(data))
+	if !ok {
+		return nil
+	}
 
 	var fm templateFrontmatter
 	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
 		return nil
 	}
+	return fm.task(body)
+}
 
+// task returns the task the frontmatter describes, with body as its description
+func (fm templateFrontmatter) task(body string) *taskpkg.Task {
 	return &taskpkg.Task{
 		Title:       fm.Title,
-		Description: body,
+		Description: strings.TrimSpace(body),
 		Type:        taskpkg.NormalizeType(fm.Type),
 		Status:      taskpkg.NormalizeStatus(fm.Status),
 		Tags:        fm.Tags,
@@ -84,24 +192,24 @@ func parseTaskTemplate(data []byte) *taskpkg.Task {
 	}
 }
 
-// setAuthorFromGit best-effort populates CreatedBy using current git user.
-func (s *TikiStore) setAuthorFromGit(task *taskpkg.Task) {
-	if task == nil || task.CreatedBy != "" {
-		return
+// gitAuthor best-effort returns the current git user as a task author: "name <email>",
+// or whichever of the two git knows, empty without git.
+func gitAuthor(gitUtil git.GitOps) string {
+	if gitUtil == nil {
+		return ""
 	}
-
-	name, email, err := s.GetCurrentUser()
+	name, email, err := gitUtil.CurrentUser()
 	if err != nil {
-		return
+		return ""
 	}
 
 	switch {
 	case name != "" && email != "":
-		task.CreatedBy = fmt.Sprintf("%s <%s>", name, email)
+		return fmt.Sprintf("%s <%s>", name, email)
 	case name != "":
-		task.CreatedBy = name
-	case email != "":
-		task.CreatedBy = email
+		return name
+	default:
+		return email
 	}
 }
 
@@ -109,6 +217,34 @@ func (s *TikiStore) setAuthorFromGit(task *taskpkg.Task) {
 // The task will have all fields from the template (priority, type, tags, etc.)
 // plus generated ID and git author.
 func (s *TikiStore) NewTaskTemplate() (*taskpkg.Task, error) {
+	return s.NewTaskFromTemplate("", nil)
+}
+
+// TemplatePrompts returns the fields the named template (new.md when empty) asks for
+// on creation, in the order they appear.
+func (s *TikiStore) TemplatePrompts(name string) ([]string, error) {
+	source, err := loadTemplateSource(name)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := templatePrompts(templateLabel(name), source)
+	if err != nil && name == "" {
+		// new.md predates placeholders and is used verbatim when they don't parse
+		slog.Warn("failed to parse new.md placeholders, using it verbatim", "error", err)
+		return nil, nil
+	}
+	return fields, err
+}
+
+// NewTaskFromTemplate returns a new task populated with the defaults of a named template,
+// or of new.md when name is empty, with its placeholders filled in. values answers the
+// template's prompts. Fields the template leaves out keep the defaults.
+func (s *TikiStore) NewTaskFromTemplate(name string, values map[string]string) (*taskpkg.Task, error) {
+	source, err := loadTemplateSource(name)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -126,10 +262,27 @@ func (s *TikiStore) NewTaskTemplate() (*taskpkg.Task, error) {
 		slog.Debug("ID collision detected during template creation, regenerating", "id", taskID)
 	}
 
-	taskID = normalizeTaskID(taskID)
+	return newTaskFromTemplate(name, source, values, normalizeTaskID(taskID), s.gitUtil)
+}
+
+// newTaskFromTemplate returns a new task with ID taskID from a template's source, with its
+// placeholders filled in. Fields the template leaves out keep the defaults.
+func newTaskFromTemplate(name string, source []byte, values map[string]string, taskID string, gitUtil git.GitOps) (*taskpkg.Task, error) {
+	now := time.Now()
 
 	// Load template (with defaults)
-	template := loadTemplateTask()
+	defaults, err := expandTaskTemplate(templateLabel(name), source, newTemplateData(gitUtil, taskID, now), values)
+	if err != nil {
+		if name != "" {
+			return nil, err
+		}
+		// new.md predates placeholders: one that doesn't expand is copied verbatim
+		slog.Warn("failed to expand new.md, using it verbatim", "error", err)
+		defaults = parseTaskTemplate(source)
+	}
+	if defaults == nil && name != "" {
+		return nil, fmt.Errorf("template %q: missing or invalid frontmatter", name)
+	}
 
 	// Create base task with defaults
 	task := &taskpkg.Task{
@@ -140,33 +293,60 @@ func (s *TikiStore) NewTaskTemplate() (*taskpkg.Task, error) {
 		Type:        taskpkg.TypeStory,     // default fallback
 		Priority:    3,                     // default: medium priority (1-5 scale)
 		Points:      0,
-		CreatedAt:   time.Now(),
+		CreatedAt:   now,
 	}
 
 	// Apply template values if available
-	if template != nil {
-		task.Title = template.Title
-		task.Description = template.Description
-		task.Type = template.Type
-		task.Priority = template.Priority
-		task.Points = template.Points
-		task.Tags = template.Tags
-		task.Assignee = template.Assignee
-		task.Status = template.Status
+	if defaults != nil {
+		applyTemplate(task, defaults)
 	}
 
-	// Ensure type has a value (fallback if template didn't provide)
-	if task.Type == "" {
-		task.Type = taskpkg.TypeStory
-	}
+	// Set git author
+	task.CreatedBy = gitAuthor(gitUtil)
 
-	// Ensure status has a value
-	if task.Status == "" {
-		task.Status = taskpkg.StatusBacklog
+	return task, nil
+}
+
+// newTemplateData gathers the placeholder values for a new task: git user and branch
+// are best effort and left empty when git can't tell
+func newTemplateData(gitUtil git.GitOps, taskID string, now time.Time) templateData {
+	data := templateData{ID: taskID, Date: now.Format("2006-01-02")}
+	if gitUtil == nil {
+		return data
+	}
+	if name, email, err := gitUtil.CurrentUser(); err == nil {
+		data.User, data.Email = name, email
 	}
+	if branch, err := gitUtil.CurrentBranch(); err == nil {
+		data.Branch = branch
+	}
+	return data
+}
 
-	// Set git author
-	s.setAuthorFromGit(task)
+// templateLabel names a template in errors
+func templateLabel(name string) string {
+	if name == "" {
+		return "new.md"
+	}
+	return name
+}
 
-	return task, nil
+// applyTemplate copies the fields a template sets onto task
+func applyTemplate(task, tmpl *taskpkg.Task) {
+	task.Title = tmpl.Title
+	task.Description = tmpl.Description
+	task.Tags = tmpl.Tags
+	task.Assignee = tmpl.Assignee
+	if tmpl.Type != "" {
+		task.Type = tmpl.Type
+	}
+	if tmpl.Status != "" {
+		task.Status = tmpl.Status
+	}
+	if tmpl.Priority != 0 {
+		task.Priority = tmpl.Priority
+	}
+	if tmpl.Points != 0 {
+		task.Points = tmpl.Points
+	}
 }
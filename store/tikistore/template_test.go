@@ -0,0 +1,151 @@
+package tikistore
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/boolean-maybe/tiki/config"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+func TestNewTaskFromTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	templates := filepath.Join(tmpDir, ".doc", "templates")
+	if err := os.MkdirAll(templates, 0755); err != nil {
+		t.Fatal(err)
+	}
+	bug := "---\ntype: bug\npriority: 2\ntags:\n  - bug\n---\n## Steps to reproduce\n"
+	if err := os.WriteFile(filepath.Join(templates, "bug.md"), []byte(bug), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templates, "broken.md"), []byte("no frontmatter"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	incident := "---\ntitle: \"Incident: {{prompt \"Service\"}}\"\n---\n{{.ID}} opened {{.Date}}, severity {{prompt \"Severity\"}} ({{prompt \"Service\"}})\n"
+	if err := os.WriteFile(filepath.Join(templates, "incident.md"), []byte(incident), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	defer config.ResetPathManager()
+
+	store, err := NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+
+	task, err := store.NewTaskFromTemplate("bug", nil)
+	if err != nil {
+		t.Fatalf("NewTaskFromTemplate(bug): %v", err)
+	}
+	if task.ID == "" {
+		t.Error("expected a generated ID")
+	}
+	if task.Type != taskpkg.TypeBug || task.Priority != 2 {
+		t.Errorf("template defaults not applied: type %q priority %d", task.Type, task.Priority)
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"bug"}) {
+		t.Errorf("tags = %v, want [bug]", task.Tags)
+	}
+	if task.Description != "## Steps to reproduce" {
+		t.Errorf("description = %q", task.Description)
+	}
+	// left out of the template: the defaults stay
+	if task.Status != taskpkg.StatusBacklog {
+		t.Errorf("status = %q, want backlog", task.Status)
+	}
+
+	fields, err := store.TemplatePrompts("incident")
+	if err != nil {
+		t.Fatalf("TemplatePrompts(incident): %v", err)
+	}
+	if !reflect.DeepEqual(fields, []string{"Service", "Severity"}) {
+		t.Errorf("prompt fields = %v, want [Service Severity]", fields)
+	}
+	task, err = store.NewTaskFromTemplate("incident", map[string]string{"Service": "api", "Severity": "high"})
+	if err != nil {
+		t.Fatalf("NewTaskFromTemplate(incident): %v", err)
+	}
+	if task.Title != "Incident: api" {
+		t.Errorf("title = %q, want %q", task.Title, "Incident: api")
+	}
+	if want := task.ID + " opened " + task.CreatedAt.Format("2006-01-02") + ", severity high (api)"; task.Description != want {
+		t.Errorf("description = %q, want %q", task.Description, want)
+	}
+
+	// answers are values, not YAML: quotes and newlines stay in the title
+	task, err = store.NewTaskFromTemplate("incident", map[string]string{"Service": "\"api\"\ngateway: down", "Severity": "high"})
+	if err != nil {
+		t.Fatalf("NewTaskFromTemplate(incident) with quotes: %v", err)
+	}
+	if task.Title != "Incident: \"api\"\ngateway: down" {
+		t.Errorf("title = %q", task.Title)
+	}
+
+	if _, err := store.NewTaskFromTemplate("missing", nil); err == nil {
+		t.Error("expected error for an unknown template")
+	}
+	if _, err := store.NewTaskFromTemplate("broken", nil); err == nil {
+		t.Error("expected error for a template without frontmatter")
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	data := templateData{ID: "TIKI-ABC123", User: "Ann", Email: "ann@example.com", Branch: "fix/login", Date: "2026-10-18"}
+	source := "---\nassignee: {{.User}}\n---\nReported by {{.User}} <{{.Email}}> on {{.Date}} from {{.Branch}}\n"
+	got, err := expandTemplate("bug", []byte(source), data, nil)
+	if err != nil {
+		t.Fatalf("expandTemplate: %v", err)
+	}
+	want := "---\nassignee: Ann\n---\nReported by Ann <ann@example.com> on 2026-10-18 from fix/login\n"
+	if string(got) != want {
+		t.Errorf("expandTemplate = %q, want %q", got, want)
+	}
+
+	if _, err := expandTemplate("bad", []byte("{{.Nope}}"), data, nil); err == nil {
+		t.Error("expected error for an unknown placeholder")
+	}
+	if _, err := expandTemplate("bad", []byte("{{.User"), data, nil); err == nil {
+		t.Error("expected error for an unterminated placeholder")
+	}
+}
+
+func TestNewTaskFromVerbatimNewMD(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	_ = os.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	config.ResetPathManager()
+	defer config.ResetPathManager()
+
+	// written before placeholders existed: the braces are text
+	newMD := "---\ntype: bug\n---\nPaste the {{payload here\n"
+	if err := os.MkdirAll(filepath.Dir(config.GetTemplateFile()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.GetTemplateFile(), []byte(newMD), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewTikiStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewTikiStore: %v", err)
+	}
+	if fields, err := store.TemplatePrompts(""); err != nil || len(fields) != 0 {
+		t.Errorf("TemplatePrompts(new.md) = %v, %v; want no prompts", fields, err)
+	}
+	task, err := store.NewTaskTemplate()
+	if err != nil {
+		t.Fatalf("NewTaskTemplate: %v", err)
+	}
+	if task.Type != taskpkg.TypeBug || task.Description != "Paste the {{payload here" {
+		t.Errorf("new.md not copied verbatim: type %q description %q", task.Type, task.Description)
+	}
+}
@@ -0,0 +1,405 @@
+package tikistore
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+// WorkspaceStore shows the tasks of several repositories on one board. Task IDs carry the
+// name of their repository, api:TIKI-ABC123, and every change is written by the TikiStore
+// of that repository, with its own git. New tasks go to the first repository.
+type WorkspaceStore struct {
+	repos []workspaceRepo
+
+	mu             sync.RWMutex
+	listeners      map[int]store.ChangeListener
+	nextListenerID int
+}
+
+type workspaceRepo struct {
+	name  string
+	root  string
+	store *TikiStore
+}
+
+// NewWorkspaceStore opens the TikiStore of every repository
+func NewWorkspaceStore(repos []config.WorkspaceRepo) (*WorkspaceStore, error) {
+	if len(repos) == 0 {
+		return nil, errors.New("no workspace repositories")
+	}
+	w := &WorkspaceStore{
+		listeners:      make(map[int]store.ChangeListener),
+		nextListenerID: 1,
+	}
+	for _, repo := range repos {
+		s, err := NewRepoTikiStore(repo.Path)
+		if err != nil {
+			return nil, fmt.Errorf("workspace repo %s: %w", repo.Name, err)
+		}
+		// children notify after releasing their locks, so forwarding is safe
+		s.AddListener(w.notifyListeners)
+		w.repos = append(w.repos, workspaceRepo{name: repo.Name, root: repo.Path, store: s})
+	}
+	return w, nil
+}
+
+// Repos returns the store of every repository, in configured order
+func (w *WorkspaceStore) Repos() []*TikiStore {
+	stores := make([]*TikiStore, len(w.repos))
+	for i, r := range w.repos {
+		stores[i] = r.store
+	}
+	return stores
+}
+
+// AddListener registers a callback for change notifications in any repository.
+// returns a listener ID that can be used to remove the listener.
+func (w *WorkspaceStore) AddListener(listener store.ChangeListener) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextListenerID
+	w.nextListenerID++
+	w.listeners[id] = listener
+	return id
+}
+
+// RemoveListener removes a previously registered listener by ID
+func (w *WorkspaceStore) RemoveListener(id int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.listeners, id)
+}
+
+func (w *WorkspaceStore) notifyListeners() {
+	w.mu.RLock()
+	listeners := make([]store.ChangeListener, 0, len(w.listeners))
+	for _, l := range w.listeners {
+		listeners = append(listeners, l)
+	}
+	w.mu.RUnlock()
+
+	for _, l := range listeners {
+		l()
+	}
+}
+
+// resolve finds the repository of a namespaced ID and returns the ID within it. An ID
+// without a repository resolves when exactly one repository has the task, so references
+// such as TIKI-ABC123 in a description still lead somewhere.
+func (w *WorkspaceStore) resolve(id string) (*workspaceRepo, string) {
+	if name, rawID, ok := strings.Cut(strings.TrimSpace(id), ":"); ok {
+		for i := range w.repos {
+			if strings.EqualFold(w.repos[i].name, name) {
+				return &w.repos[i], normalizeTaskID(rawID)
+			}
+		}
+		return nil, ""
+	}
+
+	var found *workspaceRepo
+	for i := range w.repos {
+		if w.repos[i].store.GetTask(id) != nil {
+			if found != nil {
+				return nil, "" // ambiguous
+			}
+			found = &w.repos[i]
+		}
+	}
+	return found, normalizeTaskID(id)
+}
+
+// namespaced returns a copy of a repository's task with the repository in its ID
+func (r *workspaceRepo) namespaced(t *taskpkg.Task) *taskpkg.Task {
+	if t == nil {
+		return nil
+	}
+	clone := t.Clone()
+	clone.ID = r.name + ":" + t.ID
+	return clone
+}
+
+// local returns a copy of a task with its ID within the repository
+func local(t *taskpkg.Task, rawID string) *taskpkg.Task {
+	clone := t.Clone()
+	clone.ID = rawID
+	return clone
+}
+
+// saved copies what a save changed back to the caller's task
+func (r *workspaceRepo) saved(t, saved *taskpkg.Task) {
+	t.ID = r.name + ":" + saved.ID
+	t.LoadedMtime = saved.LoadedMtime
+	t.UpdatedAt = saved.UpdatedAt
+}
+
+// CreateTask adds a new task to the repository its ID names, or to the first one
+func (w *WorkspaceStore) CreateTask(task *taskpkg.Task) error {
+	repo, rawID := &w.repos[0], ""
+	if task.ID != "" {
+		if _, _, namespaced := strings.Cut(task.ID, ":"); namespaced {
+			if repo, rawID = w.resolve(task.ID); repo == nil {
+				return fmt.Errorf("no workspace repo for task %s", task.ID)
+			}
+		} else {
+			rawID = normalizeTaskID(task.ID)
+		}
+	}
+
+	created := local(task, rawID)
+	if err := repo.store.CreateTask(created); err != nil {
+		return err
+	}
+	repo.saved(task, created)
+	return nil
+}
+
+// GetTask retrieves a task by its namespaced ID
+func (w *WorkspaceStore) GetTask(id string) *taskpkg.Task {
+	repo, rawID := w.resolve(id)
+	if repo == nil {
+		return nil
+	}
+	return repo.namespaced(repo.store.GetTask(rawID))
+}
+
+// UpdateTask saves a task in its repository.
+// Returns ErrConflict when its file changed on disk since it was loaded.
+func (w *WorkspaceStore) UpdateTask(task *taskpkg.Task) error {
+	repo, rawID := w.resolve(task.ID)
+	if repo == nil {
+		return fmt.Errorf("task not found: %s", task.ID)
+	}
+	updated := local(task, rawID)
+	if err := repo.store.UpdateTask(updated); err != nil {
+		return err
+	}
+	repo.saved(task, updated)
+	return nil
+}
+
+// DeleteTask removes a task and its file from its repository
+func (w *WorkspaceStore) DeleteTask(id string) {
+	if repo, rawID := w.resolve(id); repo != nil {
+		repo.store.DeleteTask(rawID)
+	}
+}
+
+// GetAllTasks returns the tasks of all repositories, sorted by priority then title
+func (w *WorkspaceStore) GetAllTasks() []*taskpkg.Task {
+	var tasks []*taskpkg.Task
+	for i := range w.repos {
+		for _, t := range w.repos[i].store.GetAllTasks() {
+			tasks = append(tasks, w.repos[i].namespaced(t))
+		}
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+// Search searches the tasks of all repositories, see TikiStore.Search. filterFunc sees
+// namespaced tasks.
+func (w *WorkspaceStore) Search(query string, filterFunc func(*taskpkg.Task) bool) []taskpkg.SearchResult {
+	var tasks []*taskpkg.Task
+	scores := make(map[string]float64)
+	for i := range w.repos {
+		repo := &w.repos[i]
+		var repoFilter func(*taskpkg.Task) bool
+		if filterFunc != nil {
+			repoFilter = func(t *taskpkg.Task) bool { return filterFunc(repo.namespaced(t)) }
+		}
+		for _, r := range repo.store.Search(query, repoFilter) {
+			t := repo.namespaced(r.Task)
+			tasks = append(tasks, t)
+			scores[t.ID] = r.Score
+		}
+	}
+
+	sortTasks(tasks)
+	results := make([]taskpkg.SearchResult, len(tasks))
+	for i, t := range tasks {
+		results[i] = taskpkg.SearchResult{Task: t, Score: scores[t.ID]}
+	}
+	return results
+}
+
+// AddComment adds a comment to a task
+func (w *WorkspaceStore) AddComment(taskID string, comment taskpkg.Comment) bool {
+	repo, rawID := w.resolve(taskID)
+	return repo != nil && repo.store.AddComment(rawID, comment)
+}
+
+// Reload reloads the tasks of every repository
+func (w *WorkspaceStore) Reload() error {
+	var errs []error
+	for _, r := range w.repos {
+		if err := r.store.Reload(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadTask reloads a single task from its repository
+func (w *WorkspaceStore) ReloadTask(taskID string) error {
+	repo, rawID := w.resolve(taskID)
+	if repo == nil {
+		return fmt.Errorf("task not found: %s", taskID)
+	}
+	return repo.store.ReloadTask(rawID)
+}
+
+// TaskFilePath returns the markdown file of a task in its repository
+func (w *WorkspaceStore) TaskFilePath(id string) string {
+	repo, rawID := w.resolve(id)
+	if repo == nil {
+		return w.repos[0].store.TaskFilePath(id)
+	}
+	return repo.store.TaskFilePath(rawID)
+}
+
+// GetCurrentUser returns the git user of the first repository
+func (w *WorkspaceStore) GetCurrentUser() (name string, email string, err error) {
+	return w.repos[0].store.GetCurrentUser()
+}
+
+// GetStats returns statistics for the header: the user and the workspace repositories
+func (w *WorkspaceStore) GetStats() []store.Stat {
+	stats := make([]store.Stat, 0, 2)
+	for _, stat := range w.repos[0].store.GetStats() {
+		if stat.Name == "User" {
+			stats = append(stats, stat)
+		}
+	}
+	names := make([]string, len(w.repos))
+	for i, r := range w.repos {
+		names[i] = r.name
+	}
+	return append(stats, store.Stat{Name: "Repos", Value: strings.Join(names, ", "), Order: 4})
+}
+
+// GetBurndown returns the remaining work of all repositories together
+func (w *WorkspaceStore) GetBurndown() []store.BurndownPoint {
+	series := make([][]store.BurndownPoint, 0, len(w.repos))
+	for _, r := range w.repos {
+		series = append(series, r.store.GetBurndown())
+	}
+	return sumBurndowns(series)
+}
+
+// sumBurndowns adds up burndowns point by point. Repositories share the configured window,
+// so their points fall on the same dates.
+func sumBurndowns(series [][]store.BurndownPoint) []store.BurndownPoint {
+	var sum []store.BurndownPoint
+	index := make(map[int64]int)
+	for _, points := range series {
+		for _, p := range points {
+			if i, ok := index[p.Date.Unix()]; ok {
+				sum[i].Remaining += p.Remaining
+				continue
+			}
+			index[p.Date.Unix()] = len(sum)
+			sum = append(sum, p)
+		}
+	}
+	sort.Slice(sum, func(i, j int) bool { return sum[i].Date.Before(sum[j].Date) })
+	return sum
+}
+
+// GetStatusTransitions returns the status history of the tasks of every repository
+func (w *WorkspaceStore) GetStatusTransitions() (map[string][]store.StatusChange, error) {
+	transitions := make(map[string][]store.StatusChange)
+	for _, r := range w.repos {
+		repoTransitions, err := r.store.GetStatusTransitions()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		for id, changes := range repoTransitions {
+			namespacedID := r.name + ":" + id
+			for i := range changes {
+				changes[i].TaskID = namespacedID
+			}
+			transitions[namespacedID] = changes
+		}
+	}
+	return transitions, nil
+}
+
+// GetTaskTimeline returns the committed revisions of a task file, oldest first
+func (w *WorkspaceStore) GetTaskTimeline(taskID string) ([]store.TaskRevision, error) {
+	repo, rawID := w.resolve(taskID)
+	if repo == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+	return repo.store.GetTaskTimeline(rawID)
+}
+
+// GetAllUsers returns the git users of all repositories
+func (w *WorkspaceStore) GetAllUsers() ([]string, error) {
+	var users []string
+	seen := make(map[string]bool)
+	for _, r := range w.repos {
+		repoUsers, err := r.store.GetAllUsers()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		for _, u := range repoUsers {
+			if !seen[u] {
+				seen[u] = true
+				users = append(users, u)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// repoFor returns the repository a file is in, the first one for files outside all of them
+func (w *WorkspaceStore) repoFor(path string) *TikiStore {
+	for _, r := range w.repos {
+		if rel, err := filepath.Rel(r.root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return r.store
+		}
+	}
+	return w.repos[0].store
+}
+
+// FileAtRef returns the content of a repository file as of a branch, tag or commit
+func (w *WorkspaceStore) FileAtRef(ref string, filePath string) (string, error) {
+	return w.repoFor(filePath).FileAtRef(ref, filePath)
+}
+
+// StageFile adds a file, such as a doki page, to the git index of its repository
+func (w *WorkspaceStore) StageFile(path string) error {
+	return w.repoFor(path).StageFile(path)
+}
+
+// NewTaskTemplate returns a new task for the first repository populated with template defaults
+func (w *WorkspaceStore) NewTaskTemplate() (*taskpkg.Task, error) {
+	return w.NewTaskFromTemplate("", nil)
+}
+
+// TemplatePrompts returns the fields the named template asks for on creation
+func (w *WorkspaceStore) TemplatePrompts(name string) ([]string, error) {
+	return w.repos[0].store.TemplatePrompts(name)
+}
+
+// NewTaskFromTemplate is TikiStore.NewTaskFromTemplate for the first repository
+func (w *WorkspaceStore) NewTaskFromTemplate(name string, values map[string]string) (*taskpkg.Task, error) {
+	task, err := w.repos[0].store.NewTaskFromTemplate(name, values)
+	if err != nil {
+		return nil, err
+	}
+	task.ID = w.repos[0].name + ":" + task.ID
+	return task, nil
+}
+
+// ensure WorkspaceStore implements Store
+var _ store.Store = (*WorkspaceStore)(nil)
@@ -0,0 +1,157 @@
+package tikistore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+	taskpkg "github.com/boolean-maybe/tiki/task"
+)
+
+// newWorkspaceRepo returns a repository root in root/name holding the given task files
+func newWorkspaceRepo(t *testing.T, root, name string, files map[string]string) config.WorkspaceRepo {
+	t.Helper()
+	taskDir := filepath.Join(root, name, ".doc", "tiki")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(taskDir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return config.WorkspaceRepo{Name: name, Path: filepath.Join(root, name)}
+}
+
+func TestWorkspaceStore(t *testing.T) {
+	newMultiDirProject(t, nil, nil)
+	root := t.TempDir()
+	api := newWorkspaceRepo(t, root, "api", map[string]string{
+		"tiki-aaa111.md": taskFile("Api task", "ready"),
+		"tiki-dup000.md": taskFile("Api copy", "ready"),
+	})
+	web := newWorkspaceRepo(t, root, "web", map[string]string{
+		"tiki-bbb222.md": taskFile("Web task", "ready"),
+		"tiki-dup000.md": taskFile("Web copy", "ready"),
+	})
+
+	w, err := NewWorkspaceStore([]config.WorkspaceRepo{api, web})
+	if err != nil {
+		t.Fatalf("NewWorkspaceStore: %v", err)
+	}
+
+	if got := len(w.GetAllTasks()); got != 4 {
+		t.Fatalf("GetAllTasks() returned %d tasks, want 4", got)
+	}
+	if task := w.GetTask("web:TIKI-DUP000"); task == nil || task.Title != "Web copy" || task.ID != "web:TIKI-DUP000" {
+		t.Errorf("GetTask(web:TIKI-DUP000) = %+v", task)
+	}
+	if task := w.GetTask("API:tiki-dup000"); task == nil || task.Title != "Api copy" {
+		t.Errorf("repository names should match case-insensitively, got %+v", task)
+	}
+	if task := w.GetTask("TIKI-BBB222"); task == nil || task.ID != "web:TIKI-BBB222" {
+		t.Errorf("an unprefixed ID one repository has should resolve, got %+v", task)
+	}
+	if task := w.GetTask("TIKI-DUP000"); task != nil {
+		t.Errorf("an unprefixed ID two repositories have is ambiguous, got %+v", task)
+	}
+	if task := w.GetTask("docs:TIKI-AAA111"); task != nil {
+		t.Errorf("unknown repository should not resolve, got %+v", task)
+	}
+
+	notified := 0
+	w.AddListener(func() { notified++ })
+
+	// a task is saved in its own repository
+	task := w.GetTask("web:TIKI-BBB222")
+	task.Title = "Web task, renamed"
+	if err := w.UpdateTask(task); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	if task.ID != "web:TIKI-BBB222" {
+		t.Errorf("UpdateTask changed the ID to %s", task.ID)
+	}
+	webFile := filepath.Join(web.Path, ".doc", "tiki", "tiki-bbb222.md")
+	content, err := os.ReadFile(webFile)
+	if err != nil || !strings.Contains(string(content), "Web task, renamed") {
+		t.Errorf("task not saved in its repository: %q, %v", content, err)
+	}
+	if got := w.TaskFilePath("web:TIKI-BBB222"); got != webFile {
+		t.Errorf("TaskFilePath = %s", got)
+	}
+
+	// new tasks go to the first repository, or to the one their ID names
+	created := &taskpkg.Task{Title: "New", Type: taskpkg.TypeStory, Status: taskpkg.StatusBacklog, Priority: 3}
+	if err := w.CreateTask(created); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	rawID, ok := strings.CutPrefix(created.ID, "api:")
+	if !ok {
+		t.Fatalf("new task ID = %s, want it in the api repository", created.ID)
+	}
+	if _, err := os.Stat(filepath.Join(api.Path, ".doc", "tiki", strings.ToLower(rawID)+".md")); err != nil {
+		t.Errorf("new task not in the first repository: %v", err)
+	}
+	if err := w.CreateTask(&taskpkg.Task{ID: "web:TIKI-CCC333", Title: "Web new", Type: taskpkg.TypeStory, Status: taskpkg.StatusBacklog, Priority: 3}); err != nil {
+		t.Fatalf("CreateTask(web:TIKI-CCC333): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(web.Path, ".doc", "tiki", "tiki-ccc333.md")); err != nil {
+		t.Errorf("prefixed task not in its repository: %v", err)
+	}
+	if err := w.CreateTask(&taskpkg.Task{ID: "docs:TIKI-DDD444", Title: "Nowhere"}); err == nil {
+		t.Errorf("creating a task in an unknown repository should fail")
+	}
+
+	if notified != 3 {
+		t.Errorf("listeners notified %d times, want 3", notified)
+	}
+
+	// a file changed on disk since it was loaded is a conflict
+	stale := w.GetTask("web:TIKI-BBB222")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(webFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.UpdateTask(stale); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("UpdateTask after an external change = %v, want ErrConflict", err)
+	}
+
+	w.DeleteTask("web:TIKI-BBB222")
+	if _, err := os.Stat(webFile); !os.IsNotExist(err) {
+		t.Errorf("DeleteTask should remove the file from its repository")
+	}
+}
+
+func TestNewWorkspaceStoreNotAProject(t *testing.T) {
+	newMultiDirProject(t, nil, nil)
+	root := t.TempDir()
+	api := newWorkspaceRepo(t, root, "api", nil)
+	missing := config.WorkspaceRepo{Name: "web", Path: filepath.Join(root, "web")}
+
+	_, err := NewWorkspaceStore([]config.WorkspaceRepo{api, missing})
+	if err == nil || !strings.Contains(err.Error(), "not a tiki project") {
+		t.Errorf("NewWorkspaceStore with a repository without .doc/tiki = %v", err)
+	}
+}
+
+func TestSumBurndowns(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	sum := sumBurndowns([][]store.BurndownPoint{
+		{{Date: day(2), Remaining: 5}, {Date: day(3), Remaining: 4}},
+		{{Date: day(1), Remaining: 1}, {Date: day(2), Remaining: 2}},
+	})
+	want := []store.BurndownPoint{{Date: day(1), Remaining: 1}, {Date: day(2), Remaining: 7}, {Date: day(3), Remaining: 4}}
+	if len(sum) != len(want) {
+		t.Fatalf("sumBurndowns returned %d points, want %d", len(sum), len(want))
+	}
+	for i := range want {
+		if !sum[i].Date.Equal(want[i].Date) || sum[i].Remaining != want[i].Remaining {
+			t.Errorf("point %d = %+v, want %+v", i, sum[i], want[i])
+		}
+	}
+}
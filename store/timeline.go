@@ -0,0 +1,150 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store/internal/git"
+	"github.com/boolean-maybe/tiki/task"
+
+	"gopkg.in/yaml.v3"
+)
+
+// timelineFields lists the frontmatter fields tracked by the task timeline, in display order.
+var timelineFields = []string{"title", "type", "status", "priority", "points", "assignee", "tags"}
+
+// FieldChange describes a single frontmatter field that changed between two revisions.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// TaskRevision is one committed version of a task file with the changes it introduced.
+type TaskRevision struct {
+	Commit             string
+	Author             string
+	Email              string
+	When               time.Time
+	Fields             map[string]string
+	Description        string
+	Changes            []FieldChange
+	DescriptionChanged bool
+	Unreadable         string // why the file couldn't be parsed; empty for a readable revision
+}
+
+// BuildTaskTimeline converts file versions (oldest first) into revisions with field-level diffs.
+// The first revision reports every non-empty field as a change from "". A version that doesn't
+// parse is kept as an unreadable revision, and the next one is compared with the one before it.
+func BuildTaskTimeline(versions []git.FileVersion) ([]TaskRevision, error) {
+	revisions := make([]TaskRevision, 0, len(versions))
+	var prev *TaskRevision
+
+	for _, version := range versions {
+		fields, description, err := parseRevisionContent(version.Content)
+		if err != nil {
+			revisions = append(revisions, TaskRevision{
+				Commit:     version.Hash,
+				Author:     version.Author,
+				Email:      version.Email,
+				When:       version.When,
+				Unreadable: err.Error(),
+			})
+			continue
+		}
+
+		rev := TaskRevision{
+			Commit:      version.Hash,
+			Author:      version.Author,
+			Email:       version.Email,
+			When:        version.When,
+			Fields:      fields,
+			Description: description,
+		}
+
+		var prevFields map[string]string
+		prevDescription := ""
+		if prev != nil {
+			prevFields = prev.Fields
+			prevDescription = prev.Description
+		}
+		for _, name := range timelineFields {
+			from := prevFields[name]
+			to := fields[name]
+			if from != to {
+				rev.Changes = append(rev.Changes, FieldChange{Field: name, From: from, To: to})
+			}
+		}
+		rev.DescriptionChanged = description != prevDescription
+
+		// skip commits that touched the file without changing tracked content
+		if prev != nil && len(rev.Changes) == 0 && !rev.DescriptionChanged {
+			continue
+		}
+
+		revisions = append(revisions, rev)
+		prev = &rev
+	}
+
+	return revisions, nil
+}
+
+// parseRevisionContent extracts normalized field values and the description from a task file.
+func parseRevisionContent(content string) (map[string]string, string, error) {
+	frontmatter, body, err := ParseFrontmatter(content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fields := make(map[string]string, len(timelineFields))
+	if frontmatter == "" {
+		return fields, strings.TrimSpace(body), nil
+	}
+
+	var fm map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return nil, "", err
+	}
+
+	for _, name := range timelineFields {
+		raw, ok := fm[name]
+		if !ok || raw == nil {
+			continue
+		}
+		fields[name] = formatTimelineValue(name, raw)
+	}
+
+	return fields, strings.TrimSpace(body), nil
+}
+
+// formatTimelineValue renders a raw frontmatter value as a comparable display string.
+func formatTimelineValue(field string, raw interface{}) string {
+	switch field {
+	case "status":
+		if s, ok := raw.(string); ok && s != "" {
+			return task.StatusLabel(task.MapStatus(s))
+		}
+	case "tags":
+		switch v := raw.(type) {
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				parts = append(parts, strings.TrimSpace(fmt.Sprint(item)))
+			}
+			return strings.Join(parts, ", ")
+		case string:
+			return strings.TrimSpace(v)
+		}
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/store/internal/git"
+)
+
+func TestBuildTaskTimeline(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	versions := []git.FileVersion{
+		{
+			Hash:   "aaaaaaa1",
+			Author: "alice",
+			When:   base,
+			Content: `---
+title: Fix login
+status: backlog
+priority: 3
+---
+Initial description`,
+		},
+		{
+			Hash:   "bbbbbbb2",
+			Author: "bob",
+			When:   base.Add(time.Hour),
+			Content: `---
+title: Fix login
+status: in_progress
+priority: 1
+assignee: bob
+---
+Initial description`,
+		},
+		{
+			// whitespace-only change: not a revision
+			Hash:   "ccccccc3",
+			Author: "bob",
+			When:   base.Add(2 * time.Hour),
+			Content: `---
+title: Fix login
+status: in_progress
+priority: 1
+assignee: bob
+---
+Initial description
+`,
+		},
+		{
+			Hash:   "ddddddd4",
+			Author: "carol",
+			When:   base.Add(3 * time.Hour),
+			Content: `---
+title: Fix login
+status: done
+priority: 1
+assignee: bob
+---
+Updated description`,
+		},
+	}
+
+	revisions, err := BuildTaskTimeline(versions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(revisions))
+	}
+
+	if revisions[0].Commit != "aaaaaaa1" || len(revisions[0].Changes) != 3 {
+		t.Errorf("first revision should introduce title, status, priority: %+v", revisions[0].Changes)
+	}
+
+	second := revisions[1]
+	expected := []FieldChange{
+		{Field: "status", From: "Backlog", To: "In Progress"},
+		{Field: "priority", From: "3", To: "1"},
+		{Field: "assignee", From: "", To: "bob"},
+	}
+	if len(second.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), second.Changes)
+	}
+	for i, want := range expected {
+		if second.Changes[i] != want {
+			t.Errorf("change %d: want %+v, got %+v", i, want, second.Changes[i])
+		}
+	}
+	if second.DescriptionChanged {
+		t.Error("second revision should not report a description change")
+	}
+
+	third := revisions[2]
+	if third.Author != "carol" || !third.DescriptionChanged {
+		t.Errorf("unexpected third revision: %+v", third)
+	}
+	if third.Description != "Updated description" {
+		t.Errorf("expected description at commit, got %q", third.Description)
+	}
+}
+
+func TestBuildTaskTimelineKeepsUnreadableRevision(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	versions := []git.FileVersion{
+		{Hash: "aaaaaaa1", Author: "alice", When: base, Content: "---\ntitle: Fix login\nstatus: backlog\n---\nFirst"},
+		{Hash: "bbbbbbb2", Author: "bob", When: base.Add(time.Hour), Content: "---\ntitle: [unclosed\n---\nBroken"},
+		{Hash: "ccccccc3", Author: "carol", When: base.Add(2 * time.Hour), Content: "---\ntitle: Fix login\nstatus: done\n---\nFirst"},
+	}
+
+	revisions, err := BuildTaskTimeline(versions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(revisions))
+	}
+	if broken := revisions[1]; broken.Unreadable == "" || broken.Author != "bob" || len(broken.Changes) != 0 {
+		t.Errorf("corrupt revision should be kept as unreadable: %+v", broken)
+	}
+	// the revision after it is compared with the last readable one
+	want := []FieldChange{{Field: "status", From: "Backlog", To: "Done"}}
+	if got := revisions[2].Changes; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("changes after the unreadable revision = %+v, want %+v", got, want)
+	}
+	if revisions[2].DescriptionChanged || revisions[0].Unreadable != "" || revisions[2].Unreadable != "" {
+		t.Errorf("readable revisions misreported: %+v", revisions)
+	}
+}
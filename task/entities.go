@@ -14,7 +14,9 @@ type Task struct {
 	Priority    int // lower = higher priority
 	Points      int
 	Comments    []Comment
-	CreatedBy   string // User who initially created the task
+	ExternalID  string            // ID in the tracker the task was imported from, e.g. "github:owner/repo#42"
+	Fields      map[string]string // custom fields, e.g. an imported milestone
+	CreatedBy   string            // User who initially created the task
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	LoadedMtime time.Time // File mtime when loaded (for optimistic locking)
@@ -35,6 +37,7 @@ func (t *Task) Clone() *Task {
 		Priority:    t.Priority,
 		Assignee:    t.Assignee,
 		Points:      t.Points,
+		ExternalID:  t.ExternalID,
 		CreatedBy:   t.CreatedBy,
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
@@ -47,6 +50,13 @@ func (t *Task) Clone() *Task {
 		copy(clone.Tags, t.Tags)
 	}
 
+	if t.Fields != nil {
+		clone.Fields = make(map[string]string, len(t.Fields))
+		for k, v := range t.Fields {
+			clone.Fields[k] = v
+		}
+	}
+
 	if t.Comments != nil {
 		clone.Comments = make([]Comment, len(t.Comments))
 		copy(clone.Comments, t.Comments)
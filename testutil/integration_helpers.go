@@ -313,7 +313,11 @@ func (ta *TestApp) LoadPlugins() error {
 			)
 		} else if dp, ok := p.(*plugin.DokiPlugin); ok {
 			pluginControllers[p.GetName()] = controller.NewDokiController(
-				dp, ta.NavController,
+				dp, ta.NavController, ta.TaskStore,
+			)
+		} else if cp, ok := p.(*plugin.ChartPlugin); ok {
+			pluginControllers[p.GetName()] = controller.NewChartController(
+				cp, ta.NavController,
 			)
 		}
 	}
@@ -0,0 +1,174 @@
+package diagram
+
+import "strings"
+
+// line directions leaving a cell; crossing and joining lines merge into one box character
+const (
+	lineUp uint8 = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+var lineRunes = map[uint8]rune{
+	lineUp:                                   '│',
+	lineDown:                                 '│',
+	lineUp | lineDown:                        '│',
+	lineLeft:                                 '─',
+	lineRight:                                '─',
+	lineLeft | lineRight:                     '─',
+	lineDown | lineRight:                     '┌',
+	lineDown | lineLeft:                      '┐',
+	lineUp | lineRight:                       '└',
+	lineUp | lineLeft:                        '┘',
+	lineUp | lineDown | lineRight:            '├',
+	lineUp | lineDown | lineLeft:             '┤',
+	lineLeft | lineRight | lineDown:          '┬',
+	lineLeft | lineRight | lineUp:            '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+// canvas is a character grid that grows as it is drawn on. Lines are kept as
+// directions per cell so that they join cleanly; runes placed with set win over lines.
+type canvas struct {
+	runes [][]rune
+	lines [][]uint8
+}
+
+func (c *canvas) ensure(x, y int) {
+	for len(c.runes) <= y {
+		c.runes = append(c.runes, nil)
+		c.lines = append(c.lines, nil)
+	}
+	for len(c.runes[y]) <= x {
+		c.runes[y] = append(c.runes[y], 0)
+		c.lines[y] = append(c.lines[y], 0)
+	}
+}
+
+func (c *canvas) set(x, y int, r rune) {
+	if x < 0 || y < 0 {
+		return
+	}
+	c.ensure(x, y)
+	c.runes[y][x] = r
+}
+
+func (c *canvas) get(x, y int) rune {
+	if y < 0 || y >= len(c.runes) || x < 0 || x >= len(c.runes[y]) {
+		return 0
+	}
+	return c.runes[y][x]
+}
+
+func (c *canvas) addLine(x, y int, dirs uint8) {
+	if x < 0 || y < 0 {
+		return
+	}
+	c.ensure(x, y)
+	c.lines[y][x] |= dirs
+}
+
+// text writes s starting at x
+func (c *canvas) text(x, y int, s string) {
+	for _, r := range s {
+		c.set(x, y, r)
+		x++
+	}
+}
+
+// hline draws a horizontal line between two columns, inclusive
+func (c *canvas) hline(y, x1, x2 int) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x <= x2; x++ {
+		var dirs uint8
+		if x > x1 {
+			dirs |= lineLeft
+		}
+		if x < x2 {
+			dirs |= lineRight
+		}
+		if x1 == x2 {
+			dirs = lineLeft | lineRight
+		}
+		c.addLine(x, y, dirs)
+	}
+}
+
+// vline draws a vertical line between two rows, inclusive
+func (c *canvas) vline(x, y1, y2 int) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y <= y2; y++ {
+		var dirs uint8
+		if y > y1 {
+			dirs |= lineUp
+		}
+		if y < y2 {
+			dirs |= lineDown
+		}
+		if y1 == y2 {
+			dirs = lineUp | lineDown
+		}
+		c.addLine(x, y, dirs)
+	}
+}
+
+// box draws a w by h frame with its top left corner at x, y
+func (c *canvas) box(x, y, w, h int, corners [4]rune) {
+	c.hline(y, x, x+w-1)
+	c.hline(y+h-1, x, x+w-1)
+	c.vline(x, y, y+h-1)
+	c.vline(x+w-1, y, y+h-1)
+	c.set(x, y, corners[0])
+	c.set(x+w-1, y, corners[1])
+	c.set(x, y+h-1, corners[2])
+	c.set(x+w-1, y+h-1, corners[3])
+}
+
+// frame draws a box with fixed characters that hides any lines under its border
+func (c *canvas) frame(x, y, w, h int, corners [4]rune) {
+	for col := x + 1; col < x+w-1; col++ {
+		c.set(col, y, '─')
+		c.set(col, y+h-1, '─')
+	}
+	for row := y + 1; row < y+h-1; row++ {
+		c.set(x, row, '│')
+		c.set(x+w-1, row, '│')
+	}
+	c.set(x, y, corners[0])
+	c.set(x+w-1, y, corners[1])
+	c.set(x, y+h-1, corners[2])
+	c.set(x+w-1, y+h-1, corners[3])
+}
+
+// fill blanks a rectangle so that lines drawn before don't show through
+func (c *canvas) fill(x, y, w, h int) {
+	for row := y; row < y+h; row++ {
+		for col := x; col < x+w; col++ {
+			c.set(col, row, ' ')
+		}
+	}
+}
+
+func (c *canvas) String() string {
+	lines := make([]string, len(c.runes))
+	for y, row := range c.runes {
+		var sb strings.Builder
+		for x, r := range row {
+			switch {
+			case r != 0:
+				sb.WriteRune(r)
+			case c.lines[y][x] != 0:
+				sb.WriteRune(lineRunes[c.lines[y][x]])
+			default:
+				sb.WriteByte(' ')
+			}
+		}
+		lines[y] = strings.TrimRight(sb.String(), " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,129 @@
+// Package diagram renders simple mermaid and graphviz diagrams as Unicode box art,
+// so that diagram code blocks are readable in the terminal.
+package diagram
+
+import (
+	"strings"
+)
+
+// maxNodes keeps layout time and output size reasonable; larger diagrams stay as source
+const maxNodes = 80
+
+// Render draws a diagram written in lang ("mermaid", "dot" or "graphviz"). It returns
+// false for languages and diagram types it doesn't support, or source it can't parse.
+func Render(lang, source string) (string, bool) {
+	switch strings.ToLower(lang) {
+	case "mermaid":
+		return renderMermaid(source)
+	case "dot", "graphviz":
+		g, ok := parseDot(source)
+		if !ok {
+			return "", false
+		}
+		return g.render()
+	default:
+		return "", false
+	}
+}
+
+func renderMermaid(source string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		header := strings.TrimSpace(line)
+		if header == "" || strings.HasPrefix(header, "%%") {
+			continue
+		}
+		fields := strings.Fields(header)
+		switch fields[0] {
+		case "graph", "flowchart":
+			g, ok := parseFlowchart(fields[1:], lines[i+1:])
+			if !ok {
+				return "", false
+			}
+			return g.render()
+		case "sequenceDiagram":
+			return renderSequence(lines[i+1:])
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// RenderMarkdown replaces fenced mermaid, dot and graphviz blocks in markdown with plain
+// code blocks holding their box art. Blocks that can't be drawn are left as they are.
+func RenderMarkdown(markdown string) string {
+	if !strings.Contains(markdown, "mermaid") && !strings.Contains(markdown, "dot") && !strings.Contains(markdown, "graphviz") {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		fence, lang := openingFence(lines[i])
+		if fence == "" {
+			out = append(out, lines[i])
+			continue
+		}
+
+		end := closingFence(lines, i+1, fence)
+		if end < 0 {
+			out = append(out, lines[i:]...)
+			break
+		}
+		if art, ok := Render(lang, strings.Join(lines[i+1:end], "\n")); ok {
+			out = append(out, fence)
+			out = append(out, strings.Split(art, "\n")...)
+			out = append(out, fence)
+		} else {
+			out = append(out, lines[i:end+1]...)
+		}
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+// openingFence returns the fence and language of a line opening a code block, or "" if
+// it isn't one. Only blocks not indented as code themselves are considered.
+func openingFence(line string) (string, string) {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return "", ""
+	}
+	trimmed := line[indent:]
+	marker := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`~"))]
+	if len(marker) < 3 || strings.Trim(marker, marker[:1]) != "" {
+		return "", ""
+	}
+	info := strings.Fields(trimmed[len(marker):])
+	if len(info) == 0 {
+		return marker, ""
+	}
+	return marker, info[0]
+}
+
+// closingFence returns the line closing a block opened with fence, or -1
+func closingFence(lines []string, from int, fence string) int {
+	for i := from; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// textWidth is the number of cells a label takes
+func textWidth(s string) int {
+	return len([]rune(s))
+}
+
+// labelLines splits a node label on line breaks written as <br> or \n
+func labelLines(label string) []string {
+	replacer := strings.NewReplacer("<br/>", "\n", "<br />", "\n", "<br>", "\n", `\n`, "\n")
+	lines := strings.Split(replacer.Replace(label), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return lines
+}
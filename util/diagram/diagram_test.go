@@ -0,0 +1,101 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFlowchart(t *testing.T) {
+	out, ok := Render("mermaid", "graph TD\n  A[Start] --> B{Ready?}\n  B -->|yes| C[Ship]\n  B -- no --> D[Fix]\n  D --> B")
+	if !ok {
+		t.Fatal("flowchart not rendered")
+	}
+	for _, want := range []string{"│ Start │", "Ready?", "Ship", "│ Fix │", "yes", "no", "▼", "▲"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	lines := strings.Split(out, "\n")
+	row := func(label string) int {
+		for i, line := range lines {
+			if strings.Contains(line, label) {
+				return i
+			}
+		}
+		return -1
+	}
+	if !(row("Start") < row("Ready?") && row("Ready?") < row("Ship")) {
+		t.Errorf("top-down flow out of order:\n%s", out)
+	}
+}
+
+func TestRenderFlowchartLeftToRight(t *testing.T) {
+	out, ok := Render("mermaid", "flowchart LR\n  A[Backlog] --> B[Ready] --> C[Done]")
+	if !ok {
+		t.Fatal("flowchart not rendered")
+	}
+	line := strings.Split(out, "\n")[1]
+	if !(strings.Index(line, "Backlog") < strings.Index(line, "Ready") && strings.Index(line, "Ready") < strings.Index(line, "Done")) {
+		t.Errorf("left-to-right flow should keep nodes on one row:\n%s", out)
+	}
+	if strings.Count(out, "▶") != 2 {
+		t.Errorf("expected 2 arrow heads:\n%s", out)
+	}
+}
+
+func TestRenderSequence(t *testing.T) {
+	out, ok := Render("mermaid", "sequenceDiagram\n  participant U as User\n  U->>T: create\n  T-->>U: id\n  Note right of T: saved\n  loop retry\n  T->>T: poll\n  end")
+	if !ok {
+		t.Fatal("sequence diagram not rendered")
+	}
+	for _, want := range []string{"│ User │", "│ T │", "create", "▶", "◀╌", "│ saved │", "[loop retry]", "poll"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderDot(t *testing.T) {
+	out, ok := Render("dot", "digraph {\n  rankdir=LR\n  a -> b [label=\"go\"]\n  b [label=\"Next\", shape=box]\n}")
+	if !ok {
+		t.Fatal("graphviz not rendered")
+	}
+	for _, want := range []string{"│ a ├", "Next", "go ▶"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	for _, src := range []string{"classDiagram\n  A <|-- B", "graph TD\n  A --> ??", "pie\n  \"a\": 1"} {
+		if _, ok := Render("mermaid", src); ok {
+			t.Errorf("Render(%q) should fall back to the source", src)
+		}
+	}
+	if _, ok := Render("go", "package main"); ok {
+		t.Error("other languages should not render")
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	md := "# Plan\n\n```mermaid\ngraph LR\n  A --> B\n```\n\n```mermaid\ngantt\n  title x\n```\n\n    ```mermaid\n    graph LR\n    ```\n"
+	out := RenderMarkdown(md)
+
+	if strings.Contains(out, "A --> B") {
+		t.Errorf("flowchart source should be replaced:\n%s", out)
+	}
+	if !strings.Contains(out, "```\n┌───┐") {
+		t.Errorf("diagram should become a plain code block:\n%s", out)
+	}
+	if !strings.Contains(out, "```mermaid\ngantt\n  title x\n```") {
+		t.Errorf("unsupported diagram should be kept as is:\n%s", out)
+	}
+	if !strings.Contains(out, "    ```mermaid\n    graph LR\n    ```") {
+		t.Errorf("indented code should be left alone:\n%s", out)
+	}
+	if RenderMarkdown("no diagrams") != "no diagrams" {
+		t.Error("markdown without diagrams should be unchanged")
+	}
+}
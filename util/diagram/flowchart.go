@@ -0,0 +1,366 @@
+package diagram
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// node shapes
+const (
+	shapeBox = iota
+	shapeRound
+	shapeDecision
+)
+
+type node struct {
+	id    string
+	label string
+	shape int
+
+	dummy bool // bend point of an edge spanning several layers
+	layer int
+	order int
+	pos   float64 // barycenter while ordering
+
+	x, y, w, h int
+}
+
+type edge struct {
+	from, to  *node
+	label     string
+	arrow     bool // arrow head at to
+	backArrow bool // arrow head at from
+}
+
+// graph is a flowchart: nodes, edges between them and the direction of flow
+type graph struct {
+	dir   string // "TD", "BT", "LR" or "RL"
+	nodes []*node
+	byID  map[string]*node
+	edges []*edge
+}
+
+func newGraph(dir string) *graph {
+	g := &graph{byID: make(map[string]*node)}
+	g.setDirection(dir)
+	return g
+}
+
+func (g *graph) setDirection(dir string) {
+	switch strings.ToUpper(dir) {
+	case "LR", "RL", "BT":
+		g.dir = strings.ToUpper(dir)
+	default:
+		g.dir = "TD"
+	}
+}
+
+// node returns the node with an id, adding it on first use
+func (g *graph) node(id string) *node {
+	if n, ok := g.byID[id]; ok {
+		return n
+	}
+	n := &node{id: id, label: id}
+	g.byID[id] = n
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+var (
+	// "-- text -->" style links; the text form must be tried first
+	textLinkPattern = regexp.MustCompile(`^\s*(<?)(--|==|-\.)\s+([^|]+?)\s+(-{2,}|={2,}|\.+-)([>xo]?)(\s|$)`)
+	// "-->", "---", "-.->", "==>" and friends with an optional |label|
+	linkPattern = regexp.MustCompile(`^\s*(<?)(-{2,}|={2,}|-\.+-)([>xo]?)(\s*\|([^|]*)\|)?`)
+	// flowchart statements that only style the chart
+	ignoredStatement = regexp.MustCompile(`^(classDef|class|style|linkStyle|click|subgraph|end|direction)\b`)
+)
+
+// parseFlowchart reads a mermaid flowchart: header holds the words after "graph"
+func parseFlowchart(header []string, lines []string) (*graph, bool) {
+	dir := "TD"
+	if len(header) > 0 {
+		dir = header[0]
+	}
+	g := newGraph(dir)
+
+	for _, line := range lines {
+		for _, stmt := range splitStatements(line) {
+			stmt = strings.TrimSpace(stmt)
+			if stmt == "" || strings.HasPrefix(stmt, "%%") {
+				continue
+			}
+			if ignoredStatement.MatchString(stmt) {
+				if fields := strings.Fields(stmt); fields[0] == "direction" && len(fields) > 1 && len(g.edges) == 0 {
+					g.setDirection(fields[1])
+				}
+				continue
+			}
+			if !g.parseStatement(stmt) {
+				return nil, false
+			}
+		}
+	}
+	return g, len(g.nodes) > 0 && len(g.nodes) <= maxNodes
+}
+
+// splitStatements splits a line on semicolons outside of node labels
+func splitStatements(line string) []string {
+	var parts []string
+	depth, start := 0, 0
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case strings.ContainsRune("[({", r):
+			depth++
+		case strings.ContainsRune("])}", r):
+			depth--
+		case r == ';' && depth <= 0:
+			parts = append(parts, line[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, line[start:])
+}
+
+// parseStatement reads "A[x] --> B & C -->|y| D"
+func (g *graph) parseStatement(stmt string) bool {
+	rest := stmt
+	group, rest, ok := g.parseNodeGroup(rest)
+	if !ok {
+		return false
+	}
+	for strings.TrimSpace(rest) != "" {
+		var label, head, tail string
+		if m := textLinkPattern.FindStringSubmatch(rest); m != nil {
+			tail, label, head = m[1], m[3], m[5]
+			rest = rest[len(m[0])-len(m[6]):]
+		} else if m := linkPattern.FindStringSubmatch(rest); m != nil {
+			tail, head, label = m[1], m[3], m[5]
+			rest = rest[len(m[0]):]
+		} else {
+			return false
+		}
+
+		var next []*node
+		next, rest, ok = g.parseNodeGroup(rest)
+		if !ok {
+			return false
+		}
+		for _, from := range group {
+			for _, to := range next {
+				g.edges = append(g.edges, &edge{
+					from:      from,
+					to:        to,
+					label:     strings.Trim(strings.TrimSpace(label), `"`),
+					arrow:     head != "",
+					backArrow: tail != "",
+				})
+			}
+		}
+		group = next
+	}
+	return true
+}
+
+// parseNodeGroup reads nodes joined with &
+func (g *graph) parseNodeGroup(s string) ([]*node, string, bool) {
+	var group []*node
+	for {
+		n, rest, ok := g.parseNode(s)
+		if !ok {
+			return nil, s, false
+		}
+		group = append(group, n)
+		trimmed := strings.TrimLeft(rest, " \t")
+		if !strings.HasPrefix(trimmed, "&") {
+			return group, rest, true
+		}
+		s = trimmed[1:]
+	}
+}
+
+// node shape brackets: opening sequence -> closing sequence and shape
+var shapeBrackets = []struct {
+	open, close string
+	shape       int
+}{
+	{"(((", ")))", shapeRound},
+	{"((", "))", shapeRound},
+	{"([", "])", shapeRound},
+	{"[(", ")]", shapeRound},
+	{"[[", "]]", shapeBox},
+	{"[/", "/]", shapeBox},
+	{"[\\", "\\]", shapeBox},
+	{"[/", "\\]", shapeBox},
+	{"[\\", "/]", shapeBox},
+	{"{{", "}}", shapeDecision},
+	{"[", "]", shapeBox},
+	{"(", ")", shapeRound},
+	{"{", "}", shapeDecision},
+	{">", "]", shapeBox},
+}
+
+// parseNode reads a node id with an optional shape and label, e.g. A{Ready?}
+func (g *graph) parseNode(s string) (*node, string, bool) {
+	s = strings.TrimLeft(s, " \t")
+	end := 0
+	for end < len(s) {
+		r := rune(s[end])
+		if r >= 0x80 {
+			// let multi-byte letters through; ids are compared as written
+			end++
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+		end++
+	}
+	if end == 0 {
+		return nil, s, false
+	}
+	n := g.node(s[:end])
+	rest := s[end:]
+
+	for _, b := range shapeBrackets {
+		if !strings.HasPrefix(rest, b.open) {
+			continue
+		}
+		body := rest[len(b.open):]
+		var label string
+		if strings.HasPrefix(body, `"`) {
+			closeQuote := strings.Index(body[1:], `"`)
+			if closeQuote < 0 {
+				return nil, s, false
+			}
+			label = body[1 : closeQuote+1]
+			body = body[closeQuote+2:]
+			if !strings.HasPrefix(body, b.close) {
+				continue
+			}
+			rest = body[len(b.close):]
+		} else {
+			closing := strings.Index(body, b.close)
+			if closing < 0 {
+				continue
+			}
+			label = body[:closing]
+			rest = body[closing+len(b.close):]
+		}
+		n.label = strings.TrimSpace(label)
+		n.shape = b.shape
+		break
+	}
+
+	// class shorthand: A:::important
+	if strings.HasPrefix(rest, ":::") {
+		rest = strings.TrimLeftFunc(rest[3:], func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+		})
+	}
+	return n, rest, true
+}
+
+var (
+	dotHeader    = regexp.MustCompile(`^\s*(strict\s+)?(di)?graph\b[^{]*\{`)
+	dotAttribute = regexp.MustCompile(`(\w+)\s*=\s*("(?:[^"\\]|\\.)*"|[^,\s\]]+)`)
+	dotEdgeOp    = regexp.MustCompile(`\s*(->|--)\s*`)
+)
+
+// parseDot reads a graphviz graph with node and edge statements. Attributes other
+// than label, shape and rankdir are ignored, and so is subgraph structure.
+func parseDot(source string) (*graph, bool) {
+	loc := dotHeader.FindStringSubmatchIndex(source)
+	if loc == nil {
+		return nil, false
+	}
+	directed := loc[4] >= 0
+	body := source[loc[1]:]
+	if last := strings.LastIndex(body, "}"); last >= 0 {
+		body = body[:last]
+	}
+
+	g := newGraph("TD")
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		for _, stmt := range splitStatements(line) {
+			if !g.parseDotStatement(strings.TrimSpace(stmt), directed) {
+				return nil, false
+			}
+		}
+	}
+	return g, len(g.nodes) > 0 && len(g.nodes) <= maxNodes
+}
+
+func (g *graph) parseDotStatement(stmt string, directed bool) bool {
+	stmt = strings.Trim(stmt, "{} \t")
+	if stmt == "" || strings.HasPrefix(stmt, "#") || strings.HasPrefix(stmt, "subgraph") {
+		return true
+	}
+
+	attrs := map[string]string{}
+	if open := strings.Index(stmt, "["); open >= 0 {
+		for _, m := range dotAttribute.FindAllStringSubmatch(stmt[open:], -1) {
+			attrs[strings.ToLower(m[1])] = unquoteDot(m[2])
+		}
+		stmt = strings.TrimSpace(stmt[:open])
+	}
+
+	if key, value, ok := strings.Cut(stmt, "="); ok && !strings.Contains(stmt, "-") {
+		if strings.EqualFold(strings.TrimSpace(key), "rankdir") {
+			g.setDirection(unquoteDot(strings.TrimSpace(value)))
+		}
+		return true
+	}
+	switch stmt {
+	case "graph", "node", "edge":
+		if rankdir, ok := attrs["rankdir"]; ok && stmt == "graph" {
+			g.setDirection(rankdir)
+		}
+		return true
+	}
+
+	ids := dotEdgeOp.Split(stmt, -1)
+	var prev *node
+	for _, id := range ids {
+		id = unquoteDot(strings.TrimSpace(id))
+		if id == "" {
+			return false
+		}
+		if _, seen := g.byID[id]; !seen {
+			// graphviz draws ellipses unless told otherwise
+			g.node(id).shape = shapeRound
+		}
+		n := g.node(id)
+		if len(ids) == 1 {
+			if label, ok := attrs["label"]; ok {
+				n.label = label
+			}
+			switch attrs["shape"] {
+			case "diamond":
+				n.shape = shapeDecision
+			case "box", "rect", "rectangle", "square":
+				n.shape = shapeBox
+			}
+		}
+		if prev != nil {
+			g.edges = append(g.edges, &edge{from: prev, to: n, label: attrs["label"], arrow: directed})
+		}
+		prev = n
+	}
+	return true
+}
+
+func unquoteDot(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `\"`, `"`)
+	}
+	return s
+}
@@ -0,0 +1,535 @@
+package diagram
+
+import (
+	"sort"
+	"strings"
+)
+
+// segment joins nodes on adjacent layers; an edge spanning several layers is drawn
+// as a chain of segments through dummy nodes
+type segment struct {
+	upper, lower           *node
+	arrowUpper, arrowLower bool
+	back                   bool // part of an edge pointing against the flow, drawn beside the others
+}
+
+// layout is a graph arranged in layers, top to bottom or left to right as drawn
+type layout struct {
+	layers   [][]*node
+	segments []*segment
+	up, down map[*node][]*node
+	// edge labels, drawn next to the end of the edge with the arrow head
+	labelAbove map[*node]string // on edges entering from the previous layer
+	labelBelow map[*node]string // on edges entering from the next layer
+
+	at, span map[*node]int // position along the layer, and room taken including labels
+}
+
+// render lays the graph out and draws it
+func (g *graph) render() (string, bool) {
+	l := g.arrange()
+	var c canvas
+	if g.dir == "LR" || g.dir == "RL" {
+		l.drawHorizontal(&c)
+	} else {
+		l.drawVertical(&c)
+	}
+	return c.String(), true
+}
+
+// arrange assigns layers along the longest path, breaking cycles, then inserts dummy
+// nodes and orders each layer to reduce crossings
+func (g *graph) arrange() *layout {
+	g.mergeOpposites()
+	reversed := g.breakCycles()
+	flipped := g.dir == "BT" || g.dir == "RL"
+
+	layer := make(map[*node]int, len(g.nodes))
+	for range g.nodes {
+		changed := false
+		for _, e := range g.edges {
+			if e.from == e.to {
+				continue
+			}
+			from, to := e.from, e.to
+			if reversed[e] {
+				from, to = to, from
+			}
+			if layer[to] < layer[from]+1 {
+				layer[to] = layer[from] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	maxLayer := 0
+	for _, n := range g.nodes {
+		n.layer = layer[n]
+		maxLayer = max(maxLayer, n.layer)
+	}
+	if g.dir == "BT" || g.dir == "RL" {
+		for _, n := range g.nodes {
+			n.layer = maxLayer - n.layer
+		}
+	}
+
+	l := &layout{
+		layers:     make([][]*node, maxLayer+1),
+		up:         make(map[*node][]*node),
+		down:       make(map[*node][]*node),
+		labelAbove: make(map[*node]string),
+		labelBelow: make(map[*node]string),
+		at:         make(map[*node]int),
+		span:       make(map[*node]int),
+	}
+	for _, n := range g.nodes {
+		l.layers[n.layer] = append(l.layers[n.layer], n)
+	}
+	for _, e := range g.edges {
+		if e.from != e.to {
+			l.addEdge(e, reversed[e], flipped)
+		}
+	}
+	l.order()
+	return l
+}
+
+// mergeOpposites turns pairs of edges running both ways between two nodes into
+// one edge with two arrow heads, so they don't overlap
+func (g *graph) mergeOpposites() {
+	kept := g.edges[:0]
+	for _, e := range g.edges {
+		merged := false
+		for _, f := range kept {
+			if f.from == e.to && f.to == e.from && e.from != e.to {
+				f.backArrow = f.backArrow || e.arrow
+				f.arrow = f.arrow || e.backArrow
+				if e.label != "" {
+					f.label = joinLabel(f.label, e.label)
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			kept = append(kept, e)
+		}
+	}
+	g.edges = kept
+}
+
+// breakCycles returns the edges that point back to a node being visited
+func (g *graph) breakCycles() map[*edge]bool {
+	out := make(map[*node][]*edge)
+	for _, e := range g.edges {
+		out[e.from] = append(out[e.from], e)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[*node]int)
+	reversed := make(map[*edge]bool)
+	var visit func(n *node)
+	visit = func(n *node) {
+		state[n] = visiting
+		for _, e := range out[n] {
+			switch state[e.to] {
+			case visiting:
+				reversed[e] = true
+			case unvisited:
+				visit(e.to)
+			}
+		}
+		state[n] = done
+	}
+	for _, n := range g.nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+	return reversed
+}
+
+// addEdge adds the segments of an edge, with a dummy node on every layer it crosses
+// The edge is drawn beside others when it closes a cycle or has a head pointing against
+// the flow, which runs down the layers, or up them when flipped.
+func (l *layout) addEdge(e *edge, reversed, flipped bool) {
+	a, b := e.from, e.to
+	if a.layer > b.layer {
+		a, b = b, a
+	}
+	arrowB := (e.to == b && e.arrow) || (e.from == b && e.backArrow)
+	arrowA := (e.to == a && e.arrow) || (e.from == a && e.backArrow)
+	back := reversed || (!flipped && arrowA) || (flipped && arrowB)
+
+	chain := []*node{a}
+	for layer := a.layer + 1; layer < b.layer; layer++ {
+		dummy := &node{dummy: true, layer: layer}
+		l.layers[layer] = append(l.layers[layer], dummy)
+		chain = append(chain, dummy)
+	}
+	chain = append(chain, b)
+
+	for i := 0; i+1 < len(chain); i++ {
+		s := &segment{upper: chain[i], lower: chain[i+1], back: back}
+		s.arrowUpper = i == 0 && arrowA
+		s.arrowLower = i+2 == len(chain) && arrowB
+		l.segments = append(l.segments, s)
+		l.down[s.upper] = append(l.down[s.upper], s.lower)
+		l.up[s.lower] = append(l.up[s.lower], s.upper)
+	}
+
+	if e.label == "" {
+		return
+	}
+	if arrowA && !arrowB {
+		l.labelBelow[a] = joinLabel(l.labelBelow[a], e.label)
+	} else {
+		l.labelAbove[b] = joinLabel(l.labelAbove[b], e.label)
+	}
+}
+
+func joinLabel(existing, label string) string {
+	if existing == "" {
+		return label
+	}
+	return existing + " / " + label
+}
+
+// order sorts each layer by the mean position of its neighbors, sweeping down and up
+func (l *layout) order() {
+	for _, layer := range l.layers {
+		for i, n := range layer {
+			n.order = i
+		}
+	}
+	sweep := func(i int, neighbors map[*node][]*node) {
+		layer := l.layers[i]
+		for _, n := range layer {
+			n.pos = float64(n.order)
+			if nb := neighbors[n]; len(nb) > 0 {
+				sum := 0
+				for _, m := range nb {
+					sum += m.order
+				}
+				n.pos = float64(sum) / float64(len(nb))
+			}
+		}
+		sort.SliceStable(layer, func(a, b int) bool { return layer[a].pos < layer[b].pos })
+		for j, n := range layer {
+			n.order = j
+		}
+	}
+	for range 4 {
+		for i := 1; i < len(l.layers); i++ {
+			sweep(i, l.up)
+		}
+		for i := len(l.layers) - 2; i >= 0; i-- {
+			sweep(i, l.down)
+		}
+	}
+}
+
+// place positions the nodes of every layer along it. size is a node's extent along the
+// layer; each node is centered on its neighbors in the previous (then the next) layer
+// where room allows, keeping the layer order and gap.
+func (l *layout) place(size func(*node) int, gap int) {
+	center := func(n *node) int { return l.at[n] + size(n)/2 }
+	placeLayer := func(layer []*node, neighbors map[*node][]*node) {
+		end, first := 0, true
+		for _, n := range layer {
+			start := end + gap
+			if nb := neighbors[n]; len(nb) > 0 {
+				sum := 0
+				for _, m := range nb {
+					sum += center(m)
+				}
+				desired := sum/len(nb) - size(n)/2
+				if first || desired > start {
+					start = desired
+				}
+			} else if first {
+				start = 0
+			}
+			l.at[n] = start
+			end = start + l.span[n]
+			first = false
+		}
+	}
+
+	for i := range l.layers {
+		placeLayer(l.layers[i], l.up)
+	}
+	for i := len(l.layers) - 2; i >= 0; i-- {
+		placeLayer(l.layers[i], l.down)
+	}
+	for i := 1; i < len(l.layers); i++ {
+		placeLayer(l.layers[i], l.up)
+	}
+
+	minAt, seen := 0, false
+	for _, at := range l.at {
+		if !seen || at < minAt {
+			minAt, seen = at, true
+		}
+	}
+	for n := range l.at {
+		l.at[n] -= minAt
+	}
+}
+
+// sizeNode sets the box size of a node from its label
+func sizeNode(n *node) {
+	lines := labelLines(n.label)
+	width := 0
+	for _, line := range lines {
+		width = max(width, textWidth(line))
+	}
+	n.w = width + 4
+	if n.w%2 == 0 {
+		// odd widths put the center on a column, so straight edges stay straight
+		n.w++
+	}
+	n.h = len(lines) + 2
+}
+
+var boxCorners = map[int][4]rune{
+	shapeBox:      {'┌', '┐', '└', '┘'},
+	shapeRound:    {'╭', '╮', '╰', '╯'},
+	shapeDecision: {'/', '\\', '\\', '/'},
+}
+
+// drawNode draws the box and centered label of a real node
+func drawNode(c *canvas, n *node) {
+	c.box(n.x, n.y, n.w, n.h, boxCorners[n.shape])
+	for i, line := range labelLines(n.label) {
+		c.fill(n.x+1, n.y+1+i, n.w-2, 1)
+		c.text(n.x+(n.w-textWidth(line))/2, n.y+1+i, line)
+	}
+}
+
+// drawVertical draws layers as rows, edges running down
+func (l *layout) drawVertical(c *canvas) {
+	rowHeight := make([]int, len(l.layers))
+	for i, layer := range l.layers {
+		rowHeight[i] = 1
+		for _, n := range layer {
+			if !n.dummy {
+				sizeNode(n)
+				rowHeight[i] = max(rowHeight[i], n.h)
+			}
+		}
+	}
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				n.w, n.h = 1, rowHeight[n.layer]
+				l.span[n] = 1
+				continue
+			}
+			labelRoom := max(textWidth(l.labelAbove[n]), textWidth(l.labelBelow[n]))
+			l.span[n] = n.w
+			if labelRoom > 0 {
+				l.span[n] = max(n.w, n.w/2+3+labelRoom)
+			}
+		}
+	}
+	l.place(func(n *node) int { return n.w }, 3)
+
+	for n, at := range l.at {
+		n.x = at
+	}
+
+	// rows: each gap holds a drop row, one row per bending segment and an arrow row
+	jogs := make([][]*segment, len(l.layers))
+	for _, s := range l.segments {
+		if attachX(s.upper, s) != attachX(s.lower, s) {
+			jogs[s.upper.layer] = append(jogs[s.upper.layer], s)
+		}
+	}
+	rowY := make([]int, len(l.layers))
+	for i := 1; i < len(l.layers); i++ {
+		rowY[i] = rowY[i-1] + rowHeight[i-1] + 2 + len(jogs[i-1])
+	}
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			n.y = rowY[n.layer]
+		}
+	}
+
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				c.vline(n.x, n.y, n.y+n.h-1)
+			}
+		}
+	}
+
+	jogRow := make(map[*segment]int)
+	for i, segs := range jogs {
+		for k, s := range segs {
+			jogRow[s] = rowY[i] + rowHeight[i] + 1 + k
+		}
+	}
+	for _, s := range l.segments {
+		u, w := s.upper, s.lower
+		cu, cl := attachX(u, s), attachX(w, s)
+		top, bottom := u.y+u.h-1, w.y
+		if s.arrowUpper {
+			top++
+		}
+		if s.arrowLower {
+			bottom--
+		}
+		if row, ok := jogRow[s]; ok {
+			c.vline(cu, top, row)
+			c.hline(row, cu, cl)
+			c.vline(cl, row, bottom)
+		} else {
+			c.vline(cu, top, bottom)
+		}
+		if s.arrowUpper {
+			c.set(cu, top, '▲')
+		}
+		if s.arrowLower {
+			c.set(cl, bottom, '▼')
+		}
+	}
+
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				continue
+			}
+			drawNode(c, n)
+			if label := l.labelAbove[n]; label != "" {
+				c.text(n.x+n.w/2+2, n.y-1, label)
+			}
+			if label := l.labelBelow[n]; label != "" {
+				c.text(n.x+n.w/2+2, n.y+n.h, label)
+			}
+		}
+	}
+}
+
+// attachX is the column where a segment meets a node in a vertical layout
+func attachX(n *node, s *segment) int {
+	x := n.x + n.w/2
+	if s.back && !n.dummy {
+		x -= 2
+	}
+	return x
+}
+
+// drawHorizontal draws layers as columns, edges running right
+func (l *layout) drawHorizontal(c *canvas) {
+	colWidth := make([]int, len(l.layers))
+	for i, layer := range l.layers {
+		colWidth[i] = 1
+		for _, n := range layer {
+			if !n.dummy {
+				sizeNode(n)
+				colWidth[i] = max(colWidth[i], n.w)
+			}
+		}
+	}
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				n.w, n.h = colWidth[n.layer], 1
+			}
+			l.span[n] = n.h
+		}
+	}
+	l.place(func(n *node) int { return n.h }, 1)
+
+	// columns: each gap holds an exit column, labels of edges pointing back,
+	// one column per bending segment, labels of edges pointing on and an arrow column
+	jogs := make([][]*segment, len(l.layers))
+	for _, s := range l.segments {
+		if l.at[s.upper]+s.upper.h/2 != l.at[s.lower]+s.lower.h/2 {
+			jogs[s.upper.layer] = append(jogs[s.upper.layer], s)
+		}
+	}
+	backLabels := make([]int, len(l.layers))
+	onLabels := make([]int, len(l.layers))
+	for i, layer := range l.layers {
+		for _, n := range layer {
+			if label := l.labelBelow[n]; label != "" {
+				backLabels[i] = max(backLabels[i], textWidth(label)+2)
+			}
+			if label := l.labelAbove[n]; label != "" && i > 0 {
+				onLabels[i-1] = max(onLabels[i-1], textWidth(label)+2)
+			}
+		}
+	}
+	colX := make([]int, len(l.layers))
+	for i := 1; i < len(l.layers); i++ {
+		colX[i] = colX[i-1] + colWidth[i-1] + 2 + backLabels[i-1] + len(jogs[i-1]) + onLabels[i-1]
+	}
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			n.y = l.at[n]
+			n.x = colX[n.layer] + (colWidth[n.layer]-n.w)/2
+		}
+	}
+
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				c.hline(n.y, n.x, n.x+n.w-1)
+			}
+		}
+	}
+
+	jogCol := make(map[*segment]int)
+	for i, segs := range jogs {
+		for k, s := range segs {
+			jogCol[s] = colX[i] + colWidth[i] + 1 + backLabels[i] + k
+		}
+	}
+	for _, s := range l.segments {
+		u, w := s.upper, s.lower
+		ru, rl := u.y+u.h/2, w.y+w.h/2
+		left, right := u.x+u.w-1, w.x
+		if s.arrowUpper {
+			left++
+		}
+		if s.arrowLower {
+			right--
+		}
+		if col, ok := jogCol[s]; ok {
+			c.hline(ru, left, col)
+			c.vline(col, ru, rl)
+			c.hline(rl, col, right)
+		} else {
+			c.hline(ru, left, right)
+		}
+		if s.arrowUpper {
+			c.set(left, ru, '◀')
+		}
+		if s.arrowLower {
+			c.set(right, rl, '▶')
+		}
+	}
+
+	for i, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				continue
+			}
+			drawNode(c, n)
+			if label := l.labelBelow[n]; label != "" {
+				c.text(colX[i]+colWidth[i]+1, n.y+n.h/2, " "+label+" ")
+			}
+			if label := l.labelAbove[n]; label != "" && i > 0 {
+				c.text(colX[i]-1-onLabels[i-1], n.y+n.h/2, " "+strings.TrimSpace(label)+" ")
+			}
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package diagram
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type participant struct {
+	id, label string
+	center    int
+	width     int
+}
+
+// sequence step kinds
+const (
+	stepMessage = iota
+	stepNote
+	stepBlock
+)
+
+type step struct {
+	kind     int
+	from, to int // participant indexes; a note spans from..to
+	text     string
+	dashed   bool
+	arrow    bool
+	side     string // "left of", "right of" or "over" for notes
+}
+
+var (
+	participantPattern = regexp.MustCompile(`^(participant|actor)\s+(.+?)(?:\s+as\s+(.+))?$`)
+	messagePattern     = regexp.MustCompile(`^(.+?)\s*(-->>|->>|-->|->|--x|-x|--\)|-\))\s*[+-]?\s*(.+?)\s*:\s*(.*)$`)
+	notePattern        = regexp.MustCompile(`^(?i:note)\s+(left of|right of|over)\s+([^:]+?)\s*:\s*(.*)$`)
+	blockPattern       = regexp.MustCompile(`^(loop|alt|else|opt|par|and|critical|option|break|rect|end)\b\s*(.*)$`)
+	ignoredSequence    = regexp.MustCompile(`^(autonumber|activate|deactivate|title|box|create|destroy|links?|properties|details)\b`)
+)
+
+// sequence is a parsed sequence diagram
+type sequence struct {
+	participants []*participant
+	byID         map[string]int
+	steps        []step
+}
+
+func (s *sequence) participant(id string) int {
+	id = strings.TrimSpace(id)
+	if i, ok := s.byID[id]; ok {
+		return i
+	}
+	s.participants = append(s.participants, &participant{id: id, label: id})
+	s.byID[id] = len(s.participants) - 1
+	return len(s.participants) - 1
+}
+
+// renderSequence draws a mermaid sequence diagram: participants with lifelines,
+// messages, notes and block markers (loop, alt, ...)
+func renderSequence(lines []string) (string, bool) {
+	s := &sequence{byID: make(map[string]int)}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") || ignoredSequence.MatchString(line) {
+			continue
+		}
+		if !s.parseLine(line) {
+			return "", false
+		}
+	}
+	if len(s.participants) == 0 || len(s.participants) > maxNodes {
+		return "", false
+	}
+	return s.draw(), true
+}
+
+func (s *sequence) parseLine(line string) bool {
+	if m := participantPattern.FindStringSubmatch(line); m != nil {
+		i := s.participant(m[2])
+		if m[3] != "" {
+			s.participants[i].label = strings.TrimSpace(m[3])
+		}
+		return true
+	}
+	if m := notePattern.FindStringSubmatch(line); m != nil {
+		ids := strings.Split(m[2], ",")
+		from := s.participant(ids[0])
+		to := from
+		if len(ids) > 1 {
+			to = s.participant(ids[1])
+		}
+		if from > to {
+			from, to = to, from
+		}
+		s.steps = append(s.steps, step{kind: stepNote, from: from, to: to, side: strings.ToLower(m[1]), text: m[3]})
+		return true
+	}
+	if m := blockPattern.FindStringSubmatch(line); m != nil {
+		text := m[1]
+		if m[1] == "rect" {
+			// background color only
+			if m[2] == "" {
+				return true
+			}
+			text = ""
+		}
+		if m[2] != "" && m[1] != "rect" {
+			text += " " + m[2]
+		}
+		s.steps = append(s.steps, step{kind: stepBlock, text: text})
+		return true
+	}
+	if m := messagePattern.FindStringSubmatch(line); m != nil {
+		s.steps = append(s.steps, step{
+			kind:   stepMessage,
+			from:   s.participant(m[1]),
+			to:     s.participant(m[3]),
+			dashed: strings.HasPrefix(m[2], "--"),
+			arrow:  strings.HasSuffix(m[2], ">"),
+			text:   m[4],
+		})
+		return true
+	}
+	return false
+}
+
+// layout spaces the lifelines so every message label fits between its ends
+func (s *sequence) layout() {
+	for _, p := range s.participants {
+		p.width = textWidth(p.label) + 4
+	}
+
+	gaps := make([]int, len(s.participants)) // gaps[i]: distance from lifeline i to i+1
+	for i := 0; i+1 < len(s.participants); i++ {
+		gaps[i] = (s.participants[i].width+1)/2 + (s.participants[i+1].width+1)/2 + 2
+	}
+
+	type need struct{ from, to, width int }
+	var needs []need
+	for _, st := range s.steps {
+		switch {
+		case st.kind == stepMessage && st.from != st.to:
+			from, to := min(st.from, st.to), max(st.from, st.to)
+			needs = append(needs, need{from, to, textWidth(st.text) + 4})
+		case st.kind == stepMessage:
+			needs = append(needs, need{st.from, st.from + 1, textWidth(st.text) + 6})
+		case st.kind == stepNote && st.side == "right of":
+			needs = append(needs, need{st.from, st.from + 1, textWidth(st.text) + 8})
+		case st.kind == stepNote && st.side == "left of" && st.from > 0:
+			needs = append(needs, need{st.from - 1, st.from, textWidth(st.text) + 8})
+		case st.kind == stepNote && st.from != st.to:
+			needs = append(needs, need{st.from, st.to, textWidth(st.text)})
+		}
+	}
+	sort.SliceStable(needs, func(a, b int) bool { return needs[a].to-needs[a].from < needs[b].to-needs[b].from })
+	for _, n := range needs {
+		if n.to >= len(s.participants) {
+			// room right of the last lifeline is taken as needed when drawing
+			continue
+		}
+		have := 0
+		for i := n.from; i < n.to; i++ {
+			have += gaps[i]
+		}
+		if have < n.width {
+			gaps[n.to-1] += n.width - have
+		}
+	}
+
+	center := s.participants[0].width / 2
+	left := 0
+	for _, st := range s.steps {
+		if st.kind == stepNote && st.side == "left of" && st.from == 0 {
+			left = max(left, textWidth(st.text)+6-center)
+		}
+	}
+	center += left
+	for i, p := range s.participants {
+		p.center = center
+		center += gaps[i]
+	}
+}
+
+func (s *sequence) draw() string {
+	s.layout()
+	var c canvas
+
+	const headerHeight = 3
+	for _, p := range s.participants {
+		x := p.center - p.width/2
+		c.box(x, 0, p.width, headerHeight, boxCorners[shapeBox])
+		c.text(x+2, 1, p.label)
+	}
+
+	width := 0
+	for _, p := range s.participants {
+		width = max(width, p.center+(p.width+1)/2)
+	}
+
+	y := headerHeight
+	var marks []func()
+	for _, st := range s.steps {
+		switch st.kind {
+		case stepMessage:
+			y = s.drawMessage(&c, st, y, &marks)
+		case stepNote:
+			y = s.drawNote(&c, st, y, &marks)
+		case stepBlock:
+			row := y
+			text := st.text
+			marks = append(marks, func() {
+				for x := 0; x < width; x++ {
+					if !s.isLifeline(x) {
+						c.set(x, row, '╌')
+					}
+				}
+				c.text(1, row, "["+text+"]")
+			})
+			y++
+		}
+	}
+
+	// lifelines first, so the marks drawn on top of them win
+	for _, p := range s.participants {
+		c.vline(p.center, headerHeight-1, y-1)
+	}
+	for _, mark := range marks {
+		mark()
+	}
+	return c.String()
+}
+
+func (s *sequence) isLifeline(x int) bool {
+	for _, p := range s.participants {
+		if p.center == x {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sequence) drawMessage(c *canvas, st step, y int, marks *[]func()) int {
+	from, to := s.participants[st.from].center, s.participants[st.to].center
+	if st.from == st.to {
+		// self message: out to the right and back
+		c.hline(y, from, from+2)
+		c.vline(from+2, y, y+1)
+		c.hline(y+1, from, from+2)
+		text := st.text
+		*marks = append(*marks, func() {
+			c.text(from+4, y, text)
+			if st.arrow {
+				c.set(from+1, y+1, '◀')
+			}
+		})
+		return y + 3
+	}
+
+	labelY, lineY := y, y+1
+	start, end := from, to
+	head := '▶'
+	if to < from {
+		head = '◀'
+	}
+	dir := 1
+	if to < from {
+		dir = -1
+	}
+	if st.arrow {
+		c.hline(lineY, start, end-dir)
+	} else {
+		c.hline(lineY, start, end)
+	}
+	text := st.text
+	*marks = append(*marks, func() {
+		if st.dashed {
+			for x := start + dir; x != end; x += dir {
+				if !s.isLifeline(x) {
+					c.set(x, lineY, '╌')
+				}
+			}
+		}
+		if st.arrow {
+			c.set(end-dir, lineY, head)
+		}
+		labelX := min(start, end) + (max(start, end)-min(start, end)-textWidth(text))/2
+		c.text(labelX, labelY, text)
+	})
+	return y + 3
+}
+
+func (s *sequence) drawNote(c *canvas, st step, y int, marks *[]func()) int {
+	width := textWidth(st.text) + 4
+	var x int
+	switch st.side {
+	case "right of":
+		x = s.participants[st.from].center + 2
+	case "left of":
+		x = s.participants[st.from].center - 2 - width
+	default:
+		left, right := s.participants[st.from].center, s.participants[st.to].center
+		if right-left+5 > width {
+			width = right - left + 5
+		}
+		x = (left+right)/2 - width/2
+	}
+	x = max(x, 0)
+	text := st.text
+	*marks = append(*marks, func() {
+		c.fill(x, y, width, 3)
+		c.frame(x, y, width, 3, boxCorners[shapeBox])
+		c.text(x+2, y+1, text)
+	})
+	return y + 4
+}
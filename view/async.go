@@ -0,0 +1,25 @@
+package view
+
+// loadInBackground runs load off the UI goroutine, then done on it through queueUpdate,
+// which redraws the screen. Without a queue, as in tests, both run in place.
+func loadInBackground(queueUpdate func(func()), load func(), done func()) {
+	if queueUpdate == nil {
+		load()
+		done()
+		return
+	}
+	go func() {
+		load()
+		queueUpdate(done)
+	}()
+}
+
+// onUIGoroutine wraps a listener so it runs on the UI goroutine through queueUpdate:
+// stores notify their listeners on whichever goroutine made the change. Without a queue,
+// as in tests, the listener runs in place.
+func onUIGoroutine(queueUpdate func(func()), listener func()) func() {
+	if queueUpdate == nil {
+		return listener
+	}
+	return func() { queueUpdate(listener) }
+}
@@ -0,0 +1,311 @@
+package view
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/boolean-maybe/tiki/component/barchart"
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/controller"
+	"github.com/boolean-maybe/tiki/metrics"
+	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// brailleWindowThreshold is the window (in days) above which bars switch to dense braille rendering.
+const brailleWindowThreshold = 30
+
+// ChartView renders a chart plugin: analytics computed from the git history of the task store
+type ChartView struct {
+	root        *tview.Flex
+	titleBar    tview.Primitive
+	chart       *barchart.BarChart
+	legend      *tview.TextView
+	pluginDef   *plugin.ChartPlugin
+	chartConfig *model.ChartConfig
+	taskStore   store.Store
+	registry    *controller.ActionRegistry
+
+	// runs a function on the UI goroutine; nil loads in place
+	queueUpdate func(func())
+
+	// history read in the background, kept until the store changes
+	transitions      map[string][]store.StatusChange
+	burndown         []store.BurndownPoint
+	loadErr          error
+	loading          bool
+	loadGeneration   int // drops the result of a load a newer one replaced
+	configListenerID int
+	storeListenerID  int
+}
+
+// NewChartView creates a chart view. queueUpdate hands history read in the background to
+// the UI goroutine.
+func NewChartView(
+	pluginDef *plugin.ChartPlugin,
+	chartConfig *model.ChartConfig,
+	taskStore store.Store,
+	registry *controller.ActionRegistry,
+	queueUpdate func(func()),
+) *ChartView {
+	cv := &ChartView{
+		pluginDef:   pluginDef,
+		chartConfig: chartConfig,
+		taskStore:   taskStore,
+		registry:    registry,
+		queueUpdate: queueUpdate,
+		loading:     true,
+	}
+
+	cv.build()
+	return cv
+}
+
+func (cv *ChartView) build() {
+	textColor := tcell.ColorDefault
+	if cv.pluginDef.Foreground != tcell.ColorDefault {
+		textColor = cv.pluginDef.Foreground
+	}
+	cv.titleBar = NewGradientCaptionRow([]string{cv.pluginDef.Name}, cv.pluginDef.Background, textColor)
+
+	cv.chart = barchart.NewBarChart().ShowAxis(true).ShowLabels(true)
+	cv.chart.SetBorderPadding(1, 0, 2, 2)
+
+	cv.legend = tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter)
+
+	cv.root = tview.NewFlex().SetDirection(tview.FlexRow)
+	cv.root.AddItem(cv.titleBar, 1, 0, false)
+	cv.root.AddItem(cv.chart, 0, 1, true)
+	cv.root.AddItem(cv.legend, 3, 0, false)
+
+	cv.refresh()
+}
+
+// load reads the history the chart draws in the background; the chart shows it is
+// loading until then
+func (cv *ChartView) load() {
+	cv.loadGeneration++
+	generation := cv.loadGeneration
+	cv.loading = true
+	cv.refresh()
+
+	var transitions map[string][]store.StatusChange
+	var burndown []store.BurndownPoint
+	var err error
+	loadInBackground(cv.queueUpdate, func() {
+		if cv.pluginDef.Chart == "burndown" {
+			burndown = cv.taskStore.GetBurndown()
+			return
+		}
+		transitions, err = cv.taskStore.GetStatusTransitions()
+	}, func() {
+		if generation != cv.loadGeneration {
+			return
+		}
+		if err != nil {
+			slog.Error("failed to load status history for chart", "plugin", cv.pluginDef.Name, "error", err)
+		}
+		cv.transitions, cv.burndown, cv.loadErr = transitions, burndown, err
+		cv.loading = false
+		cv.refresh()
+	})
+}
+
+// refresh recomputes the bars for the selected window
+func (cv *ChartView) refresh() {
+	if cv.loading {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText("Loading the task history…")
+		return
+	}
+
+	switch cv.pluginDef.Chart {
+	case "burndown":
+		cv.refreshBurndown()
+		return
+	case "velocity":
+		cv.refreshVelocity()
+		return
+	}
+
+	if cv.loadErr != nil {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText(fmt.Sprintf("[red]History unavailable: %v[-]", cv.loadErr))
+		return
+	}
+
+	window := cv.chartConfig.GetWindow()
+	points := metrics.CumulativeFlow(cv.transitions, time.Now(), window)
+	colors := config.GetColors()
+
+	bars := make([]barchart.Bar, 0, len(points))
+	for _, point := range points {
+		segments := make([]barchart.Segment, 0, len(metrics.CFDStatuses))
+		for _, status := range metrics.CFDStatuses {
+			segments = append(segments, barchart.Segment{
+				Value: float64(point.Counts[status]),
+				Color: colors.ChartStatusColors[string(status)],
+			})
+		}
+		bars = append(bars, barchart.Bar{Label: point.Date.Format("02"), Segments: segments})
+	}
+
+	cv.chart.SetBars(bars)
+	if window > brailleWindowThreshold {
+		cv.chart.UseBraille()
+	} else {
+		cv.chart.UseSolidBars().SetBarWidth(3).SetGapWidth(1)
+	}
+
+	cv.legend.SetText(cv.legendText(points, colors))
+}
+
+// refreshBurndown renders the configured burndown at full size
+func (cv *ChartView) refreshBurndown() {
+	points := cv.burndown
+	if len(points) == 0 {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText("Burndown history is not available yet")
+		return
+	}
+
+	bars := make([]barchart.Bar, 0, len(points))
+	lastDay := ""
+	maxVal := 0.0
+	for _, point := range points {
+		// label only the first interval of each day
+		label := point.Date.Local().Format("02")
+		if label == lastDay {
+			label = ""
+		} else {
+			lastDay = label
+		}
+		value := float64(point.Remaining)
+		if value > maxVal {
+			maxVal = value
+		}
+		bars = append(bars, barchart.Bar{Label: label, Value: value})
+	}
+	if maxVal <= 0 {
+		maxVal = 1
+	}
+
+	cv.chart.UseSolidBars().SetBarWidth(3).SetGapWidth(1)
+	cv.chart.SetMaxValue(maxVal).SetBars(bars)
+	cv.legend.SetText(burndownLegend(points))
+}
+
+// refreshVelocity renders completed points per sprint with the rolling average as a marker
+func (cv *ChartView) refreshVelocity() {
+	if cv.loadErr != nil {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText(fmt.Sprintf("[red]History unavailable: %v[-]", cv.loadErr))
+		return
+	}
+
+	sprints, err := config.GetSprints()
+	if err != nil {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText(fmt.Sprintf("[red]Invalid sprints config: %v[-]", tview.Escape(err.Error())))
+		return
+	}
+	velocities := metrics.Velocity(cv.transitions, sprints, time.Now())
+	if len(velocities) == 0 {
+		cv.chart.SetBars(nil)
+		cv.legend.SetText("No sprints have started yet: add a sprints section to config.yaml")
+		return
+	}
+
+	bars := make([]barchart.Bar, 0, len(velocities))
+	for _, v := range velocities {
+		bars = append(bars, barchart.Bar{
+			Label:  v.Sprint.Name,
+			Value:  float64(v.Points),
+			Marker: v.Average,
+		})
+	}
+
+	cv.chart.UseSolidBars().SetBarWidth(6).SetGapWidth(2).ShowValues(true)
+	cv.chart.SetBars(bars)
+
+	last := velocities[len(velocities)-1]
+	markerColor := config.GetColors().ChartMarkerColor
+	cv.legend.SetText(fmt.Sprintf("Velocity, last %d sprints\n%s: %d points (%d tasks)   [#%06x]━[-] %d-sprint average: %.1f points",
+		len(velocities), tview.Escape(last.Sprint.Name), last.Points, last.Tasks,
+		markerColor.Hex(), metrics.VelocityAverageSprints, last.Average))
+}
+
+// burndownLegend describes the sprint window, unit and the latest remaining work
+func burndownLegend(points []store.BurndownPoint) string {
+	unit := "tasks"
+	if config.GetBurndownPoints() {
+		unit = "points"
+	}
+
+	window := fmt.Sprintf("last %d days", store.DefaultBurndownDays)
+	if start, end, err := config.GetBurndownSprint(); err == nil && !start.IsZero() {
+		window = fmt.Sprintf("sprint %s – %s", start.Format("Jan 02"), end.AddDate(0, 0, -1).Format("Jan 02"))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Burndown, %s\n", window)
+	if scope := config.GetBurndownFilter(); scope != "" {
+		fmt.Fprintf(&sb, "Scope: %s   ", tview.Escape(scope))
+	}
+	fmt.Fprintf(&sb, "Remaining: %d %s", points[len(points)-1].Remaining, unit)
+	return sb.String()
+}
+
+// legendText describes the window and the latest count per status in stacking order
+func (cv *ChartView) legendText(points []metrics.FlowPoint, colors *config.ColorConfig) string {
+	if len(points) == 0 {
+		return ""
+	}
+	first, last := points[0], points[len(points)-1]
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Cumulative flow, last %d days (%s – %s)\n", len(points),
+		first.Date.Format("Jan 02"), last.Date.Format("Jan 02"))
+
+	// legend reads top-down like the stacked bars
+	parts := make([]string, 0, len(metrics.CFDStatuses))
+	for i := len(metrics.CFDStatuses) - 1; i >= 0; i-- {
+		status := metrics.CFDStatuses[i]
+		color := colors.ChartStatusColors[string(status)]
+		parts = append(parts, fmt.Sprintf("[#%06x]■[-] %s %d", color.Hex(), task.StatusLabel(status), last.Counts[status]))
+	}
+	sb.WriteString(strings.Join(parts, "   "))
+	return sb.String()
+}
+
+func (cv *ChartView) GetPrimitive() tview.Primitive {
+	return cv.root
+}
+
+func (cv *ChartView) GetActionRegistry() *controller.ActionRegistry {
+	return cv.registry
+}
+
+func (cv *ChartView) GetViewID() model.ViewID {
+	return model.MakePluginViewID(cv.pluginDef.Name)
+}
+
+// OnFocus is called when the view becomes active
+func (cv *ChartView) OnFocus() {
+	cv.configListenerID = cv.chartConfig.AddListener(onUIGoroutine(cv.queueUpdate, cv.refresh))
+	cv.storeListenerID = cv.taskStore.AddListener(onUIGoroutine(cv.queueUpdate, cv.load))
+	cv.load()
+}
+
+// OnBlur is called when the view becomes inactive
+func (cv *ChartView) OnBlur() {
+	cv.chartConfig.RemoveListener(cv.configListenerID)
+	cv.taskStore.RemoveListener(cv.storeListenerID)
+}
@@ -0,0 +1,79 @@
+package view
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/controller"
+	"github.com/boolean-maybe/tiki/model"
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/store"
+	"github.com/boolean-maybe/tiki/task"
+)
+
+// transitionCountingStore counts the reads of the full status history
+type transitionCountingStore struct {
+	*store.InMemoryStore
+	reads int
+}
+
+func (s *transitionCountingStore) GetStatusTransitions() (map[string][]store.StatusChange, error) {
+	s.reads++
+	return s.InMemoryStore.GetStatusTransitions()
+}
+
+func TestChartViewCachesHistoryUntilTheStoreChanges(t *testing.T) {
+	taskStore := &transitionCountingStore{InMemoryStore: store.NewInMemoryStore()}
+	chartConfig := model.NewChartConfig("Flow", 14)
+	queued := make(chan func(), 4)
+	publish := func() {
+		t.Helper()
+		select {
+		case f := <-queued:
+			f()
+		case <-time.After(5 * time.Second):
+			t.Fatal("history never handed to the UI goroutine")
+		}
+	}
+
+	cv := NewChartView(&plugin.ChartPlugin{BasePlugin: plugin.BasePlugin{Name: "Flow"}, Chart: "cfd"},
+		chartConfig, taskStore, controller.NewActionRegistry(), func(f func()) { queued <- f })
+	cv.OnFocus()
+	if !strings.Contains(cv.legend.GetText(true), "Loading") {
+		t.Errorf("legend = %q while loading", cv.legend.GetText(true))
+	}
+	publish()
+	if strings.Contains(cv.legend.GetText(true), "Loading") {
+		t.Errorf("legend = %q after loading", cv.legend.GetText(true))
+	}
+
+	chartConfig.CycleWindow(1)
+	publish()
+	if taskStore.reads != 1 {
+		t.Errorf("history read %d times after a window change, want it cached", taskStore.reads)
+	}
+
+	// listeners only queue their work: the store may notify from any goroutine
+	if err := taskStore.CreateTask(&task.Task{ID: "TIKI-NEW001", Title: "New", Status: task.StatusReady}); err != nil {
+		t.Fatal(err)
+	}
+	if cv.loading {
+		t.Error("store listener touched the view off the UI goroutine")
+	}
+	publish()
+	publish()
+	if taskStore.reads != 2 {
+		t.Errorf("history read %d times after a store change, want 2", taskStore.reads)
+	}
+
+	cv.OnBlur()
+	if err := taskStore.CreateTask(&task.Task{ID: "TIKI-NEW002", Title: "Unseen", Status: task.StatusReady}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-queued:
+		t.Error("history reloaded after the view was blurred")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,66 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// doki find: text search within the page on display. The find box sits above the page
+// while a pattern is typed; matches stay highlighted after it closes, n and N move
+// between them and Esc clears them.
+
+// ShowFind displays the find box and returns the primitive to focus
+func (dv *DokiView) ShowFind() tview.Primitive {
+	if dv.IsSearchVisible() || dv.outline != nil {
+		// the page isn't on display
+		return nil
+	}
+	if dv.findBox == nil {
+		dv.findBox = NewSearchBox()
+		dv.findBox.SetSubmitHandler(func(text string) {
+			dv.closeFindBox()
+			dv.markdown.Find(strings.TrimSpace(text))
+		})
+		dv.findBox.SetCancelHandler(dv.HideFind)
+		dv.rebuildLayout()
+	}
+	return dv.findBox
+}
+
+// HideFind closes the find box and clears the highlighted matches
+func (dv *DokiView) HideFind() {
+	dv.closeFindBox()
+	dv.markdown.ClearFind()
+}
+
+// IsFindActive reports whether the find box or highlighted matches are shown
+func (dv *DokiView) IsFindActive() bool {
+	query, _, _ := dv.markdown.FindStatus()
+	return dv.findBox != nil || query != ""
+}
+
+// IsFindBoxFocused returns whether the find box currently has focus
+func (dv *DokiView) IsFindBoxFocused() bool {
+	return dv.findBox != nil && dv.findBox.HasFocus()
+}
+
+// NextMatch scrolls the page to the next match
+func (dv *DokiView) NextMatch() {
+	dv.markdown.NextMatch()
+}
+
+// PreviousMatch scrolls the page to the previous match
+func (dv *DokiView) PreviousMatch() {
+	dv.markdown.PreviousMatch()
+}
+
+// closeFindBox removes the find box and returns focus to the page
+func (dv *DokiView) closeFindBox() {
+	if dv.findBox == nil {
+		return
+	}
+	dv.findBox = nil
+	dv.rebuildLayout()
+	dv.setFocus(dv.markdown.Viewer())
+}
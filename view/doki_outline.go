@@ -0,0 +1,48 @@
+package view
+
+// doki outline: the headings of the page on display, listed in place of the document.
+// Choosing one scrolls the page to it; ] and [ move between headings without the list.
+
+// ToggleOutline shows or hides the list of headings
+func (dv *DokiView) ToggleOutline() {
+	if dv.outline != nil {
+		dv.HideOutline()
+		return
+	}
+	if dv.search != nil && dv.search.IsActive() {
+		// search results are on display instead of the page
+		return
+	}
+	dv.outline = dv.markdown.Outline(dv.HideOutline)
+	dv.rebuildLayout()
+	dv.setFocus(dv.outline)
+}
+
+// HideOutline closes the list of headings and returns to the page
+func (dv *DokiView) HideOutline() {
+	if dv.outline == nil {
+		return
+	}
+	dv.outline = nil
+	dv.rebuildLayout()
+	dv.setFocus(dv.markdown.Viewer())
+}
+
+// IsOutlineVisible reports whether the list of headings is currently shown
+func (dv *DokiView) IsOutlineVisible() bool {
+	return dv.outline != nil
+}
+
+// NextHeading scrolls the page to the next heading
+func (dv *DokiView) NextHeading() {
+	if dv.outline == nil {
+		dv.markdown.NextHeading()
+	}
+}
+
+// PreviousHeading scrolls the page to the previous heading
+func (dv *DokiView) PreviousHeading() {
+	if dv.outline == nil {
+		dv.markdown.PreviousHeading()
+	}
+}
@@ -4,11 +4,18 @@ import (
 	_ "embed"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/controller"
+	"github.com/boolean-maybe/tiki/doki"
+	"github.com/boolean-maybe/tiki/metrics"
 	"github.com/boolean-maybe/tiki/model"
 	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/store"
 	"github.com/boolean-maybe/tiki/view/renderer"
 
 	"github.com/boolean-maybe/navidown/loaders"
@@ -34,17 +41,42 @@ type DokiView struct {
 	pluginDef *plugin.DokiPlugin
 	registry  *controller.ActionRegistry
 	renderer  renderer.MarkdownRenderer
+	taskStore store.Store
+
+	// runs a function on the UI goroutine; nil loads in place
+	queueUpdate func(func())
+
+	// full-text search across the doki tree (nil when the plugin is not searchable)
+	search           *model.DokiSearch
+	searchHelper     *SearchHelper
+	results          *tview.List
+	focusSetter      func(p tview.Primitive)
+	searchListenerID int
+	pageRevision     int // last page edit reloaded from disk
+
+	// headings of the page on display (nil while hidden)
+	outline *tview.List
+
+	// find in page box (nil while hidden)
+	findBox *SearchBox
 }
 
-// NewDokiView creates a doki view
+// NewDokiView creates a doki view. search is nil for plugins that don't show the doki tree.
+// queueUpdate hands content generated in the background to the UI goroutine.
 func NewDokiView(
 	pluginDef *plugin.DokiPlugin,
 	mdRenderer renderer.MarkdownRenderer,
+	taskStore store.Store,
+	search *model.DokiSearch,
+	queueUpdate func(func()),
 ) *DokiView {
 	dv := &DokiView{
-		pluginDef: pluginDef,
-		registry:  controller.NewActionRegistry(),
-		renderer:  mdRenderer,
+		pluginDef:   pluginDef,
+		registry:    controller.NewActionRegistry(),
+		renderer:    mdRenderer,
+		taskStore:   taskStore,
+		search:      search,
+		queueUpdate: queueUpdate,
 	}
 
 	dv.build()
@@ -82,6 +114,40 @@ func (dv *DokiView) build() {
 			SearchRoots:   searchRoots,
 			OnStateChange: dv.UpdateNavigationActions,
 		})
+		dv.markdown.SetMissingPageHandler(dv.showMissingPage)
+
+	case "git":
+		// docs as of a branch or tag, read through git instead of the working tree
+		searchRoots := []string{config.GetDokiDir()}
+		provider := &doki.GitProvider{Ref: dv.pluginDef.Ref, SearchRoots: searchRoots}
+		if reader, ok := dv.taskStore.(doki.RefReader); ok {
+			provider.Reader = reader
+		}
+
+		content, err = provider.FetchContent(nav.NavElement{URL: dv.pluginDef.URL})
+		sourcePath = provider.ResolvePath(dv.pluginDef.URL, "")
+
+		dv.markdown = NewNavigableMarkdown(NavigableMarkdownConfig{
+			Provider:      provider,
+			SearchRoots:   searchRoots,
+			ResolveSource: provider.ResolvePath,
+			OnStateChange: dv.UpdateNavigationActions,
+		})
+
+	case "dir":
+		// generated table of contents; its links are relative to the directory
+		dir := resolveDokiDir(dv.pluginDef.URL)
+		searchRoots := []string{dir, config.GetDokiDir()}
+		provider := &loaders.FileHTTP{SearchRoots: searchRoots}
+
+		content, err = doki.DirectoryIndex(dir)
+		sourcePath = filepath.Join(dir, "index.md")
+
+		dv.markdown = NewNavigableMarkdown(NavigableMarkdownConfig{
+			Provider:      provider,
+			SearchRoots:   searchRoots,
+			OnStateChange: dv.UpdateNavigationActions,
+		})
 
 	case "internal":
 		cnt := map[string]string{
@@ -97,6 +163,14 @@ func (dv *DokiView) build() {
 			OnStateChange: dv.UpdateNavigationActions,
 		})
 
+	case "metrics":
+		// flow metrics report generated from the full git history of the task store,
+		// which takes a while: a placeholder is shown until it is ready
+		content = metricsLoadingContent
+		dv.markdown = NewNavigableMarkdown(NavigableMarkdownConfig{
+			OnStateChange: dv.UpdateNavigationActions,
+		})
+
 	default:
 		content = "Error: Unknown fetcher type"
 		dv.markdown = NewNavigableMarkdown(NavigableMarkdownConfig{
@@ -109,22 +183,52 @@ func (dv *DokiView) build() {
 		content = fmt.Sprintf("Error loading content: %v", err)
 	}
 
+	content = doki.LinkTaskReferences(content)
+
 	// Display initial content (don't push to history - this is the first page)
 	if sourcePath != "" {
 		dv.markdown.SetMarkdownWithSource(content, sourcePath, false)
 	} else {
 		dv.markdown.SetMarkdown(content)
 	}
+	dv.UpdateNavigationActions()
+
+	// search results replace the document while a search is active
+	dv.results = tview.NewList().ShowSecondaryText(true).SetHighlightFullLine(true)
+	dv.results.SetBorder(true)
+	dv.searchHelper = NewSearchHelper(dv.results)
+	dv.searchHelper.SetCancelHandler(func() {
+		dv.HideSearch()
+	})
 
 	// root layout
 	dv.root = tview.NewFlex().SetDirection(tview.FlexRow)
 	dv.rebuildLayout()
+
+	if dv.pluginDef.Fetcher == "metrics" {
+		dv.loadMetrics()
+	}
 }
 
+// rebuildLayout rebuilds the root layout based on current state (search visibility)
 func (dv *DokiView) rebuildLayout() {
 	dv.root.Clear()
 	dv.root.AddItem(dv.titleBar, 1, 0, false)
-	dv.root.AddItem(dv.markdown.Viewer(), 0, 1, true)
+	if dv.searchHelper.IsVisible() {
+		dv.root.AddItem(dv.searchHelper.GetSearchBox(), config.SearchBoxHeight, 0, true)
+	}
+	if dv.search != nil && dv.search.IsActive() {
+		dv.root.AddItem(dv.results, 0, 1, false)
+		return
+	}
+	if dv.outline != nil {
+		dv.root.AddItem(dv.outline, 0, 1, true)
+		return
+	}
+	if dv.findBox != nil {
+		dv.root.AddItem(dv.findBox, config.SearchBoxHeight, 0, true)
+	}
+	dv.root.AddItem(dv.markdown.Viewer(), 0, 1, !dv.searchHelper.IsVisible() && dv.findBox == nil)
 }
 
 func (dv *DokiView) GetPrimitive() tview.Primitive {
@@ -140,11 +244,119 @@ func (dv *DokiView) GetViewID() model.ViewID {
 }
 
 func (dv *DokiView) OnFocus() {
-	// Focus behavior
+	if dv.search != nil {
+		dv.searchListenerID = dv.search.AddListener(func() {
+			if revision := dv.search.GetPageRevision(); revision != dv.pageRevision {
+				dv.pageRevision = revision
+				dv.reloadPage()
+			}
+			dv.refreshResults()
+			dv.rebuildLayout()
+			// backlinks have no search box to hand focus over, so select the list directly
+			if dv.search.IsBacklinks() {
+				dv.setFocus(dv.results)
+			}
+		})
+	}
 }
 
 func (dv *DokiView) OnBlur() {
-	// No cleanup needed yet
+	if dv.search != nil {
+		dv.search.RemoveListener(dv.searchListenerID)
+	}
+}
+
+// metricsLoadingContent is shown while the flow metrics report is generated
+const metricsLoadingContent = "# Flow metrics\n\nLoading the task history…\n"
+
+// loadMetrics generates the flow metrics report in the background and shows it
+func (dv *DokiView) loadMetrics() {
+	var content string
+	loadInBackground(dv.queueUpdate, func() {
+		report, err := metrics.Generate(dv.taskStore, time.Now())
+		if err != nil {
+			slog.Error("failed to generate flow metrics", "plugin", dv.pluginDef.Name, "error", err)
+			content = fmt.Sprintf("Error loading content: %v", err)
+			return
+		}
+		content = report.Markdown()
+	}, func() {
+		dv.markdown.SetMarkdown(doki.LinkTaskReferences(content))
+		dv.UpdateNavigationActions()
+	})
+}
+
+// reloadPage shows the page on display again after it was edited
+func (dv *DokiView) reloadPage() {
+	page := dv.markdown.SourceFilePath()
+	content, err := os.ReadFile(page)
+	if err != nil {
+		// a page that still doesn't exist keeps its placeholder
+		return
+	}
+	dv.markdown.SetMarkdownWithSource(doki.LinkTaskReferences(string(content)), page, false)
+}
+
+// showMissingPage follows a link to a markdown file that doesn't exist yet: the page
+// is shown as a placeholder whose source is the missing file, so Edit creates it.
+func (dv *DokiView) showMissingPage(url, sourceFile string) {
+	target := missingPageTarget(url, sourceFile)
+	if target == "" {
+		dv.markdown.SetMarkdown(FormatErrorContent(fmt.Errorf("%s: %w", url, nav.ErrFileNotFound)))
+		return
+	}
+
+	name := strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+	content := fmt.Sprintf("# %s\n\n`%s` doesn't exist yet. Press `e` to create it\n", name, url)
+	dv.markdown.SetMarkdownWithSource(content, target, true)
+}
+
+// missingPageTarget returns the file a relative markdown link points at, or ""
+// for links that can't become a new page
+func missingPageTarget(url, sourceFile string) string {
+	if !strings.EqualFold(filepath.Ext(url), ".md") ||
+		strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return ""
+	}
+	if filepath.IsAbs(url) {
+		return filepath.Clean(url)
+	}
+	if sourceFile == "" {
+		return filepath.Join(config.GetDokiDir(), url)
+	}
+	return filepath.Join(filepath.Dir(sourceFile), url)
+}
+
+// resolveDokiDir locates a dir fetcher's directory: absolute, under the doki directory,
+// or relative to the project root
+func resolveDokiDir(url string) string {
+	if filepath.IsAbs(url) {
+		return url
+	}
+	dir := filepath.Join(config.GetDokiDir(), url)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return url
+}
+
+// SetTaskLinkHandler sets the callback for following a task reference
+func (dv *DokiView) SetTaskLinkHandler(handler func(taskID string)) {
+	dv.markdown.SetTaskLinkHandler(handler)
+}
+
+// OpenPage shows a markdown file, scrolled to the heading with the given slug
+func (dv *DokiView) OpenPage(path, anchor string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		dv.markdown.SetMarkdown(FormatErrorContent(err))
+		return
+	}
+	// push history so Back returns to the page shown before
+	dv.markdown.SetMarkdownWithSource(doki.LinkTaskReferences(string(content)), path, true)
+	if anchor != "" {
+		dv.markdown.Viewer().ScrollToAnchor(anchor, false)
+	}
 }
 
 // UpdateNavigationActions updates the registry to reflect current navigation state
@@ -152,9 +364,34 @@ func (dv *DokiView) UpdateNavigationActions() {
 	// Clear and rebuild the registry
 	dv.registry = controller.NewActionRegistry()
 
+	if dv.search != nil {
+		dv.search.SetPage(dv.markdown.SourceFilePath())
+		dv.registry.Register(controller.Action{
+			ID:           controller.ActionSearch,
+			Key:          tcell.KeyRune,
+			Rune:         '/',
+			Label:        "Search",
+			ShowInHeader: true,
+		})
+		dv.registry.Register(controller.Action{
+			ID:           controller.ActionBacklinks,
+			Key:          tcell.KeyRune,
+			Rune:         'b',
+			Label:        "Backlinks",
+			ShowInHeader: true,
+		})
+		dv.registry.Register(controller.Action{
+			ID:           controller.ActionEditSource,
+			Key:          tcell.KeyRune,
+			Rune:         'e',
+			Label:        "Edit",
+			ShowInHeader: true,
+		})
+	}
+
 	// Always show Tab/Shift+Tab for link navigation
 	dv.registry.Register(controller.Action{
-		ID:           "navigate_next_link",
+		ID:           controller.ActionNextLink,
 		Key:          tcell.KeyTab,
 		Label:        "Next Link",
 		ShowInHeader: true,
@@ -165,6 +402,36 @@ func (dv *DokiView) UpdateNavigationActions() {
 		Label:        "Prev Link",
 		ShowInHeader: true,
 	})
+	dv.registry.Register(controller.Action{
+		ID:           controller.ActionOutline,
+		Key:          tcell.KeyRune,
+		Rune:         'o',
+		Label:        "Outline",
+		ShowInHeader: true,
+	})
+	dv.registry.Register(controller.Action{
+		ID:           controller.ActionFind,
+		Key:          tcell.KeyRune,
+		Rune:         'f',
+		Label:        "Find",
+		ShowInHeader: true,
+	})
+	if _, _, total := dv.markdown.FindStatus(); total > 0 {
+		dv.registry.Register(controller.Action{
+			ID:           controller.ActionFindNext,
+			Key:          tcell.KeyRune,
+			Rune:         'n',
+			Label:        "Next match",
+			ShowInHeader: true,
+		})
+		dv.registry.Register(controller.Action{
+			ID:           controller.ActionFindPrev,
+			Key:          tcell.KeyRune,
+			Rune:         'N',
+			Label:        "Prev match",
+			ShowInHeader: true,
+		})
+	}
 
 	// Add back action if available
 	// Note: navidown supports both plain Left/Right and Alt+Left/Right for navigation
@@ -0,0 +1,75 @@
+package view
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boolean-maybe/tiki/plugin"
+	"github.com/boolean-maybe/tiki/store"
+
+	nav "github.com/boolean-maybe/navidown/navidown"
+)
+
+func TestMissingPageTarget(t *testing.T) {
+	source := filepath.Join("/repo", ".doc", "doki", "doc", "config.md")
+
+	if got := missingPageTarget("new.md", source); got != filepath.Join("/repo", ".doc", "doki", "doc", "new.md") {
+		t.Errorf("relative link resolved to %q", got)
+	}
+	if got := missingPageTarget("../guide/intro.md", source); got != filepath.Join("/repo", ".doc", "doki", "guide", "intro.md") {
+		t.Errorf("parent link resolved to %q", got)
+	}
+	for _, url := range []string{"image.png", "https://example.com/page.md", "notes"} {
+		if got := missingPageTarget(url, source); got != "" {
+			t.Errorf("missingPageTarget(%q) = %q, want no target", url, got)
+		}
+	}
+}
+
+type staticProvider string
+
+func (p staticProvider) FetchContent(nav.NavElement) (string, error) { return string(p), nil }
+
+func TestTaskReferencesLinkedOnlyWithHandler(t *testing.T) {
+	nm := NewNavigableMarkdown(NavigableMarkdownConfig{Provider: staticProvider("see TIKI-ABC123")})
+	page := nav.NavElement{URL: "page.md"}
+
+	if got, _ := nm.provider.FetchContent(page); got != "see TIKI-ABC123" {
+		t.Errorf("without a task link handler got %q, want the text as written", got)
+	}
+	nm.SetTaskLinkHandler(func(string) {})
+	if got, _ := nm.provider.FetchContent(page); !strings.Contains(got, "(tiki:TIKI-ABC123)") {
+		t.Errorf("with a task link handler got %q, want a link", got)
+	}
+
+	// diagrams are drawn once, when the fetched page is shown
+	diagramPage := "```mermaid\ngraph TD\nA-->B\n```\n"
+	nm = NewNavigableMarkdown(NavigableMarkdownConfig{Provider: staticProvider(diagramPage)})
+	if got, _ := nm.provider.FetchContent(page); got != diagramPage {
+		t.Errorf("provider drew the diagram: got %q", got)
+	}
+}
+
+func TestMetricsReportLoadsInBackground(t *testing.T) {
+	queued := make(chan func(), 1)
+	queue := func(f func()) { queued <- f }
+	def := &plugin.DokiPlugin{BasePlugin: plugin.BasePlugin{Name: "Metrics"}, Fetcher: "metrics"}
+
+	dv := NewDokiView(def, nil, store.NewInMemoryStore(), nil, queue)
+	text := func() string { return dv.markdown.Viewer().GetText(true) }
+	if !strings.Contains(text(), "Loading") {
+		t.Fatalf("placeholder not shown while the report is generated: %q", text())
+	}
+
+	select {
+	case publish := <-queued:
+		publish()
+	case <-time.After(5 * time.Second):
+		t.Fatal("report never handed to the UI goroutine")
+	}
+	if strings.Contains(text(), "Loading") || !strings.Contains(text(), "tasks tracked") {
+		t.Errorf("report not shown after it was published: %q", text())
+	}
+}
@@ -0,0 +1,117 @@
+package view
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/doki"
+
+	"github.com/rivo/tview"
+)
+
+// doki search: full-text search across the doki tree. Submitting a query replaces the
+// document with a list of hits; selecting one opens it scrolled to the matching heading.
+// Backlinks (pages linking to the current one) are listed the same way, without a search box.
+
+// ShowSearch displays the search box and returns the primitive to focus
+func (dv *DokiView) ShowSearch() tview.Primitive {
+	if dv.search == nil {
+		return nil
+	}
+	if !dv.searchHelper.IsVisible() {
+		dv.outline = nil
+		dv.searchHelper.ShowSearch(dv.search.GetQuery())
+		dv.rebuildLayout()
+	}
+	return dv.searchHelper.GetSearchBox()
+}
+
+// HideSearch hides the search box and closes the results
+func (dv *DokiView) HideSearch() {
+	if !dv.IsSearchVisible() {
+		return
+	}
+	dv.searchHelper.HideSearch()
+	dv.search.Clear()
+	dv.rebuildLayout()
+	dv.setFocus(dv.markdown.Viewer())
+}
+
+// IsSearchVisible returns whether the search box or a results list is visible,
+// so that Esc closes them before leaving the view
+func (dv *DokiView) IsSearchVisible() bool {
+	return dv.search != nil && (dv.searchHelper.IsVisible() || dv.search.IsActive())
+}
+
+// IsSearchBoxFocused returns whether the search box currently has focus
+func (dv *DokiView) IsSearchBoxFocused() bool {
+	return dv.search != nil && dv.searchHelper.HasFocus()
+}
+
+// SetSearchSubmitHandler sets the callback for when search is submitted
+func (dv *DokiView) SetSearchSubmitHandler(handler func(text string)) {
+	dv.searchHelper.SetSubmitHandler(handler)
+}
+
+// SetFocusSetter sets the callback for requesting focus changes
+func (dv *DokiView) SetFocusSetter(setter func(p tview.Primitive)) {
+	dv.focusSetter = setter
+	dv.searchHelper.SetFocusSetter(setter)
+}
+
+func (dv *DokiView) setFocus(p tview.Primitive) {
+	if dv.focusSetter != nil {
+		dv.focusSetter(p)
+	}
+}
+
+// refreshResults fills the results list from the search model
+func (dv *DokiView) refreshResults() {
+	dv.results.Clear()
+
+	hits, err := dv.search.GetResults()
+	query := dv.search.GetQuery()
+	colors := config.GetColors()
+
+	if dv.search.IsBacklinks() {
+		query = filepath.Base(dv.search.GetPage())
+	}
+
+	switch {
+	case err != nil:
+		dv.results.SetTitle(" Search failed ")
+		dv.results.AddItem(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())), "", 0, nil)
+	case dv.search.IsBacklinks() && len(hits) == 0:
+		dv.results.SetTitle(fmt.Sprintf(" No pages link to %s ", query))
+	case dv.search.IsBacklinks():
+		dv.results.SetTitle(fmt.Sprintf(" %d links to %s ", len(hits), query))
+	case len(hits) == 0:
+		dv.results.SetTitle(fmt.Sprintf(" No results for %q ", query))
+	default:
+		dv.results.SetTitle(fmt.Sprintf(" %d results for %q ", len(hits), query))
+	}
+
+	for _, hit := range hits {
+		main := fmt.Sprintf("%s[gray]  %s[-]", tview.Escape(hitTitle(hit)), tview.Escape(hit.Document.Path))
+		dv.results.AddItem(main, tview.Escape(hit.Snippet), 0, func() {
+			dv.openHit(hit)
+		})
+	}
+	dv.results.SetBorderColor(colors.TaskBoxUnselectedBorder)
+	dv.results.SetCurrentItem(0)
+}
+
+// hitTitle names the document and, for hits below a heading, the section
+func hitTitle(hit doki.Hit) string {
+	if hit.Heading == "" || hit.Heading == hit.Document.Title {
+		return hit.Document.Title
+	}
+	return hit.Document.Title + " › " + hit.Heading
+}
+
+// openHit shows the hit's document in the viewer, scrolled to its section
+func (dv *DokiView) openHit(hit doki.Hit) {
+	dv.OpenPage(filepath.Join(config.GetDokiDir(), filepath.FromSlash(hit.Document.Path)), hit.Slug)
+	dv.HideSearch()
+}
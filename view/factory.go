@@ -18,6 +18,8 @@ import (
 type ViewFactory struct {
 	taskStore store.Store
 	renderer  renderer.MarkdownRenderer
+	// runs a function on the UI goroutine and redraws; views load slow data in the background
+	queueUpdate func(func())
 	// Plugin support
 	pluginConfigs     map[string]*model.PluginConfig
 	pluginDefs        map[string]plugin.Plugin
@@ -52,6 +54,12 @@ func (f *ViewFactory) SetPlugins(
 	f.pluginControllers = controllers
 }
 
+// SetUpdateQueue sets how views hand data they loaded in the background to the UI
+// goroutine, e.g. tview.Application.QueueUpdateDraw. Without one they load it in place.
+func (f *ViewFactory) SetUpdateQueue(queueUpdate func(func())) {
+	f.queueUpdate = queueUpdate
+}
+
 // CreateView instantiates a view by ID with optional parameters
 func (f *ViewFactory) CreateView(viewID model.ViewID, params map[string]interface{}) controller.View {
 	var v controller.View
@@ -59,7 +67,9 @@ func (f *ViewFactory) CreateView(viewID model.ViewID, params map[string]interfac
 	switch viewID {
 	case model.TaskDetailViewID:
 		taskID := model.DecodeTaskDetailParams(params).TaskID
-		v = taskdetail.NewTaskDetailView(f.taskStore, taskID, f.renderer)
+		detailView := taskdetail.NewTaskDetailView(f.taskStore, taskID, f.renderer)
+		detailView.SetUpdateQueue(f.queueUpdate)
+		v = detailView
 
 	case model.TaskEditViewID:
 		taskID := model.DecodeTaskEditParams(params).TaskID
@@ -95,7 +105,21 @@ func (f *ViewFactory) CreateView(viewID model.ViewID, params map[string]interfac
 						slog.Error("plugin controller type mismatch", "plugin", pluginName)
 					}
 				} else if dokiPlugin, ok := pluginDef.(*plugin.DokiPlugin); ok {
-					v = NewDokiView(dokiPlugin, f.renderer)
+					var search *model.DokiSearch
+					if dokiController, ok := pluginControllerInterface.(*controller.DokiController); ok && dokiPlugin.Fetcher == "file" {
+						search = dokiController.GetSearch()
+					}
+					dokiView := NewDokiView(dokiPlugin, f.renderer, f.taskStore, search, f.queueUpdate)
+					if dokiParams := model.DecodeDokiParams(params); dokiParams.Page != "" {
+						dokiView.OpenPage(dokiParams.Page, dokiParams.Anchor)
+					}
+					v = dokiView
+				} else if chartPlugin, ok := pluginDef.(*plugin.ChartPlugin); ok {
+					if chartController, ok := pluginControllerInterface.(*controller.ChartController); ok {
+						v = NewChartView(chartPlugin, chartController.GetChartConfig(), f.taskStore, chartController.GetActionRegistry(), f.queueUpdate)
+					} else {
+						slog.Error("plugin controller type mismatch", "plugin", pluginName)
+					}
 				} else {
 					slog.Error("unknown plugin type or missing config", "plugin", pluginName)
 				}
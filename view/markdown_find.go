@@ -0,0 +1,276 @@
+package view
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/boolean-maybe/tiki/view/outline"
+)
+
+// markdown find: text search within the document on display. Matches are highlighted by
+// adding style tags to the viewer's text. navidown rewrites that text whenever the content
+// or the selected link changes, so the highlights are applied again on every state change.
+
+const (
+	findMatchTag   = "[::r]"
+	findCurrentTag = "[black:yellow:b]"
+	findResetTag   = "[-:-:-]"
+)
+
+// findMatch is a match position in visible runes of a rendered line
+type findMatch struct {
+	line, col, length int
+}
+
+type findState struct {
+	query   string
+	matches []findMatch
+	current int
+	base    string // viewer text without highlights
+	text    string // viewer text with highlights, as last set
+}
+
+// Find highlights the matches of query in the document on display and scrolls to the
+// first one at or below the top of the viewport. Matching ignores case unless the
+// query has upper case letters. An empty query clears the highlights.
+// Returns the number of matches.
+func (nm *NavigableMarkdown) Find(query string) int {
+	if query == "" {
+		nm.ClearFind()
+		return 0
+	}
+	nm.ClearFind()
+	nm.find.query = query
+	nm.refreshFind(true)
+	if len(nm.find.matches) == 0 {
+		if nm.onStateChange != nil {
+			nm.onStateChange()
+		}
+		return 0
+	}
+
+	offset := nm.viewer.Core().ScrollOffset()
+	nm.find.current = 0
+	for i, m := range nm.find.matches {
+		if m.line >= offset {
+			nm.find.current = i
+			break
+		}
+	}
+	nm.showCurrentMatch()
+	return len(nm.find.matches)
+}
+
+// NextMatch moves to the next match, wrapping around at the end of the document.
+func (nm *NavigableMarkdown) NextMatch() bool {
+	return nm.moveMatch(1)
+}
+
+// PreviousMatch moves to the previous match, wrapping around at the start of the document.
+func (nm *NavigableMarkdown) PreviousMatch() bool {
+	return nm.moveMatch(-1)
+}
+
+func (nm *NavigableMarkdown) moveMatch(step int) bool {
+	count := len(nm.find.matches)
+	if count == 0 {
+		return false
+	}
+	nm.find.current = (nm.find.current + step + count) % count
+	nm.showCurrentMatch()
+	return true
+}
+
+// ClearFind removes the highlights and forgets the query.
+func (nm *NavigableMarkdown) ClearFind() {
+	if nm.find.query != "" && nm.viewer.GetText(false) == nm.find.text {
+		nm.setViewerText(nm.find.base)
+	}
+	nm.find = findState{}
+	if nm.onStateChange != nil {
+		nm.onStateChange()
+	}
+}
+
+// FindStatus returns the active query, the 1-based index of the current match and the
+// number of matches. The query is empty while nothing is being searched for.
+func (nm *NavigableMarkdown) FindStatus() (query string, current, total int) {
+	if len(nm.find.matches) == 0 {
+		return nm.find.query, 0, 0
+	}
+	return nm.find.query, nm.find.current + 1, len(nm.find.matches)
+}
+
+// showCurrentMatch scrolls the current match into view, a third down the viewport,
+// and highlights it as current
+func (nm *NavigableMarkdown) showCurrentMatch() {
+	line := nm.find.matches[nm.find.current].line
+	_, _, _, height := nm.viewer.GetInnerRect()
+	offset := nm.viewer.Core().ScrollOffset()
+	if line < offset || line >= offset+height {
+		outline.ScrollToLine(nm.viewer, max(line-height/3, 0))
+	}
+	nm.refreshFind(true)
+	if nm.onStateChange != nil {
+		nm.onStateChange()
+	}
+}
+
+// refreshFind highlights the matches again when the viewer text was replaced since they
+// were last applied, or always when force is set (the current match moved)
+func (nm *NavigableMarkdown) refreshFind(force bool) {
+	if nm.find.query == "" {
+		return
+	}
+	text := nm.viewer.GetText(false)
+	if text != nm.find.text {
+		nm.find.base = text
+		nm.find.matches = findMatches(strings.Split(text, "\n"), nm.find.query)
+		if nm.find.current >= len(nm.find.matches) {
+			nm.find.current = 0
+		}
+	} else if !force {
+		return
+	}
+
+	nm.find.text = highlightMatches(strings.Split(nm.find.base, "\n"), nm.find.matches, nm.find.current)
+	nm.setViewerText(nm.find.text)
+}
+
+// setViewerText replaces the viewer text keeping its scroll position
+func (nm *NavigableMarkdown) setViewerText(text string) {
+	row, col := nm.viewer.GetScrollOffset()
+	nm.viewer.SetText(text)
+	nm.viewer.ScrollTo(row, col)
+}
+
+// findMatches returns the non-overlapping matches of query in the visible text of lines
+func findMatches(lines []string, query string) []findMatch {
+	needle := []rune(query)
+	fold := !hasUpper(query)
+	if fold {
+		needle = []rune(strings.ToLower(query))
+	}
+
+	var matches []findMatch
+	for i, line := range lines {
+		plain := visibleRunes(line)
+		for col := 0; col+len(needle) <= len(plain); {
+			if runesMatch(plain[col:col+len(needle)], needle, fold) {
+				matches = append(matches, findMatch{line: i, col: col, length: len(needle)})
+				col += len(needle)
+				continue
+			}
+			col++
+		}
+	}
+	return matches
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func runesMatch(text, needle []rune, fold bool) bool {
+	for i, r := range text {
+		if fold {
+			r = unicode.ToLower(r)
+		}
+		if r != needle[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// visibleRunes returns a tagged line without its style and region tags
+func visibleRunes(line string) []rune {
+	runes := []rune(line)
+	plain := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); {
+		if end := tagEnd(runes, i); end > i {
+			i = end + 1
+			continue
+		}
+		plain = append(plain, runes[i])
+		i++
+	}
+	return plain
+}
+
+// tagEnd returns the index of the ']' closing a tag that starts at i, or i if there is
+// no tag there. Tags are found the way navidown finds them when marking selected links.
+func tagEnd(runes []rune, i int) int {
+	if runes[i] != '[' {
+		return i
+	}
+	for j := i + 1; j < len(runes); j++ {
+		switch runes[j] {
+		case ']':
+			return j
+		case '[':
+			return i
+		}
+	}
+	return i
+}
+
+// highlightMatches wraps each match in highlight tags. Style tags inside a match are
+// followed by the highlight again, and the style in effect before the match is restored
+// after it.
+func highlightMatches(lines []string, matches []findMatch, current int) string {
+	byLine := make(map[int][]int)
+	for i, m := range matches {
+		byLine[m.line] = append(byLine[m.line], i)
+	}
+
+	for lineIdx, indexes := range byLine {
+		runes := []rune(lines[lineIdx])
+		var sb strings.Builder
+		style := findResetTag
+		col, next := 0, 0
+		active := ""   // highlight tag of the match being written
+		matchEnd := -1 // visible column where it ends
+
+		for i := 0; i < len(runes); {
+			if end := tagEnd(runes, i); end > i {
+				tag := string(runes[i : end+1])
+				sb.WriteString(tag)
+				if !strings.HasPrefix(tag, `["`) {
+					style = tag
+					if active != "" {
+						sb.WriteString(active)
+					}
+				}
+				i = end + 1
+				continue
+			}
+			if active != "" && col == matchEnd {
+				sb.WriteString(style)
+				active = ""
+			}
+			if active == "" && next < len(indexes) && col == matches[indexes[next]].col {
+				active = findMatchTag
+				if indexes[next] == current {
+					active = findCurrentTag
+				}
+				matchEnd = col + matches[indexes[next]].length
+				next++
+				sb.WriteString(active)
+			}
+			sb.WriteRune(runes[i])
+			i++
+			col++
+		}
+		if active != "" {
+			sb.WriteString(style)
+		}
+		lines[lineIdx] = sb.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package view
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindMatches(t *testing.T) {
+	lines := []string{
+		"[#ffffff:-:b]Task[-:-:-] states: [#00ff00:-:-]todo[-:-:-], doing, done",
+		"no hits here",
+		"TODO list",
+	}
+
+	matches := findMatches(lines, "todo")
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 case-insensitive matches, got %+v", matches)
+	}
+	if matches[0] != (findMatch{line: 0, col: 13, length: 4}) || matches[1].line != 2 {
+		t.Errorf("unexpected matches: %+v", matches)
+	}
+
+	if matches := findMatches(lines, "TODO"); len(matches) != 1 || matches[0].line != 2 {
+		t.Errorf("upper case query should match case: %+v", matches)
+	}
+	if matches := findMatches(lines, "do"); len(matches) != 4 {
+		t.Errorf("expected 4 non-overlapping matches of do, got %d", len(matches))
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	lines := []string{"[#00ff00:-:-]one two[-:-:-] one"}
+	matches := findMatches(lines, "one")
+
+	got := highlightMatches(append([]string(nil), lines...), matches, 1)
+	want := "[#00ff00:-:-]" + findMatchTag + "one[#00ff00:-:-] two[-:-:-] " + findCurrentTag + "one[-:-:-]"
+	if got != want {
+		t.Errorf("highlightMatches:\n got %q\nwant %q", got, want)
+	}
+	if plain := string(visibleRunes(got)); plain != "one two one" {
+		t.Errorf("highlights changed the visible text: %q", plain)
+	}
+
+	// a style change inside a match keeps the highlight
+	lines = []string{"a[#ff0000:-:-]bc"}
+	got = highlightMatches(lines, findMatches(lines, "ab"), 0)
+	if !strings.Contains(got, "[#ff0000:-:-]"+findCurrentTag+"b[#ff0000:-:-]") {
+		t.Errorf("highlight not continued after a style tag: %q", got)
+	}
+}
+
+func TestNavigableMarkdownFind(t *testing.T) {
+	nm := NewNavigableMarkdown(NavigableMarkdownConfig{})
+	var md strings.Builder
+	md.WriteString("# Notes\n\n")
+	for i := 0; i < 30; i++ {
+		md.WriteString("filler line\n\n")
+	}
+	md.WriteString("the needle is here\n\nand another needle\n")
+	nm.SetMarkdown(md.String())
+
+	if count := nm.Find("needle"); count != 2 {
+		t.Fatalf("expected 2 matches, got %d", count)
+	}
+	query, current, total := nm.FindStatus()
+	if query != "needle" || current != 1 || total != 2 {
+		t.Errorf("unexpected status %q %d/%d", query, current, total)
+	}
+	if nm.Viewer().Core().ScrollOffset() == 0 {
+		t.Error("expected the viewer to scroll to the first match")
+	}
+	if !strings.Contains(nm.Viewer().GetText(false), findCurrentTag) {
+		t.Error("expected the current match to be highlighted")
+	}
+
+	nm.NextMatch()
+	if _, current, _ = nm.FindStatus(); current != 2 {
+		t.Errorf("expected second match, got %d", current)
+	}
+	nm.NextMatch()
+	if _, current, _ = nm.FindStatus(); current != 1 {
+		t.Errorf("expected to wrap around to the first match, got %d", current)
+	}
+
+	// highlights survive navidown rewriting the text
+	nm.SetMarkdown(md.String() + "\none more needle\n")
+	if _, _, total = nm.FindStatus(); total != 3 {
+		t.Errorf("expected matches to follow new content, got %d", total)
+	}
+
+	nm.ClearFind()
+	if strings.Contains(nm.Viewer().GetText(false), findMatchTag) {
+		t.Error("expected highlights to be removed")
+	}
+}
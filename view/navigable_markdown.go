@@ -1,13 +1,18 @@
 package view
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/doki"
+	"github.com/boolean-maybe/tiki/util/diagram"
+	"github.com/boolean-maybe/tiki/view/outline"
 
 	nav "github.com/boolean-maybe/navidown/navidown"
 	navtview "github.com/boolean-maybe/navidown/navidown/tview"
 	navutil "github.com/boolean-maybe/navidown/util"
+	"github.com/rivo/tview"
 )
 
 // NavigableMarkdown wraps navidown TextViewViewer with link/anchor handling.
@@ -15,28 +20,35 @@ type NavigableMarkdown struct {
 	viewer        *navtview.TextViewViewer
 	provider      nav.ContentProvider
 	searchRoots   []string
+	resolveSource func(url, sourceFile string) string
 	onStateChange func()
+	onTaskLink    func(taskID string)
+	onMissingPage func(url, sourceFile string)
+	find          findState
 }
 
 // NavigableMarkdownConfig configures a NavigableMarkdown component.
 type NavigableMarkdownConfig struct {
 	Provider      nav.ContentProvider
 	SearchRoots   []string
-	OnStateChange func() // called on navigation state changes
+	ResolveSource func(url, sourceFile string) string // optional, for providers not reading the working tree
+	OnStateChange func()                              // called on navigation state changes
 }
 
 // NewNavigableMarkdown creates a new navigable markdown viewer.
 func NewNavigableMarkdown(cfg NavigableMarkdownConfig) *NavigableMarkdown {
 	nm := &NavigableMarkdown{
 		viewer:        navtview.NewTextView(),
-		provider:      cfg.Provider,
 		searchRoots:   cfg.SearchRoots,
+		resolveSource: cfg.ResolveSource,
 		onStateChange: cfg.OnStateChange,
 	}
+	nm.provider = taskRefProvider{ContentProvider: cfg.Provider, linkTasks: func() bool { return nm.onTaskLink != nil }}
 	nm.viewer.SetAnsiConverter(navutil.NewAnsiConverter(true))
 	nm.viewer.SetRenderer(nav.NewANSIRendererWithStyle(config.GetEffectiveTheme()))
 	nm.viewer.SetBackgroundColor(config.GetContentBackgroundColor())
 	nm.viewer.SetStateChangedHandler(func(_ *navtview.TextViewViewer) {
+		nm.refreshFind(false)
 		if nm.onStateChange != nil {
 			nm.onStateChange()
 		}
@@ -50,6 +62,13 @@ func (nm *NavigableMarkdown) setupSelectHandler() {
 		if elem.Type != nav.NavElementURL {
 			return
 		}
+		// Task reference (rendered from TIKI-XXXXXX by taskRefProvider)
+		if taskID, ok := doki.TaskReference(elem.URL); ok {
+			if nm.onTaskLink != nil {
+				nm.onTaskLink(taskID)
+			}
+			return
+		}
 		// Internal anchor (same file)
 		if elem.IsInternalLink() {
 			v.ScrollToAnchor(elem.AnchorTarget(), true)
@@ -63,13 +82,17 @@ func (nm *NavigableMarkdown) setupSelectHandler() {
 			Type:           elem.Type,
 		})
 		if err != nil {
+			if errors.Is(err, nav.ErrFileNotFound) && nm.onMissingPage != nil {
+				nm.onMissingPage(path, elem.SourceFilePath)
+				return
+			}
 			v.SetMarkdown(FormatErrorContent(err))
 			return
 		}
 		if content == "" {
 			return
 		}
-		v.SetMarkdownWithSource(content, nm.resolveSourcePath(path, elem.SourceFilePath), true)
+		nm.SetMarkdownWithSource(content, nm.resolveSourcePath(path, elem.SourceFilePath), true)
 		if fragment != "" {
 			v.ScrollToAnchor(fragment, false)
 		}
@@ -80,6 +103,9 @@ func (nm *NavigableMarkdown) resolveSourcePath(url, sourceFile string) string {
 	if sourceFile == "" {
 		return url
 	}
+	if nm.resolveSource != nil {
+		return nm.resolveSource(url, sourceFile)
+	}
 	resolved, err := nav.ResolveMarkdownPath(url, sourceFile, nm.searchRoots)
 	if err != nil || resolved == "" {
 		return url
@@ -108,13 +134,36 @@ func (nm *NavigableMarkdown) SourceFilePath() string {
 }
 
 // SetMarkdown sets markdown content without source context.
+// Mermaid and graphviz blocks are drawn as box art.
 func (nm *NavigableMarkdown) SetMarkdown(content string) {
-	nm.viewer.SetMarkdown(content)
+	nm.viewer.SetMarkdown(diagram.RenderMarkdown(content))
 }
 
 // SetMarkdownWithSource sets markdown content with source context.
+// Mermaid and graphviz blocks are drawn as box art.
 func (nm *NavigableMarkdown) SetMarkdownWithSource(content, source string, pushHistory bool) {
-	nm.viewer.SetMarkdownWithSource(content, source, pushHistory)
+	nm.viewer.SetMarkdownWithSource(diagram.RenderMarkdown(content), source, pushHistory)
+}
+
+// NextHeading scrolls to the next heading below the top of the viewport.
+func (nm *NavigableMarkdown) NextHeading() bool {
+	return outline.Next(nm.viewer)
+}
+
+// PreviousHeading scrolls to the heading above the top of the viewport.
+func (nm *NavigableMarkdown) PreviousHeading() bool {
+	return outline.Previous(nm.viewer)
+}
+
+// ScrollToHeading scrolls to the heading with the given slug (a #fragment without the #).
+func (nm *NavigableMarkdown) ScrollToHeading(slug string) bool {
+	return nm.viewer.ScrollToAnchor(slug, false)
+}
+
+// Outline returns a list of the document's headings; choosing one scrolls to it
+// and then calls done.
+func (nm *NavigableMarkdown) Outline(done func()) *tview.List {
+	return outline.NewList(nm.viewer, done)
 }
 
 // SetStateChangedHandler sets the callback for navigation state changes.
@@ -123,6 +172,40 @@ func (nm *NavigableMarkdown) SetStateChangedHandler(handler func()) {
 	nm.onStateChange = handler
 }
 
+// SetTaskLinkHandler sets the callback for following a link to a task.
+func (nm *NavigableMarkdown) SetTaskLinkHandler(handler func(taskID string)) {
+	nm.onTaskLink = handler
+}
+
+// SetMissingPageHandler sets the callback for following a link to a file that doesn't exist.
+// Without a handler such links show an error.
+func (nm *NavigableMarkdown) SetMissingPageHandler(handler func(url, sourceFile string)) {
+	nm.onMissingPage = handler
+}
+
+// taskRefProvider links the task references of fetched content when there is somewhere
+// to follow them: a viewer without a task link handler, such as the standalone markdown
+// viewer, shows TIKI-XXXXXX as written. Diagrams are drawn by the setters, once.
+type taskRefProvider struct {
+	nav.ContentProvider
+	linkTasks func() bool
+}
+
+// FetchContent fetches content from the wrapped provider.
+func (p taskRefProvider) FetchContent(elem nav.NavElement) (string, error) {
+	if p.ContentProvider == nil {
+		return "", nil
+	}
+	content, err := p.ContentProvider.FetchContent(elem)
+	if err != nil {
+		return content, err
+	}
+	if p.linkTasks != nil && p.linkTasks() {
+		content = doki.LinkTaskReferences(content)
+	}
+	return content, nil
+}
+
 func splitURLFragment(url string) (path, fragment string) {
 	path, fragment, _ = strings.Cut(url, "#")
 	return path, fragment
@@ -0,0 +1,123 @@
+// Package outline navigates the headings of a rendered markdown document: jumping to
+// the next or previous heading and listing them all as a table of contents.
+package outline
+
+import (
+	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
+
+	nav "github.com/boolean-maybe/navidown/navidown"
+	navtview "github.com/boolean-maybe/navidown/navidown/tview"
+	"github.com/rivo/tview"
+)
+
+// Headings returns the headings of the document on display, in document order.
+// Headings that couldn't be placed in the rendered output are left out.
+func Headings(v *navtview.TextViewViewer) []nav.NavElement {
+	var headings []nav.NavElement
+	for _, elem := range v.Core().Elements() {
+		if elem.Type == nav.NavElementHeader && elem.StartLine >= 0 {
+			headings = append(headings, elem)
+		}
+	}
+	return headings
+}
+
+// Current returns the index of the heading of the section at the top of the viewport,
+// or -1 above the first heading
+func Current(v *navtview.TextViewViewer) int {
+	offset := v.Core().ScrollOffset()
+	current := -1
+	for i, h := range Headings(v) {
+		if top(h) > offset {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// Next scrolls the next heading below the top of the viewport to the top.
+// It returns false when there is none or the document can't scroll further.
+func Next(v *navtview.TextViewViewer) bool {
+	offset := v.Core().ScrollOffset()
+	for _, h := range Headings(v) {
+		if top(h) > offset {
+			return ScrollToLine(v, top(h))
+		}
+	}
+	return false
+}
+
+// Previous scrolls the heading above the top of the viewport to the top
+func Previous(v *navtview.TextViewViewer) bool {
+	offset := v.Core().ScrollOffset()
+	headings := Headings(v)
+	for i := len(headings) - 1; i >= 0; i-- {
+		if top(headings[i]) < offset {
+			return ScrollToLine(v, top(headings[i]))
+		}
+	}
+	return false
+}
+
+// top is the line scrolled to for a heading: the line above it, which is blank or
+// holds the heading's own padding, stays in view
+func top(h nav.NavElement) int {
+	return max(h.StartLine-1, 0)
+}
+
+// ScrollToLine moves the viewport so line is at the top, or as close as the end of
+// the document allows. Scrolling goes through the session one line at a time so
+// selection and scroll state stay in sync with the text view.
+func ScrollToLine(v *navtview.TextViewViewer, line int) bool {
+	core := v.Core()
+	_, _, _, height := v.GetInnerRect()
+	start := core.ScrollOffset()
+	for core.ScrollOffset() < line {
+		if !core.ScrollDown(height) {
+			break
+		}
+	}
+	for core.ScrollOffset() > line {
+		if !core.ScrollUp(height) {
+			break
+		}
+	}
+	v.ScrollTo(core.ScrollOffset(), 0)
+	return core.ScrollOffset() != start
+}
+
+// NewList builds a table of contents for the document on display, indented by heading
+// level, with the current section selected. Choosing an entry scrolls to its heading,
+// then done is called so the caller can close the list.
+func NewList(v *navtview.TextViewViewer, done func()) *tview.List {
+	colors := config.GetColors()
+	list := tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	list.SetBorder(true).SetTitle(" Outline ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+
+	headings := Headings(v)
+	if len(headings) == 0 {
+		list.AddItem("(no headings)", "", 0, done)
+		return list
+	}
+
+	for _, h := range headings {
+		slug := h.Slug
+		label := strings.Repeat("  ", max(h.Level-1, 0)) + tview.Escape(h.Text)
+		list.AddItem(label, "", 0, func() {
+			// looked up when chosen: a resize since the list was built rewraps the lines
+			if header := v.Core().FindHeaderBySlug(slug); header != nil {
+				ScrollToLine(v, top(*header))
+			}
+			if done != nil {
+				done()
+			}
+		})
+	}
+	if current := Current(v); current >= 0 {
+		list.SetCurrentItem(current)
+	}
+	return list
+}
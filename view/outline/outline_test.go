@@ -0,0 +1,87 @@
+package outline
+
+import (
+	"strings"
+	"testing"
+
+	navtview "github.com/boolean-maybe/navidown/navidown/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func newViewer(t *testing.T) *navtview.TextViewViewer {
+	t.Helper()
+	var md strings.Builder
+	for _, h := range []string{"# Design", "## Goals", "## Data model", "### Tasks", "## Open questions"} {
+		md.WriteString(h + "\n\n")
+		for i := 0; i < 8; i++ {
+			md.WriteString("paragraph text\n\n")
+		}
+	}
+	v := navtview.NewTextView()
+	v.SetRect(0, 0, 80, 10)
+	v.SetMarkdown(md.String())
+	return v
+}
+
+func TestHeadings(t *testing.T) {
+	v := newViewer(t)
+	headings := Headings(v)
+	if len(headings) != 5 {
+		t.Fatalf("expected 5 headings, got %d", len(headings))
+	}
+	if headings[2].Slug != "data-model" || headings[3].Level != 3 {
+		t.Fatalf("unexpected headings: %+v", headings)
+	}
+	if Current(v) != 0 {
+		t.Fatalf("expected the first section at the top, got %d", Current(v))
+	}
+}
+
+func TestNextPrevious(t *testing.T) {
+	v := newViewer(t)
+	headings := Headings(v)
+
+	if !Next(v) {
+		t.Fatal("expected Next to scroll")
+	}
+	if got := v.Core().ScrollOffset(); got != headings[1].StartLine-1 {
+		t.Fatalf("expected offset %d, got %d", headings[1].StartLine-1, got)
+	}
+	Next(v)
+	if Current(v) != 2 {
+		t.Fatalf("expected data model section, got %d", Current(v))
+	}
+
+	if !Previous(v) {
+		t.Fatal("expected Previous to scroll")
+	}
+	if Current(v) != 1 {
+		t.Fatalf("expected goals section, got %d", Current(v))
+	}
+	Previous(v)
+	if Previous(v) {
+		t.Fatal("expected no heading above the first")
+	}
+}
+
+func TestNewListScrollsToHeading(t *testing.T) {
+	v := newViewer(t)
+	closed := false
+	list := NewList(v, func() { closed = true })
+	if list.GetItemCount() != 5 {
+		t.Fatalf("expected 5 entries, got %d", list.GetItemCount())
+	}
+	if main, _ := list.GetItemText(3); main != "    Tasks" {
+		t.Fatalf("expected indented entry, got %q", main)
+	}
+
+	list.SetCurrentItem(3)
+	list.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) {})
+	if !closed {
+		t.Fatal("expected done after choosing a heading")
+	}
+	if Current(v) != 3 {
+		t.Fatalf("expected tasks section, got %d", Current(v))
+	}
+}
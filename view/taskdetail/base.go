@@ -20,7 +20,7 @@ type Base struct {
 	taskStore store.Store
 	taskID    string
 	renderer  renderer.MarkdownRenderer
-	descView  *tview.TextView
+	descView  tview.Primitive
 
 	// Task data
 	fallbackTask   *taskpkg.Task
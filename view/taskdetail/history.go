@@ -0,0 +1,190 @@
+package taskdetail
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
+	"github.com/boolean-maybe/tiki/store"
+
+	"github.com/rivo/tview"
+)
+
+// history pane: timeline of committed revisions of the task file, newest first,
+// with the description as it was at the selected commit.
+
+const historyTimeFormat = "2006-01-02 15:04"
+
+// ToggleHistory shows or hides the history pane
+func (tv *TaskDetailView) ToggleHistory() {
+	if tv.historyVisible {
+		tv.HideHistory()
+		return
+	}
+	tv.historyVisible = true
+	tv.outlineVisible = false
+	tv.historySelected = 0
+	tv.loadHistory()
+}
+
+// HideHistory closes the history pane and restores the description
+func (tv *TaskDetailView) HideHistory() {
+	if !tv.historyVisible {
+		return
+	}
+	tv.historyVisible = false
+	tv.historyLoading = false
+	tv.historyGeneration++
+	tv.historySelected = 0
+	tv.revisions = nil
+	tv.historyErr = nil
+	tv.refresh()
+}
+
+// IsHistoryVisible reports whether the history pane is currently shown
+func (tv *TaskDetailView) IsHistoryVisible() bool {
+	return tv.historyVisible
+}
+
+// loadHistory reads the task timeline off the UI goroutine; the pane shows it is
+// loading until then
+func (tv *TaskDetailView) loadHistory() {
+	tv.historyGeneration++
+	generation := tv.historyGeneration
+	tv.historyLoading = true
+	tv.revisions, tv.historyErr = nil, nil
+	tv.refresh()
+
+	var revisions []store.TaskRevision
+	var err error
+	load := func() { revisions, err = tv.taskStore.GetTaskTimeline(tv.taskID) }
+	done := func() {
+		if generation != tv.historyGeneration {
+			return
+		}
+		tv.revisions, tv.historyErr = revisions, err
+		tv.historyLoading = false
+		tv.refresh()
+	}
+
+	if tv.queueUpdate == nil {
+		load()
+		done()
+		return
+	}
+	go func() {
+		load()
+		tv.queueUpdate(done)
+	}()
+}
+
+// buildHistory returns the history pane and the primitive that should receive focus
+func (tv *TaskDetailView) buildHistory(colors *config.ColorConfig) (tview.Primitive, tview.Primitive) {
+	if tv.historyLoading {
+		loading := tview.NewTextView().SetDynamicColors(true).SetText(colors.TaskDetailEditDimTextColor + "Loading history…")
+		loading.SetBorder(true).SetTitle(" History ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+		loading.SetBorderPadding(1, 1, 2, 2)
+		return loading, loading
+	}
+	if tv.historyErr != nil || len(tv.revisions) == 0 {
+		msg := "No committed history for this task yet"
+		if tv.historyErr != nil {
+			msg = fmt.Sprintf("History unavailable: %v", tv.historyErr)
+		}
+		empty := tview.NewTextView().SetDynamicColors(true).SetText(colors.TaskDetailEditDimTextColor + msg)
+		empty.SetBorder(true).SetTitle(" History ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+		empty.SetBorderPadding(1, 1, 2, 2)
+		return empty, empty
+	}
+
+	revisionView := tview.NewFlex().SetDirection(tview.FlexRow)
+	revisionView.SetBorder(true).SetBorderColor(colors.TaskBoxUnselectedBorder)
+	revisionView.SetBorderPadding(1, 1, 2, 2)
+
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true).SetTitle(" History ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+	list.SetHighlightFullLine(true)
+
+	// newest first
+	for i := len(tv.revisions) - 1; i >= 0; i-- {
+		rev := tv.revisions[i]
+		main := fmt.Sprintf("%s  %s  [gray]%s[-]", rev.When.Local().Format(historyTimeFormat), tview.Escape(rev.Author), shortCommit(rev.Commit))
+		list.AddItem(main, summarizeChanges(rev, i == 0), 0, nil)
+	}
+
+	showRevision := func(index int) {
+		revIndex := len(tv.revisions) - 1 - index
+		if revIndex < 0 || revIndex >= len(tv.revisions) {
+			return
+		}
+		tv.renderRevision(revisionView, tv.revisions[revIndex], colors)
+	}
+	// the pane is rebuilt on every store change: keep the revision the user selected
+	if tv.historySelected >= len(tv.revisions) {
+		tv.historySelected = len(tv.revisions) - 1
+	}
+	list.SetCurrentItem(tv.historySelected)
+	list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		tv.historySelected = index
+		showRevision(index)
+	})
+	showRevision(tv.historySelected)
+
+	pane := tview.NewFlex().SetDirection(tview.FlexColumn)
+	pane.AddItem(list, 0, 2, true)
+	pane.AddItem(revisionView, 0, 3, false)
+	return pane, list
+}
+
+// renderRevision shows the field changes of a single revision above its description, which
+// is rendered like the current one
+func (tv *TaskDetailView) renderRevision(pane *tview.Flex, rev store.TaskRevision, colors *config.ColorConfig) {
+	pane.Clear()
+	pane.SetTitle(fmt.Sprintf(" %s ", shortCommit(rev.Commit)))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s%s <%s>[-]\n", colors.TaskDetailLabelText, tview.Escape(rev.Author), tview.Escape(rev.Email))
+	fmt.Fprintf(&sb, "%s%s[-]\n", colors.TaskDetailLabelText, rev.When.Local().Format(historyTimeFormat))
+	if rev.Unreadable != "" {
+		fmt.Fprintf(&sb, "\n%sThis revision of the task file can't be read: %s[-]\n", colors.TaskDetailEditDimTextColor, tview.Escape(rev.Unreadable))
+	}
+	if len(rev.Changes) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, change := range rev.Changes {
+		fmt.Fprintf(&sb, "%s%-9s%s%s → %s[-]\n", colors.TaskDetailLabelText, change.Field+":", colors.TaskDetailValueText,
+			tview.Escape(defaultString(change.From, "(none)")), tview.Escape(defaultString(change.To, "(none)")))
+	}
+	header := strings.TrimSuffix(sb.String(), "\n")
+
+	pane.AddItem(tview.NewTextView().SetDynamicColors(true).SetText(header), strings.Count(header, "\n")+2, 0, false)
+	if rev.Unreadable == "" {
+		pane.AddItem(tv.newDescriptionView(rev.Description), 0, 1, false)
+	}
+}
+
+// summarizeChanges builds the one-line change summary shown under each revision
+func summarizeChanges(rev store.TaskRevision, first bool) string {
+	if rev.Unreadable != "" {
+		return "  unreadable"
+	}
+	if first {
+		return "  created"
+	}
+	parts := make([]string, 0, len(rev.Changes)+1)
+	for _, change := range rev.Changes {
+		parts = append(parts, fmt.Sprintf("%s: %s → %s", change.Field, defaultString(change.From, "(none)"), defaultString(change.To, "(none)")))
+	}
+	if rev.DescriptionChanged {
+		parts = append(parts, "description edited")
+	}
+	return "  " + tview.Escape(strings.Join(parts, "; "))
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,84 @@
+package taskdetail
+
+import (
+	"github.com/boolean-maybe/tiki/view/outline"
+
+	navtview "github.com/boolean-maybe/navidown/navidown/tview"
+)
+
+// outline pane: the headings of the description listed to its left. Choosing one
+// scrolls the description to it; ] and [ move between headings without the pane.
+
+const outlineWidth = 32
+
+// ToggleOutline shows or hides the outline pane
+func (tv *TaskDetailView) ToggleOutline() {
+	if tv.outlineVisible {
+		tv.HideOutline()
+		return
+	}
+	if tv.descViewer() == nil {
+		return
+	}
+	tv.outlineVisible = true
+	tv.layoutDescription()
+	if tv.focusSetter != nil {
+		tv.focusSetter(tv.outline)
+	}
+}
+
+// HideOutline closes the outline pane and returns focus to the description
+func (tv *TaskDetailView) HideOutline() {
+	if !tv.outlineVisible {
+		return
+	}
+	tv.outlineVisible = false
+	tv.layoutDescription()
+	if tv.focusSetter != nil {
+		tv.focusSetter(tv.descView)
+	}
+}
+
+// IsOutlineVisible reports whether the outline pane is currently shown
+func (tv *TaskDetailView) IsOutlineVisible() bool {
+	return tv.outlineVisible
+}
+
+// NextHeading scrolls the description to the next heading
+func (tv *TaskDetailView) NextHeading() {
+	if viewer := tv.descViewer(); viewer != nil {
+		outline.Next(viewer)
+	}
+}
+
+// PreviousHeading scrolls the description to the previous heading
+func (tv *TaskDetailView) PreviousHeading() {
+	if viewer := tv.descViewer(); viewer != nil {
+		outline.Previous(viewer)
+	}
+}
+
+// layoutDescription fills the description area, with the outline pane when it is visible.
+// The description itself is kept, so its scroll position survives toggling the pane.
+func (tv *TaskDetailView) layoutDescription() {
+	if tv.descArea == nil {
+		return
+	}
+	tv.descArea.Clear()
+	tv.outline = nil
+	viewer := tv.descViewer()
+	if viewer == nil {
+		return
+	}
+	if tv.outlineVisible {
+		tv.outline = outline.NewList(viewer, tv.HideOutline)
+		tv.descArea.AddItem(tv.outline, outlineWidth, 0, true)
+	}
+	tv.descArea.AddItem(viewer, 0, 1, !tv.outlineVisible)
+}
+
+// descViewer returns the description viewer, or nil while the history pane replaces it
+func (tv *TaskDetailView) descViewer() *navtview.TextViewViewer {
+	viewer, _ := tv.descView.(*navtview.TextViewViewer)
+	return viewer
+}
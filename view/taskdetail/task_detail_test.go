@@ -8,6 +8,8 @@ import (
 	"github.com/boolean-maybe/tiki/store"
 	"github.com/boolean-maybe/tiki/task"
 	"github.com/boolean-maybe/tiki/view/renderer"
+
+	"github.com/rivo/tview"
 )
 
 // TestBuildMetadataColumns_Structure verifies that buildMetadataColumns returns 2 flex containers
@@ -137,3 +139,83 @@ func TestBuildMetadataColumns_Column3Fields(t *testing.T) {
 		t.Errorf("Expected col3 to have 3 items, got %d", col3.GetItemCount())
 	}
 }
+
+// timelineStore serves a fixed timeline and counts the reads of it
+type timelineStore struct {
+	*store.InMemoryStore
+	revisions []store.TaskRevision
+	reads     int
+}
+
+func (s *timelineStore) GetTaskTimeline(string) ([]store.TaskRevision, error) {
+	s.reads++
+	return s.revisions, nil
+}
+
+func TestHistoryLoadsOncePerOpenAndKeepsSelection(t *testing.T) {
+	s := &timelineStore{InMemoryStore: store.NewInMemoryStore()}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, commit := range []string{"aaaaaaa1", "bbbbbbb2", "ccccccc3"} {
+		s.revisions = append(s.revisions, store.TaskRevision{Commit: commit, Author: "ann", When: start.Add(time.Duration(i) * time.Hour)})
+	}
+	if err := s.CreateTask(&task.Task{ID: "TIKI-1", Title: "Tracked", Status: task.StatusReady, Type: task.TypeStory}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := renderer.NewGlamourRenderer()
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	view := NewTaskDetailView(s, "TIKI-1", r)
+	queued := make(chan func(), 4)
+	view.SetUpdateQueue(func(f func()) { queued <- f })
+	view.OnFocus()
+	defer view.OnBlur()
+
+	view.ToggleHistory()
+	if !view.historyLoading {
+		t.Fatal("history should show it is loading until the timeline is read")
+	}
+	select {
+	case f := <-queued:
+		f()
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeline never handed to the UI goroutine")
+	}
+	if view.historyLoading || len(view.revisions) != 3 {
+		t.Fatalf("loading = %v, %d revisions after the load", view.historyLoading, len(view.revisions))
+	}
+
+	_, focus := view.buildHistory(config.GetColors())
+	list, ok := focus.(*tview.List)
+	if !ok {
+		t.Fatalf("history focus is %T, want the revision list", focus)
+	}
+	list.SetCurrentItem(2)
+
+	updated := s.GetTask("TIKI-1").Clone()
+	updated.Title = "Changed elsewhere"
+	if err := s.UpdateTask(updated); err != nil {
+		t.Fatal(err)
+	}
+
+	_, focus = view.buildHistory(config.GetColors())
+	if got := focus.(*tview.List).GetCurrentItem(); got != 2 {
+		t.Errorf("selected revision = %d after a store change, want 2", got)
+	}
+	if s.reads != 1 {
+		t.Errorf("timeline read %d times, want once per open", s.reads)
+	}
+
+	view.HideHistory()
+	view.ToggleHistory()
+	select {
+	case f := <-queued:
+		f()
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeline never handed to the UI goroutine")
+	}
+	if s.reads != 2 || view.historySelected != 0 {
+		t.Errorf("reopened: %d reads, selection %d; want a fresh load from the newest revision", s.reads, view.historySelected)
+	}
+}
@@ -2,15 +2,23 @@ package taskdetail
 
 import (
 	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
 
 	"github.com/boolean-maybe/tiki/config"
 	"github.com/boolean-maybe/tiki/controller"
+	"github.com/boolean-maybe/tiki/doki"
 	"github.com/boolean-maybe/tiki/model"
 	"github.com/boolean-maybe/tiki/store"
 	taskpkg "github.com/boolean-maybe/tiki/task"
+	"github.com/boolean-maybe/tiki/util/diagram"
 	"github.com/boolean-maybe/tiki/util/gradient"
 	"github.com/boolean-maybe/tiki/view/renderer"
 
+	nav "github.com/boolean-maybe/navidown/navidown"
+	navtview "github.com/boolean-maybe/navidown/navidown/tview"
+	navutil "github.com/boolean-maybe/navidown/util"
 	"github.com/rivo/tview"
 )
 
@@ -23,6 +31,26 @@ type TaskDetailView struct {
 
 	// View-mode specific
 	storeListenerID int
+
+	// History pane: loaded in the background once per open
+	historyVisible    bool
+	historyLoading    bool
+	historyGeneration int // drops the result of a load the pane was closed or reopened during
+	historySelected   int // list index of the selected revision, kept across refreshes
+	revisions         []store.TaskRevision
+	historyErr        error
+
+	// runs a function on the UI goroutine; nil loads in place
+	queueUpdate func(func())
+
+	// Cross-reference links in the description
+	onTaskLink func(taskID string)
+	onDokiLink func(path, anchor string)
+
+	// Outline of the description's headings, shown beside it
+	outlineVisible bool
+	descArea       *tview.Flex
+	outline        *tview.List
 }
 
 // NewTaskDetailView creates a task detail view in read-only mode
@@ -89,12 +117,27 @@ func (tv *TaskDetailView) refresh() {
 		tv.content.AddItem(metadataBox, 9, 0, false)
 	}
 
+	if tv.historyVisible {
+		historyPane, focusTarget := tv.buildHistory(colors)
+		tv.content.AddItem(historyPane, 0, 1, true)
+		if tv.focusSetter != nil {
+			tv.focusSetter(focusTarget)
+		}
+		return
+	}
+
 	descPrimitive := tv.buildDescription(task)
-	tv.content.AddItem(descPrimitive, 0, 1, true)
+	tv.descArea = tview.NewFlex()
+	tv.layoutDescription()
+	tv.content.AddItem(tv.descArea, 0, 1, true)
 
-	// Ensure focus is restored to description after refresh
+	// Ensure focus is restored to description (or its outline) after refresh
 	if tv.focusSetter != nil {
-		tv.focusSetter(descPrimitive)
+		if tv.outline != nil {
+			tv.focusSetter(tv.outline)
+		} else {
+			tv.focusSetter(descPrimitive)
+		}
 	}
 }
 
@@ -162,21 +205,78 @@ func (tv *TaskDetailView) buildMetadataColumns(task *taskpkg.Task, ctx FieldRend
 }
 
 func (tv *TaskDetailView) buildDescription(task *taskpkg.Task) tview.Primitive {
-	desc := defaultString(task.Description, "(No description)")
+	descBox := tv.newDescriptionView(task.Description)
+	descBox.SetBorderPadding(1, 1, 2, 2)
+	tv.descView = descBox
+	return descBox
+}
 
-	renderedDesc, err := tv.renderer.Render(desc)
-	if err != nil {
-		renderedDesc = desc
+// newDescriptionView renders a description of the task as markdown with followable links,
+// the same for the current task and its past revisions
+func (tv *TaskDetailView) newDescriptionView(description string) *navtview.TextViewViewer {
+	desc := defaultString(description, "(No description)")
+
+	descBox := navtview.NewTextView()
+	descBox.SetAnsiConverter(navutil.NewAnsiConverter(true))
+	descBox.SetRenderer(nav.NewANSIRendererWithStyle(config.GetEffectiveTheme()))
+	descBox.SetSelectHandler(func(v *navtview.TextViewViewer, elem nav.NavElement) {
+		tv.followLink(v, elem)
+	})
+	// the task file is the source so relative links resolve as they do on disk
+	descBox.SetMarkdownWithSource(diagram.RenderMarkdown(doki.LinkTaskReferences(desc)), tv.taskFilePath(tv.taskID), false)
+	descBox.SetScrollable(true)
+	return descBox
+}
+
+// followLink opens the task or doki page a description link points at
+func (tv *TaskDetailView) followLink(v *navtview.TextViewViewer, elem nav.NavElement) {
+	if elem.Type != nav.NavElementURL {
+		return
+	}
+	if taskID, ok := doki.TaskReference(elem.URL); ok {
+		if tv.onTaskLink != nil {
+			tv.onTaskLink(taskID)
+		}
+		return
+	}
+	if elem.IsInternalLink() {
+		v.ScrollToAnchor(elem.AnchorTarget(), true)
+		return
 	}
 
-	descBox := tview.NewTextView().
-		SetDynamicColors(true).
-		SetText(renderedDesc).
-		SetScrollable(true)
+	resolved := doki.ResolveLink(elem.URL, elem.SourceFilePath, []string{config.GetDokiDir()})
+	switch {
+	case resolved.External:
+		return
+	case resolved.Err != nil:
+		slog.Warn("failed to resolve description link", "task", tv.taskID, "url", elem.URL, "error", resolved.Err)
+	case tv.onDokiLink != nil:
+		tv.onDokiLink(resolved.Path, resolved.Fragment)
+	}
+}
 
-	descBox.SetBorderPadding(1, 1, 2, 2)
-	tv.descView = descBox
-	return descBox
+// SetTaskLinkHandler sets the callback for following a task reference
+func (tv *TaskDetailView) SetTaskLinkHandler(handler func(taskID string)) {
+	tv.onTaskLink = handler
+}
+
+// SetUpdateQueue sets how history read in the background is handed to the UI goroutine
+func (tv *TaskDetailView) SetUpdateQueue(queueUpdate func(func())) {
+	tv.queueUpdate = queueUpdate
+}
+
+// SetDokiLinkHandler sets the callback for following a link to a markdown file
+func (tv *TaskDetailView) SetDokiLinkHandler(handler func(path, anchor string)) {
+	tv.onDokiLink = handler
+}
+
+// taskFilePath returns the markdown file of a task: TIKI-ABC123 -> tiki-abc123.md, in the
+// directory the store loaded it from when the store spans several
+func (tv *TaskDetailView) taskFilePath(taskID string) string {
+	if locator, ok := tv.taskStore.(interface{ TaskFilePath(id string) string }); ok {
+		return locator.TaskFilePath(taskID)
+	}
+	return filepath.Join(config.GetTaskDir(), strings.ToLower(taskID)+".md")
 }
 
 // EnterFullscreen switches the view to fullscreen mode (description only)
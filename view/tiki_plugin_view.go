@@ -23,6 +23,7 @@ type PluginView struct {
 	searchHelper        *SearchHelper
 	lanes               *tview.Flex
 	laneBoxes           []*ScrollableList
+	templates           tview.Primitive // template picker or prompt form, shown in place of the lanes
 	taskStore           store.Store
 	pluginConfig        *model.PluginConfig
 	pluginDef           *plugin.TikiPlugin
@@ -90,6 +91,11 @@ func (pv *PluginView) rebuildLayout() {
 	pv.root.Clear()
 	pv.root.AddItem(pv.titleBar, 1, 0, false)
 
+	if pv.templates != nil {
+		pv.root.AddItem(pv.templates, 0, 1, true)
+		return
+	}
+
 	// Restore search box if search is active (e.g., returning from task details)
 	if pv.pluginConfig.IsSearchActive() {
 		query := pv.pluginConfig.GetSearchQuery()
@@ -0,0 +1,74 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/boolean-maybe/tiki/config"
+
+	"github.com/rivo/tview"
+)
+
+// template picker: the named task templates, listed in place of the lanes.
+// Choosing one starts a new tiki from it, after a form for the fields the template
+// prompts for, if any.
+
+// ShowTemplatePicker lists the template names and returns the list to focus. Without
+// templates it says where to add them; Esc closes it.
+func (pv *PluginView) ShowTemplatePicker(names []string, choose func(name string)) tview.Primitive {
+	colors := config.GetColors()
+	list := tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	list.SetBorder(true).SetTitle(" New from template ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+	if len(names) == 0 {
+		list.AddItem(tview.Escape("No templates yet: add .md files to "+strings.Join(config.GetTemplateDirs(), " or ")), "", 0, nil)
+	}
+	for _, name := range names {
+		list.AddItem(tview.Escape(name), "", 0, func() {
+			choose(name)
+		})
+	}
+
+	pv.templates = list
+	pv.rebuildLayout()
+	return list
+}
+
+// ShowTemplatePrompts shows a form with an input per prompt field and returns it to focus
+func (pv *PluginView) ShowTemplatePrompts(template string, fields []string, submit func(values map[string]string)) tview.Primitive {
+	colors := config.GetColors()
+	title := "new tiki"
+	if template != "" {
+		title = template
+	}
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(" " + tview.Escape(title) + " ").SetBorderColor(colors.TaskBoxUnselectedBorder)
+	for _, field := range fields {
+		form.AddInputField(field, "", 40, nil, nil)
+	}
+	form.AddButton("Create", func() {
+		values := make(map[string]string, len(fields))
+		for i, field := range fields {
+			if input, ok := form.GetFormItem(i).(*tview.InputField); ok {
+				values[field] = input.GetText()
+			}
+		}
+		submit(values)
+	})
+
+	pv.templates = form
+	pv.rebuildLayout()
+	return form
+}
+
+// HideTemplatePicker closes the list of templates or the prompt form and returns to the lanes
+func (pv *PluginView) HideTemplatePicker() {
+	if pv.templates == nil {
+		return
+	}
+	pv.templates = nil
+	pv.rebuildLayout()
+}
+
+// IsTemplatePickerVisible reports whether the list of templates or the prompt form is shown
+func (pv *PluginView) IsTemplatePickerVisible() bool {
+	return pv.templates != nil
+}